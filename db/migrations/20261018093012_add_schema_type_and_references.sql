@@ -0,0 +1,5 @@
+-- 2026-10-18 09:30:12 : add schema type and references
+
+ALTER TABLE schema_process
+    ADD COLUMN schema_type VARCHAR(32) NOT NULL DEFAULT 'JSON',
+    ADD COLUMN schema_references JSONB NULL;
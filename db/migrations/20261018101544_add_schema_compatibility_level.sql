@@ -0,0 +1,13 @@
+-- 2026-10-18 10:15:44 : add schema compatibility level
+
+ALTER TABLE schema_process
+    ADD COLUMN compatibility_level VARCHAR(32) NOT NULL DEFAULT '';
+
+CREATE TABLE capability_schema_settings
+(
+    capability_id       VARCHAR(255) NOT NULL,
+    compatibility_level VARCHAR(32)  NOT NULL,
+    updated_at          TIMESTAMP    NOT NULL,
+
+    CONSTRAINT capability_schema_settings_pk PRIMARY KEY (capability_id)
+);
@@ -0,0 +1,7 @@
+-- 2026-10-18 14:32:07 : add subject name strategy
+
+ALTER TABLE schema_process
+    ADD COLUMN subject_name_strategy VARCHAR(64) NOT NULL DEFAULT 'TopicMessageTypeNameStrategy';
+
+ALTER TABLE cluster
+    ADD COLUMN subject_name_strategy VARCHAR(64) NOT NULL DEFAULT '';
@@ -0,0 +1,5 @@
+-- 2026-10-18 16:05:21 : add schema deletion
+
+ALTER TABLE schema_process
+    ADD COLUMN deleted_at          TIMESTAMP NULL,
+    ADD COLUMN permanently_deleted BOOLEAN   NOT NULL DEFAULT FALSE;
@@ -0,0 +1,5 @@
+-- 2026-10-18 17:28:46 : add schema metadata and rule set
+
+ALTER TABLE schema_process
+    ADD COLUMN schema_metadata JSONB NULL,
+    ADD COLUMN schema_rule_set JSONB NULL;
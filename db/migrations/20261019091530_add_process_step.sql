@@ -0,0 +1,18 @@
+-- 2026-10-19 09:15:30 : add process step
+
+CREATE TABLE process_step
+(
+    id             UUID         NOT NULL,
+    process        VARCHAR(64)  NOT NULL,
+    process_id     VARCHAR(255) NOT NULL,
+    name           VARCHAR(255) NOT NULL,
+    attempts       INTEGER      NOT NULL DEFAULT 0,
+    last_error     TEXT         NOT NULL DEFAULT '',
+    started_at     TIMESTAMP    NOT NULL,
+    updated_at     TIMESTAMP    NOT NULL,
+    completed_at   TIMESTAMP    NULL,
+    compensated_at TIMESTAMP    NULL,
+
+    CONSTRAINT process_step_pk PRIMARY KEY (id),
+    CONSTRAINT process_step_name_uq UNIQUE (process, process_id, name)
+);
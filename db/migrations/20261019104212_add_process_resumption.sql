@@ -0,0 +1,13 @@
+-- 2026-10-19 10:42:12 : add process resumption
+
+ALTER TABLE create_process
+    ADD COLUMN resume_attempts INTEGER   NOT NULL DEFAULT 0,
+    ADD COLUMN failed_at       TIMESTAMP NULL;
+
+ALTER TABLE delete_process
+    ADD COLUMN resume_attempts INTEGER   NOT NULL DEFAULT 0,
+    ADD COLUMN failed_at       TIMESTAMP NULL;
+
+ALTER TABLE schema_process
+    ADD COLUMN resume_attempts INTEGER   NOT NULL DEFAULT 0,
+    ADD COLUMN failed_at       TIMESTAMP NULL;
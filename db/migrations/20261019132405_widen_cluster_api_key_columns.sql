@@ -0,0 +1,7 @@
+-- 2026-10-19 13:24:05 : widen cluster api key columns to hold encrypted api keys
+
+ALTER TABLE cluster
+    ALTER COLUMN admin_api_key_username           TYPE TEXT,
+    ALTER COLUMN admin_api_key_password           TYPE TEXT,
+    ALTER COLUMN schema_registry_api_key_username TYPE TEXT,
+    ALTER COLUMN schema_registry_api_key_password TYPE TEXT;
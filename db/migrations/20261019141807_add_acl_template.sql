@@ -0,0 +1,13 @@
+-- 2026-10-19 14:18:07 : add acl templates
+
+CREATE TABLE acl_template
+(
+    cluster_id VARCHAR(255) NOT NULL REFERENCES cluster (id) ON DELETE CASCADE,
+    version    INTEGER      NOT NULL,
+    entries    JSONB        NOT NULL,
+    created_at TIMESTAMP    NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (cluster_id, version)
+);
+
+ALTER TABLE cluster_access
+    ADD COLUMN acl_template_version INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,9 @@
+-- 2026-10-19 15:30:12 : add access grants to acl entries
+
+ALTER TABLE acl
+    ADD COLUMN grant_id       UUID         NULL,
+    ADD COLUMN granted_by     VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN owner_approved BOOLEAN      NOT NULL DEFAULT FALSE;
+
+CREATE INDEX acl_grant_id_idx ON acl (grant_id);
+CREATE INDEX acl_granted_by_idx ON acl (granted_by);
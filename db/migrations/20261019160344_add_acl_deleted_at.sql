@@ -0,0 +1,4 @@
+-- 2026-10-19 16:03:44 : keep deleted acl entries
+
+ALTER TABLE acl
+    ADD COLUMN deleted_at TIMESTAMP NULL;
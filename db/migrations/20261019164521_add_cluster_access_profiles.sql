@@ -0,0 +1,4 @@
+-- 2026-10-19 16:45:21 : add access profiles to cluster accesses
+
+ALTER TABLE cluster_access
+    ADD COLUMN profiles JSONB NOT NULL DEFAULT '[]';
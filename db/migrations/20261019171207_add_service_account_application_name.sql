@@ -0,0 +1,6 @@
+-- 2026-10-19 17:12:07 : add application name to service account
+
+ALTER TABLE service_account
+    ADD COLUMN application_name VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX service_account_capability_application_idx ON service_account (capability_id, application_name);
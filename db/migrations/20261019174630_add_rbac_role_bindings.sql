@@ -0,0 +1,18 @@
+-- 2026-10-19 17:46:30 : add rbac access mode and role bindings
+
+ALTER TABLE cluster
+    ADD COLUMN access_mode VARCHAR(255) NOT NULL DEFAULT 'acl';
+
+CREATE TABLE role_binding
+(
+    id                UUID          NOT NULL,
+    cluster_access_id UUID          NOT NULL,
+    role_name         VARCHAR(255)  NOT NULL,
+    crn_pattern       VARCHAR(1024) NOT NULL,
+    confluent_id      VARCHAR(255)  NOT NULL DEFAULT '',
+    created_at        TIMESTAMP     NULL,
+    deleted_at        TIMESTAMP     NULL,
+
+    CONSTRAINT role_binding_pk PRIMARY KEY (id),
+    CONSTRAINT fk_cluster_access FOREIGN KEY (cluster_access_id) REFERENCES cluster_access (id)
+);
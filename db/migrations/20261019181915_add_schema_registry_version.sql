@@ -0,0 +1,4 @@
+-- 2026-10-19 18:19:15 : add the version the schema registry assigned to a schema
+
+ALTER TABLE schema_process
+    ADD COLUMN registry_version int NOT NULL DEFAULT 0;
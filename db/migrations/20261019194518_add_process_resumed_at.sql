@@ -0,0 +1,10 @@
+-- 2026-10-19 19:45:18 : add the time a process was last resumed
+
+ALTER TABLE create_process
+    ADD COLUMN resumed_at TIMESTAMP NULL;
+
+ALTER TABLE delete_process
+    ADD COLUMN resumed_at TIMESTAMP NULL;
+
+ALTER TABLE schema_process
+    ADD COLUMN resumed_at TIMESTAMP NULL;
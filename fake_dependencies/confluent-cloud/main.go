@@ -2,6 +2,9 @@ package main
 
 import (
 	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -74,6 +77,27 @@ func main() {
 
 	})
 
+	r.GET("/kafka/v3/clusters/:cluster_id/topics/:topic_name", func(c *gin.Context) {
+		c.JSON(200, gin.H{
+			"kind":               "KafkaTopic",
+			"cluster_id":         c.Param("cluster_id"),
+			"topic_name":         c.Param("topic_name"),
+			"is_internal":        false,
+			"replication_factor": 3,
+			"partitions_count":   1,
+		})
+	})
+
+	r.GET("/kafka/v3/clusters/:cluster_id/topics/:topic_name/configs", func(c *gin.Context) {
+		c.JSON(200, gin.H{
+			"kind": "KafkaTopicConfigList",
+			"data": []gin.H{
+				{"name": "retention.ms", "value": "604800000"},
+				{"name": "cleanup.policy", "value": "delete"},
+			},
+		})
+	})
+
 	r.POST("/kafka/v3/clusters/:cluster_id/topics", func(c *gin.Context) {
 		c.Status(204)
 	})
@@ -86,6 +110,10 @@ func main() {
 		c.Status(204)
 	})
 
+	r.DELETE("/subjects/:subject", func(c *gin.Context) {
+		c.JSON(200, []int{1})
+	})
+
 	r.POST("/iam/v2/service-accounts", func(c *gin.Context) {
 		c.JSON(200, gin.H{
 			//"id": fmt.Sprintf("sa-%s", time.Now().Format("150405")),
@@ -93,8 +121,43 @@ func main() {
 		})
 	})
 
+	acls := &fakeAcls{}
+
 	r.POST("/kafka/v3/clusters/:cluster_id/acls", func(c *gin.Context) {
-		c.Status(204)
+		var acl fakeAcl
+		c.BindJSON(&acl)
+		acls.add(c.Param("cluster_id"), acl)
+		c.Status(201)
+	})
+
+	// gin cannot route a literal colon, so the batch endpoint is matched by its last path segment
+	r.POST("/kafka/v3/clusters/:cluster_id/:action", func(c *gin.Context) {
+		if c.Param("action") != "acls:batch" {
+			c.Status(404)
+			return
+		}
+
+		var batch struct {
+			Data []fakeAcl `json:"data"`
+		}
+		c.BindJSON(&batch)
+		for _, acl := range batch.Data {
+			acls.add(c.Param("cluster_id"), acl)
+		}
+		c.Status(201)
+	})
+
+	r.GET("/kafka/v3/clusters/:cluster_id/acls", func(c *gin.Context) {
+		c.JSON(200, gin.H{
+			"kind": "KafkaAclList",
+			"data": acls.find(c.Param("cluster_id"), c.Request.URL.Query(), false),
+		})
+	})
+
+	r.DELETE("/kafka/v3/clusters/:cluster_id/acls", func(c *gin.Context) {
+		c.JSON(200, gin.H{
+			"data": acls.find(c.Param("cluster_id"), c.Request.URL.Query(), true),
+		})
 	})
 
 	r.POST("/iam/v2/api-keys", func(c *gin.Context) {
@@ -109,12 +172,30 @@ func main() {
 		})
 	})
 
+	roleBindings := &fakeRoleBindings{}
+
 	r.POST("/iam/v2/role-bindings", func(c *gin.Context) {
+		var roleBinding fakeRoleBinding
+		c.BindJSON(&roleBinding)
+		c.JSON(201, roleBindings.add(roleBinding))
+	})
+
+	r.GET("/iam/v2/role-bindings", func(c *gin.Context) {
 		c.JSON(200, gin.H{
-			"id": "fake-role-binding-id",
+			"api_version": "iam/v2",
+			"kind":        "RoleBindingList",
+			"data":        roleBindings.find(c.Query("principal"), c.Query("crn_pattern")),
 		})
 	})
 
+	r.DELETE("/iam/v2/role-bindings/:id", func(c *gin.Context) {
+		if !roleBindings.remove(c.Param("id")) {
+			c.Status(404)
+			return
+		}
+		c.Status(200)
+	})
+
 	r.GET("/iam/v2/api-keys", func(c *gin.Context) {
 		c.JSON(200, gin.H{
 			"api_version": "iam/v2",
@@ -231,28 +312,195 @@ func main() {
 
 	})
 
-	r.POST("/aws-ssm-put", func(c *gin.Context) {
+	r.GET("/subjects", func(c *gin.Context) {
+		subjectPrefix := c.Query("subjectPrefix")
+		if subjectPrefix == "" {
+			subjectPrefix = "cloudengineering"
+		}
+
+		c.JSON(200, []string{subjectPrefix + ".selfservice.test-EnvelopeOfAdmin"})
+	})
 
-		c.Data(200, "application/x-amz-json-1.1", []byte(`
-			{
-			   "Tier": "Standard",
-			   "Version": 1
-			}
-		`))
+	r.GET("/subjects/:subject/versions", func(c *gin.Context) {
+		c.JSON(200, []int{1, 2})
 	})
 
-	// [THFIS] trailing slash (!!) because of AWS SDK
-	r.POST("/aws-ssm-put/", func(c *gin.Context) {
+	r.GET("/subjects/:subject/versions/:version", func(c *gin.Context) {
+		version := 2
+		if v, err := strconv.Atoi(c.Param("version")); err == nil {
+			version = v
+		}
 
-		c.Data(200, "application/x-amz-json-1.1", []byte(`
-			{
-			   "Tier": "Standard",
-			   "Version": 1
-			}
-		`))
+		c.JSON(200, gin.H{
+			"subject":    c.Param("subject"),
+			"version":    version,
+			"id":         100020,
+			"schemaType": "JSON",
+			"schema":     `{"type":"object"}`,
+		})
 	})
 
+	r.POST("/compatibility/subjects/:subject/versions/latest", func(c *gin.Context) {
+		c.JSON(200, gin.H{"is_compatible": true, "messages": []string{}})
+	})
+
+	r.GET("/config/:subject", func(c *gin.Context) {
+		c.JSON(200, gin.H{"compatibilityLevel": "BACKWARD"})
+	})
+
+	r.PUT("/config/:subject", func(c *gin.Context) {
+		var body struct {
+			Compatibility string `json:"compatibility"`
+		}
+		c.BindJSON(&body)
+		c.JSON(200, gin.H{"compatibility": body.Compatibility})
+	})
+
+	r.POST("/aws-ssm-put", fakeSsm)
+
+	// [THFIS] trailing slash (!!) because of AWS SDK
+	r.POST("/aws-ssm-put/", fakeSsm)
+
 	fmt.Println("Fake Confluent Cloud!")
 	r.Run() // listen and serve on 0.0.0.0:8080
 
 }
+
+func fakeSsm(c *gin.Context) {
+	if c.GetHeader("X-Amz-Target") == "AmazonSSM.GetParameter" {
+		var body struct {
+			Name string `json:"Name"`
+		}
+		c.BindJSON(&body)
+
+		c.Data(200, "application/x-amz-json-1.1", []byte(fmt.Sprintf(`
+			{
+			   "Parameter": {
+			      "Name": %q,
+			      "Type": "SecureString",
+			      "Value": "{ \"key\": \"admin_user\", \"secret\": \"admin_pass\" }",
+			      "Version": 1
+			   }
+			}
+		`, body.Name)))
+		return
+	}
+
+	c.Data(200, "application/x-amz-json-1.1", []byte(`
+		{
+		   "Tier": "Standard",
+		   "Version": 1
+		}
+	`))
+}
+
+type fakeAcl struct {
+	Kind         string `json:"kind"`
+	ClusterId    string `json:"cluster_id"`
+	ResourceType string `json:"resource_type"`
+	ResourceName string `json:"resource_name"`
+	PatternType  string `json:"pattern_type"`
+	Principal    string `json:"principal"`
+	Host         string `json:"host"`
+	Operation    string `json:"operation"`
+	Permission   string `json:"permission"`
+}
+
+// fakeAcls keeps the ACLs created on the fake clusters, so that they can be listed and deleted again.
+type fakeAcls struct {
+	mu   sync.Mutex
+	acls []fakeAcl
+}
+
+func (f *fakeAcls) add(clusterId string, acl fakeAcl) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	acl.Kind = "KafkaAcl"
+	acl.ClusterId = clusterId
+	f.acls = append(f.acls, acl)
+}
+
+// find returns the ACLs matching the query parameters, which match any value when left out or set to ANY, and
+// removes them when asked to.
+func (f *fakeAcls) find(clusterId string, query url.Values, remove bool) []fakeAcl {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	matches := func(key string, value string) bool {
+		filter := query.Get(key)
+		return filter == "" || filter == "ANY" || filter == value
+	}
+
+	found, kept := []fakeAcl{}, []fakeAcl{}
+	for _, acl := range f.acls {
+		if acl.ClusterId == clusterId &&
+			matches("resource_type", acl.ResourceType) &&
+			matches("resource_name", acl.ResourceName) &&
+			matches("pattern_type", acl.PatternType) &&
+			matches("principal", acl.Principal) &&
+			matches("host", acl.Host) &&
+			matches("operation", acl.Operation) &&
+			matches("permission", acl.Permission) {
+			found = append(found, acl)
+		} else {
+			kept = append(kept, acl)
+		}
+	}
+
+	if remove {
+		f.acls = kept
+	}
+
+	return found
+}
+
+type fakeRoleBinding struct {
+	Id         string `json:"id"`
+	Principal  string `json:"principal"`
+	RoleName   string `json:"role_name"`
+	CrnPattern string `json:"crn_pattern"`
+}
+
+// fakeRoleBindings keeps the role bindings created, so that they can be listed and deleted again.
+type fakeRoleBindings struct {
+	mu           sync.Mutex
+	created      int
+	roleBindings []fakeRoleBinding
+}
+
+func (f *fakeRoleBindings) add(roleBinding fakeRoleBinding) fakeRoleBinding {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.created++
+	roleBinding.Id = fmt.Sprintf("rb-%d", f.created)
+	f.roleBindings = append(f.roleBindings, roleBinding)
+	return roleBinding
+}
+
+func (f *fakeRoleBindings) find(principal string, crnPattern string) []fakeRoleBinding {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	found := []fakeRoleBinding{}
+	for _, roleBinding := range f.roleBindings {
+		if (principal == "" || roleBinding.Principal == principal) && (crnPattern == "" || roleBinding.CrnPattern == crnPattern) {
+			found = append(found, roleBinding)
+		}
+	}
+	return found
+}
+
+func (f *fakeRoleBindings) remove(id string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i, roleBinding := range f.roleBindings {
+		if roleBinding.Id == id {
+			f.roleBindings = append(f.roleBindings[:i], f.roleBindings[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
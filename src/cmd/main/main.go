@@ -14,8 +14,10 @@ import (
 	"github.com/dfds/confluent-gateway/internal/confluent"
 	"github.com/dfds/confluent-gateway/internal/create"
 	del "github.com/dfds/confluent-gateway/internal/delete"
+	"github.com/dfds/confluent-gateway/internal/grant"
 	"github.com/dfds/confluent-gateway/internal/handlers"
 	"github.com/dfds/confluent-gateway/internal/http/metrics"
+	"github.com/dfds/confluent-gateway/internal/resume"
 	"github.com/dfds/confluent-gateway/internal/router"
 	schema "github.com/dfds/confluent-gateway/internal/schema"
 	"github.com/dfds/confluent-gateway/internal/serviceaccount"
@@ -35,40 +37,94 @@ func main() {
 	config := configuration.LoadInto("", &configuration.Configuration{})
 	logger := logging.NewLogger(logging.LoggerOptions{IsProduction: config.IsProduction(), AppName: config.ApplicationName})
 	db := Must(storage.NewDatabase(config.DbConnectionString, logger))
-	clusters := Must(db.GetClusters(ctx))
-	confluentClient := confluent.NewClient(logger, config.CreateCloudApiAccess(), storage.NewClusterCache(clusters))
+	clusterCache := storage.NewClusterCache(nil)
+	confluentClient := confluent.NewClient(logger, config.CreateCloudApiAccess(), clusterCache)
 	awsClient := Must(vault.NewVaultClient(logger, Must(config.CreateVaultConfig())))
+	clusterService := services.NewClusterService(logger, db, awsClient, clusterCache, Must(config.CreateClusterEncryptor()))
+
+	if len(os.Args) > 1 && os.Args[1] == "reencrypt-clusters" {
+		if err := RunReencrypt(ctx, clusterService, os.Stdout); err != nil {
+			logger.Error(err, "Re-encrypting clusters failed: {Reason}", err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+
+	if err := clusterService.RefreshClusters(ctx); err != nil {
+		panic(err)
+	}
 
 	outboxFactory := Must(messaging.ConfigureOutbox(logger,
 		// TODO -- fix inconsistency in message type
 		messaging.RegisterMessage(config.TopicNameProvisioning, "topic_provisioned", &create.TopicProvisioned{}),
 		messaging.RegisterMessage(config.TopicNameProvisioning, "topic_provisioning_begun", &create.TopicProvisioningBegun{}),
+		messaging.RegisterMessage(config.TopicNameProvisioning, "topic_provisioning_failed", &create.TopicProvisioningFailed{}),
 		messaging.RegisterMessage(config.TopicNameProvisioning, "topic-deleted", &del.TopicDeleted{}),
+		messaging.RegisterMessage(config.TopicNameProvisioning, "topic_deletion_failed", &del.TopicDeletionFailed{}),
+		messaging.RegisterMessage(config.TopicNameProvisioning, "process-failed", &resume.ProcessFailed{}),
 		messaging.RegisterMessage(config.TopicNameSchema, "schema-registered", &schema.SchemaRegistered{}),
 		messaging.RegisterMessage(config.TopicNameSchema, "schema-registration-failed", &schema.SchemaRegistrationFailed{}),
+		messaging.RegisterMessage(config.TopicNameSchema, "schema-deleted", &schema.SchemaDeleted{}),
 		messaging.RegisterMessage(config.TopicNameKafkaClusterAccessGranted, "cluster-access-granted", &serviceaccount.ServiceAccountAccessGranted{}),
+		messaging.RegisterMessage(config.TopicNameKafkaClusterAccessGranted, "access-granted", &grant.AccessGranted{}),
+		messaging.RegisterMessage(config.TopicNameKafkaClusterAccessGranted, "access-revoked", &grant.AccessRevoked{}),
 	))
-	createTopicProcess := create.NewProcess(logger, db, confluentClient, func(repository create.OutboxRepository) create.Outbox { return outboxFactory(repository) })
+	topicNamingPolicy := Must(config.CreateTopicNamingPolicy())
+	createTopicProcess := create.NewProcess(logger, db, confluentClient, func(repository create.OutboxRepository) create.Outbox { return outboxFactory(repository) }, topicNamingPolicy)
 	createServiceAccountProcess := serviceaccount.NewProcess(logger, db, confluentClient, awsClient, func(repository serviceaccount.OutboxRepository) serviceaccount.Outbox {
 		return outboxFactory(repository)
 	})
 	deleteTopicProcess := del.NewProcess(logger, db, confluentClient, func(repository del.OutboxRepository) del.Outbox { return outboxFactory(repository) })
 	addSchemaProcess := schema.NewProcess(logger, db, confluentClient, awsClient, func(repository schema.OutboxRepository) schema.Outbox { return outboxFactory(repository) })
+	deleteSchemaProcess := schema.NewDeleteProcess(logger, db, confluentClient, func(repository schema.OutboxRepository) schema.Outbox { return outboxFactory(repository) })
+	grantService := services.NewGrantService(logger, db, db, confluentClient, func(repository services.GrantOutboxRepository) services.GrantOutbox { return outboxFactory(repository) })
+	planService := services.NewPlanService(logger, db, confluentClient, awsClient, outboxFactory, topicNamingPolicy)
+
+	if len(os.Args) > 1 && os.Args[1] == "plan" {
+		if err := RunPlan(ctx, planService, os.Args[2:], os.Stdin, os.Stdout); err != nil {
+			logger.Error(err, "Plan failed: {Reason}", err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+
 	consumer := Must(messaging.ConfigureConsumer(logger, config.KafkaBroker, config.KafkaGroupId,
 		messaging.WithCredentials(config.CreateConsumerCredentials()),
 		messaging.RegisterMessageHandler(config.TopicNameSelfService, "topic_requested", create.NewTopicRequestedHandler(createTopicProcess), &create.TopicRequested{}),
 		messaging.RegisterMessageHandler(config.TopicNameSelfService, "topic-requested", create.NewTopicRequestedHandler(createTopicProcess), &create.TopicRequested{}),
 		messaging.RegisterMessageHandler(config.TopicNameSelfService, "topic-deleted", del.NewTopicRequestedHandler(deleteTopicProcess), &del.TopicDeletionRequested{}),
 		messaging.RegisterMessageHandler(config.TopicNameMessageContract, "message-contract-requested", schema.NewSchemaAddedHandler(addSchemaProcess), &schema.MessageContractRequested{}),
+		messaging.RegisterMessageHandler(config.TopicNameMessageContract, "message-contract-deleted", schema.NewMessageContractDeletedHandler(deleteSchemaProcess), &schema.MessageContractDeleted{}),
 		messaging.RegisterMessageHandler(config.TopicNameMessageContract, "message-contract-provisioned", messaging.NewNopHandler(logger), &messaging.Nop{}),
 		messaging.RegisterMessageHandler(config.TopicNameKafkaClusterAccess, "cluster-access-requested", serviceaccount.NewAccessRequestedHandler(createServiceAccountProcess), &serviceaccount.ServiceAccountAccessRequested{}),
+		messaging.RegisterMessageHandler(config.TopicNameKafkaClusterAccess, "access-grant-requested", grant.NewAccessGrantRequestedHandler(grantService), &grant.AccessGrantRequested{}),
 	))
 
 	// API setup
-	schemaService := services.NewSchemaService(logger, confluentClient)
-	handler := handlers.NewHandler(ctx, logger, schemaService)
+	schemaService := services.NewSchemaService(logger, confluentClient, db)
+	topicService := services.NewTopicService(logger, db, confluentClient)
+	accessService := services.NewAccessService(logger, db, confluentClient, awsClient)
+	aclTemplateService := services.NewAclTemplateService(logger, db, db, confluentClient)
+	handler := &handlers.Handler{
+		Ctx:                ctx,
+		Logger:             logger,
+		SchemaService:      schemaService,
+		TopicService:       topicService,
+		AccessService:      accessService,
+		PlanService:        planService,
+		ClusterService:     clusterService,
+		AclTemplateService: aclTemplateService,
+		GrantService:       grantService,
+	}
 
-	m := NewMain(logger, config, consumer, handler)
+	auth := Must(config.CreateApiAuth())
+	if auth == nil {
+		logger.Warning("API authentication is disabled, every route is open to anyone who can reach the API")
+	}
+
+	scheduler := resume.NewScheduler(logger, db, func(repository resume.OutboxRepository) resume.Outbox { return outboxFactory(repository) }, Must(config.CreateResumeConfig()), createTopicProcess, deleteTopicProcess, addSchemaProcess)
+
+	m := NewMain(logger, config, consumer, handler, auth, scheduler, clusterService, Must(config.CreateClusterRefreshInterval()))
 
 	logger.Information("Running")
 
@@ -92,17 +148,24 @@ type Main struct {
 	Consumer      messaging.Consumer
 	MetricsServer *metrics.Server
 	HttpServer    *http.Server
+	Scheduler     *resume.Scheduler
+
+	ClusterService         *services.ClusterService
+	ClusterRefreshInterval time.Duration
 }
 
-func NewMain(logger logging.Logger, config *configuration.Configuration, consumer messaging.Consumer, handler *handlers.Handler) *Main {
+func NewMain(logger logging.Logger, config *configuration.Configuration, consumer messaging.Consumer, handler *handlers.Handler, auth *router.Auth, scheduler *resume.Scheduler, clusterService *services.ClusterService, clusterRefreshInterval time.Duration) *Main {
 	return &Main{
 		Logger:        logger,
 		Consumer:      consumer,
 		MetricsServer: metrics.NewServer(logger, config.IsProduction()),
 		HttpServer: &http.Server{
 			Addr:    ":8080",
-			Handler: router.SetupRoutes(handler),
+			Handler: router.SetupRoutes(handler, auth),
 		},
+		Scheduler:              scheduler,
+		ClusterService:         clusterService,
+		ClusterRefreshInterval: clusterRefreshInterval,
 	}
 }
 
@@ -112,6 +175,8 @@ func (m *Main) Run(ctx context.Context) error {
 	m.RunMetricsServer(g, gCtx)
 	m.RunConsumer(g, gCtx)
 	m.RunHttpServer(g, gCtx)
+	m.RunScheduler(g, gCtx)
+	m.RunClusterRefresh(g, gCtx)
 
 	// wait for context or all go routines to finish
 	return g.Wait()
@@ -148,6 +213,19 @@ func (m *Main) RunMetricsServer(g *errgroup.Group, ctx context.Context) {
 	})
 }
 
+func (m *Main) RunScheduler(g *errgroup.Group, ctx context.Context) {
+	g.Go(func() error {
+		m.Logger.Information("Starting scheduler resuming stuck processes")
+		return m.Scheduler.Run(ctx)
+	})
+}
+
+func (m *Main) RunClusterRefresh(g *errgroup.Group, ctx context.Context) {
+	g.Go(func() error {
+		return m.ClusterService.RefreshPeriodically(ctx, m.ClusterRefreshInterval)
+	})
+}
+
 func (m *Main) RunConsumer(g *errgroup.Group, ctx context.Context) {
 	cleanup := func() {
 		log.Println("Stopping consumer")
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dfds/confluent-gateway/internal/services"
+)
+
+// RunPlan implements the "plan" command, which prints what the gateway would do when receiving an event without
+// making any changes:
+//
+//	main plan -event topic-requested [-file event.json]
+//
+// The event is read from stdin when no file is given.
+func RunPlan(ctx context.Context, planner services.PlanServiceInterface, args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("plan", flag.ContinueOnError)
+	flags.SetOutput(stdout)
+	eventType := flags.String("event", "", fmt.Sprintf("type of the event to plan, one of: %s", strings.Join(planner.EventTypes(), ", ")))
+	file := flags.String("file", "", "file containing the event data, defaults to stdin")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *eventType == "" {
+		flags.Usage()
+		return errors.New("missing event type")
+	}
+
+	var data []byte
+	var err error
+	if *file != "" {
+		data, err = os.ReadFile(*file)
+	} else {
+		data, err = io.ReadAll(stdin)
+	}
+	if err != nil {
+		return err
+	}
+
+	plan, err := planner.Plan(ctx, *eventType, data)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(plan)
+}
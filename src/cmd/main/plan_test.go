@@ -0,0 +1,35 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/dfds/confluent-gateway/internal/mocks"
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRunPlan_ReadsEventFromStdin(t *testing.T) {
+	planner := &mocks.MockPlanService{}
+	planner.On("EventTypes").Return([]string{"topic-requested"})
+	planner.On("Plan", mock.Anything, "topic-requested", []byte(`{"topicName":"some-topic"}`)).Return(&models.Plan{EventType: "topic-requested", Actions: []models.PlanAction{}}, nil)
+	stdout := &bytes.Buffer{}
+
+	err := RunPlan(context.TODO(), planner, []string{"-event", "topic-requested"}, strings.NewReader(`{"topicName":"some-topic"}`), stdout)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"eventType": "topic-requested", "actions": []}`, stdout.String())
+	planner.AssertExpectations(t)
+}
+
+func TestRunPlan_MissingEventType(t *testing.T) {
+	planner := &mocks.MockPlanService{}
+	planner.On("EventTypes").Return([]string{"topic-requested"})
+
+	err := RunPlan(context.TODO(), planner, []string{}, strings.NewReader(""), &bytes.Buffer{})
+
+	assert.EqualError(t, err, "missing event type")
+}
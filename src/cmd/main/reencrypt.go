@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/dfds/confluent-gateway/internal/models"
+)
+
+type ApiKeyReencrypter interface {
+	ReencryptApiKeys(ctx context.Context) ([]models.ClusterId, error)
+}
+
+// RunReencrypt implements the "reencrypt-clusters" command, which encrypts the api keys kept in the cluster table
+// with new data keys, including the ones stored in plain text:
+//
+//	main reencrypt-clusters
+func RunReencrypt(ctx context.Context, reencrypter ApiKeyReencrypter, stdout io.Writer) error {
+	reencrypted, err := reencrypter.ReencryptApiKeys(ctx)
+	for _, clusterId := range reencrypted {
+		fmt.Fprintf(stdout, "re-encrypted api keys of cluster %s\n", clusterId)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "re-encrypted %d cluster(s)\n", len(reencrypted))
+	return nil
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunReencrypt(t *testing.T) {
+	stdout := &bytes.Buffer{}
+
+	err := RunReencrypt(context.TODO(), reencrypterStub{ClusterIds: []models.ClusterId{"abc-1234"}}, stdout)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "re-encrypted api keys of cluster abc-1234\nre-encrypted 1 cluster(s)\n", stdout.String())
+}
+
+func TestRunReencrypt_ReportsProgressOnFailure(t *testing.T) {
+	stdout := &bytes.Buffer{}
+
+	err := RunReencrypt(context.TODO(), reencrypterStub{ClusterIds: []models.ClusterId{"abc-1234"}, Err: errors.New("some-error")}, stdout)
+
+	assert.EqualError(t, err, "some-error")
+	assert.Equal(t, "re-encrypted api keys of cluster abc-1234\n", stdout.String())
+}
+
+type reencrypterStub struct {
+	ClusterIds []models.ClusterId
+	Err        error
+}
+
+func (s reencrypterStub) ReencryptApiKeys(context.Context) ([]models.ClusterId, error) {
+	return s.ClusterIds, s.Err
+}
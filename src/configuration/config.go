@@ -1,10 +1,20 @@
 package configuration
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/dfds/confluent-gateway/internal/confluent"
+	"github.com/dfds/confluent-gateway/internal/create"
+	"github.com/dfds/confluent-gateway/internal/encryption"
+	"github.com/dfds/confluent-gateway/internal/resume"
+	"github.com/dfds/confluent-gateway/internal/router"
 	"github.com/dfds/confluent-gateway/internal/vault"
 	"github.com/dfds/confluent-gateway/messaging"
 )
@@ -29,6 +39,24 @@ type Configuration struct {
 	TopicNameMessageContract           string `env:"CG_TOPIC_NAME_MESSAGE_CONTRACT"`
 	TopicNameSchema                    string `env:"CG_TOPIC_NAME_SCHEMA"`
 	ApiHttpListenAddress               string `env:"CG_API_HTTP_LISTEN_ADDRESS"`
+	ApiAuthEnabled                     string `env:"CG_API_AUTH_ENABLED"`
+	ApiJwksUrl                         string `env:"CG_API_JWKS_URL"`
+	ApiJwksFile                        string `env:"CG_API_JWKS_FILE"`
+	ApiJwtIssuer                       string `env:"CG_API_JWT_ISSUER"`
+	ApiJwtAudience                     string `env:"CG_API_JWT_AUDIENCE"`
+	ApiKeys                            string `env:"CG_API_KEYS"`
+	ResumeInterval                     string `env:"CG_RESUME_INTERVAL"`
+	ResumeThreshold                    string `env:"CG_RESUME_THRESHOLD"`
+	ResumeMaxAttempts                  string `env:"CG_RESUME_MAX_ATTEMPTS"`
+	ResumeBackoff                      string `env:"CG_RESUME_BACKOFF"`
+	ResumeConcurrency                  string `env:"CG_RESUME_CONCURRENCY"`
+	ClusterRefreshInterval             string `env:"CG_CLUSTER_REFRESH_INTERVAL"`
+	ClusterKeyFile                     string `env:"CG_CLUSTER_KEY_FILE"`
+	ClusterKmsKeyId                    string `env:"CG_CLUSTER_KMS_KEY_ID"`
+	TopicNamePrefixOptional            bool   `env:"CG_TOPIC_NAME_PREFIX_OPTIONAL"`
+	TopicNameAllowedPattern            string `env:"CG_TOPIC_NAME_ALLOWED_PATTERN"`
+	TopicNameMaxLength                 string `env:"CG_TOPIC_NAME_MAX_LENGTH"`
+	TopicNameReserved                  string `env:"CG_TOPIC_NAME_RESERVED"`
 }
 
 func (c *Configuration) IsProduction() bool {
@@ -62,3 +90,166 @@ func (c *Configuration) CreateCloudApiAccess() confluent.CloudApiAccess {
 		UserApiEndpoint: c.ConfluentUserApiUrl,
 	}
 }
+
+// CreateApiAuth returns the authentication of the HTTP API, or nil when it has been disabled. Authentication is on
+// unless CG_API_AUTH_ENABLED is set to false.
+func (c *Configuration) CreateApiAuth() (*router.Auth, error) {
+	if c.ApiAuthEnabled != "" {
+		enabled, err := strconv.ParseBool(c.ApiAuthEnabled)
+		if err != nil {
+			return nil, fmt.Errorf("CG_API_AUTH_ENABLED must be true or false, got %q", c.ApiAuthEnabled)
+		}
+		if !enabled {
+			return nil, nil
+		}
+	}
+
+	var authenticators []router.Authenticator
+
+	if c.ApiJwksFile != "" {
+		data, err := os.ReadFile(c.ApiJwksFile)
+		if err != nil {
+			return nil, err
+		}
+
+		keySet, err := router.NewStaticKeySet(data)
+		if err != nil {
+			return nil, err
+		}
+
+		authenticators = append(authenticators, router.NewJwtAuthenticator(keySet, c.ApiJwtIssuer, c.ApiJwtAudience))
+	} else if c.ApiJwksUrl != "" {
+		authenticators = append(authenticators, router.NewJwtAuthenticator(router.NewRemoteKeySet(c.ApiJwksUrl), c.ApiJwtIssuer, c.ApiJwtAudience))
+	}
+
+	if c.ApiKeys != "" {
+		apiKeys, err := router.ParseApiKeys(c.ApiKeys)
+		if err != nil {
+			return nil, err
+		}
+
+		authenticators = append(authenticators, apiKeys)
+	}
+
+	if len(authenticators) == 0 {
+		return nil, errors.New("api authentication is enabled but neither a key set nor api keys are configured")
+	}
+
+	return router.NewAuth(router.DefaultRules(), authenticators...), nil
+}
+
+// CreateResumeConfig returns the configuration of resuming stuck processes, using the defaults for the settings
+// left out.
+func (c *Configuration) CreateResumeConfig() (resume.Config, error) {
+	config := resume.DefaultConfig()
+
+	durations := []struct {
+		name   string
+		value  string
+		target *time.Duration
+	}{
+		{"CG_RESUME_INTERVAL", c.ResumeInterval, &config.Interval},
+		{"CG_RESUME_THRESHOLD", c.ResumeThreshold, &config.Threshold},
+		{"CG_RESUME_BACKOFF", c.ResumeBackoff, &config.Backoff},
+	}
+	for _, d := range durations {
+		if d.value == "" {
+			continue
+		}
+		value, err := time.ParseDuration(d.value)
+		if err != nil || value <= 0 {
+			return config, fmt.Errorf("%s must be a positive duration, got %q", d.name, d.value)
+		}
+		*d.target = value
+	}
+
+	numbers := []struct {
+		name   string
+		value  string
+		target *int
+	}{
+		{"CG_RESUME_MAX_ATTEMPTS", c.ResumeMaxAttempts, &config.MaxAttempts},
+		{"CG_RESUME_CONCURRENCY", c.ResumeConcurrency, &config.Concurrency},
+	}
+	for _, n := range numbers {
+		if n.value == "" {
+			continue
+		}
+		value, err := strconv.Atoi(n.value)
+		if err != nil || value <= 0 {
+			return config, fmt.Errorf("%s must be a positive number, got %q", n.name, n.value)
+		}
+		*n.target = value
+	}
+
+	return config, nil
+}
+
+// CreateClusterRefreshInterval returns how often the clusters are reloaded, which is every minute unless configured.
+func (c *Configuration) CreateClusterRefreshInterval() (time.Duration, error) {
+	if c.ClusterRefreshInterval == "" {
+		return time.Minute, nil
+	}
+
+	interval, err := time.ParseDuration(c.ClusterRefreshInterval)
+	if err != nil || interval <= 0 {
+		return 0, fmt.Errorf("CG_CLUSTER_REFRESH_INTERVAL must be a positive duration, got %q", c.ClusterRefreshInterval)
+	}
+
+	return interval, nil
+}
+
+// CreateTopicNamingPolicy returns the rules requested topic names are checked against, using the defaults for the
+// settings left out. Reserved names are separated by commas.
+func (c *Configuration) CreateTopicNamingPolicy() (create.NamingPolicy, error) {
+	policy := create.DefaultNamingPolicy()
+	policy.RequireCapabilityPrefix = !c.TopicNamePrefixOptional
+
+	if c.TopicNameAllowedPattern != "" {
+		pattern, err := regexp.Compile(c.TopicNameAllowedPattern)
+		if err != nil {
+			return policy, fmt.Errorf("CG_TOPIC_NAME_ALLOWED_PATTERN must be a regular expression, got %q", c.TopicNameAllowedPattern)
+		}
+		policy.AllowedCharacters = pattern
+	}
+
+	if c.TopicNameMaxLength != "" {
+		value, err := strconv.Atoi(c.TopicNameMaxLength)
+		if err != nil || value <= 0 {
+			return policy, fmt.Errorf("CG_TOPIC_NAME_MAX_LENGTH must be a positive number, got %q", c.TopicNameMaxLength)
+		}
+		policy.MaxLength = value
+	}
+
+	for _, name := range strings.Split(c.TopicNameReserved, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			policy.ReservedNames = append(policy.ReservedNames, name)
+		}
+	}
+
+	return policy, nil
+}
+
+// CreateClusterEncryptor returns the encryption of the api keys kept in the cluster table, using KMS when a key id is
+// configured and otherwise a local key file. Without either, only api keys stored in plain text can be read.
+func (c *Configuration) CreateClusterEncryptor() (*encryption.Encryptor, error) {
+	if c.ClusterKmsKeyId != "" {
+		cfg, err := c.CreateVaultConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		return encryption.NewEncryptor(encryption.NewKmsKeyProvider(cfg, c.ClusterKmsKeyId)), nil
+	}
+
+	if c.ClusterKeyFile != "" {
+		provider, err := encryption.NewLocalKeyProviderFromFile(c.ClusterKeyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		return encryption.NewEncryptor(provider), nil
+	}
+
+	return encryption.NewEncryptor(nil), nil
+}
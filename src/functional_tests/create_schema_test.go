@@ -23,8 +23,8 @@ func setupCreateSchemaHttpMock(processInput schema.ProcessInput, topicName strin
 		Version    int32  `json:"version"`
 	}
 	payload, err := json.Marshal(schemaPayload{
-		SchemaType: "JSON",
-		Schema:     processInput.Schema,
+		SchemaType: string(processInput.Schema.SchemaType),
+		Schema:     processInput.Schema.Schema,
 		Version:    processInput.SchemaVersion,
 	})
 	if err != nil {
@@ -32,6 +32,12 @@ func setupCreateSchemaHttpMock(processInput schema.ProcessInput, topicName strin
 	}
 
 	subjectName := fmt.Sprintf("%s-%s", topicName, processInput.MessageType)
+	gock.
+		New(seedVariables.SchemaRegistryApiEndpoint).
+		Post(fmt.Sprintf("/compatibility/subjects/%s/versions/latest", subjectName)).
+		BasicAuth(seedVariables.SchemaRegistryAdminUser, seedVariables.SchemaRegistryAdminPassword).
+		Reply(404).
+		BodyString(`{"error_code":40401,"message":"Subject not found"}`) // first version of the subject
 	gock.
 		New(seedVariables.SchemaRegistryApiEndpoint).
 		Post(fmt.Sprintf("/subjects/%s/versions", subjectName)).
@@ -39,6 +45,12 @@ func setupCreateSchemaHttpMock(processInput schema.ProcessInput, topicName strin
 		JSON(payload).
 		Reply(200).
 		BodyString("") // our code panics on empty responses
+	gock.
+		New(seedVariables.SchemaRegistryApiEndpoint).
+		Post(fmt.Sprintf("/subjects/%s$", subjectName)).
+		BasicAuth(seedVariables.SchemaRegistryAdminUser, seedVariables.SchemaRegistryAdminPassword).
+		Reply(200).
+		JSON(map[string]any{"subject": subjectName, "id": 1, "version": 1})
 }
 
 func TestCreateSchemaProcess(t *testing.T) {
@@ -88,9 +100,9 @@ func TestCreateSchemaProcess(t *testing.T) {
 	err = testerApp.db.CreateClusterAccess(&clusterAccess)
 
 	//ensureServiceAccountSchemaRegistryAccessStep
-	setupListKeysHTTPMock(string(testerApp.dbSeedVariables.DevelopmentSchemaRegistryId), someServiceAccountID, 0)                      // Check if the api key has already been created
-	setupCreateApiKeyMock(string(testerApp.dbSeedVariables.DevelopmentSchemaRegistryId), someServiceAccountID, "username", "p4ssword") // Then we create an API key for the schema registry
-	setupRoleBindingHTTPMock(string(someServiceAccountID), testerApp.dbSeedVariables.GetDevelopmentClusterValues())                    // Then we create a role binding for the service account
+	setupListKeysHTTPMock(string(testerApp.dbSeedVariables.DevelopmentSchemaRegistryId), someServiceAccountID, 0)                                       // Check if the api key has already been created
+	setupCreateApiKeyMock(string(testerApp.dbSeedVariables.DevelopmentSchemaRegistryId), someServiceAccountID, "username", "p4ssword")                  // Then we create an API key for the schema registry
+	setupRoleBindingHTTPMock(string(someServiceAccountID), createSchemaVariables.CapabilityId, testerApp.dbSeedVariables.GetDevelopmentClusterValues()) // Then we create a role binding for the service account
 
 	process := schema.NewProcess(testerApp.logger, testerApp.db, testerApp.confluentClient, *testerApp.vaultClient, func(repository schema.OutboxRepository) schema.Outbox {
 		return outboxFactory(repository)
@@ -101,7 +113,7 @@ func TestCreateSchemaProcess(t *testing.T) {
 		TopicId:           createSchemaVariables.TopicId,
 		MessageType:       "message-type-for-schema-registry",
 		Description:       "schema-description",
-		Schema:            "test-schema",
+		Schema:            models.NewSchemaDefinition(models.SchemaTypeJson, `{"type":"object"}`, nil),
 		SchemaVersion:     1,
 	}
 
@@ -9,6 +9,7 @@ import (
 	"github.com/dfds/confluent-gateway/messaging"
 	"github.com/h2non/gock"
 	"github.com/stretchr/testify/require"
+	"regexp"
 	"testing"
 )
 
@@ -146,19 +147,36 @@ func setupCreateApiKeyMock(resourceId string, serviceAccountId models.ServiceAcc
 		JSON(r)
 }
 
-func setupRoleBindingHTTPMock(serviceAccount string, cluster models.Cluster) {
-	payload := fmt.Sprintf(`{
+func setupRoleBindingHTTPMock(serviceAccount string, capabilityId models.CapabilityId, cluster models.Cluster) {
+	for i, definition := range models.SchemaRegistryRoleBindings(&cluster, capabilityId, nil) {
+		setupListRoleBindingsHTTPMock(serviceAccount, definition.CrnPattern)
+
+		payload := fmt.Sprintf(`{
 		"principal": "User:%s",
-		"role_name": "DeveloperRead",
-		"crn_pattern": "crn://confluent.cloud/organization=%s/environment=%s/schema-registry=%s/subject=*"
-	}`, serviceAccount, cluster.OrganizationId, cluster.EnvironmentId, cluster.SchemaRegistryId)
+		"role_name": "%s",
+		"crn_pattern": "%s"
+	}`, serviceAccount, definition.RoleName, definition.CrnPattern)
+
+		gock.New(testerApp.config.ConfluentCloudApiUrl).
+			Post("/iam/v2/role-bindings").
+			BodyString(payload).
+			BasicAuth(testerApp.config.ConfluentCloudApiUserName, testerApp.config.ConfluentCloudApiPassword).
+			Reply(200).
+			JSON(map[string]string{"id": fmt.Sprintf("rb-%d", i)})
+	}
+
+	// the role binding on all subjects is revoked, and the service account never had it
+	setupListRoleBindingsHTTPMock(serviceAccount, models.AllSubjectsRoleBinding(&cluster).CrnPattern)
+}
 
+func setupListRoleBindingsHTTPMock(serviceAccount string, crnPattern string) {
 	gock.New(testerApp.config.ConfluentCloudApiUrl).
-		Post("/iam/v2/role-bindings").
-		BodyString(payload).
+		Get("/iam/v2/role-bindings").
+		MatchParam("principal", regexp.QuoteMeta("User:"+serviceAccount)).
+		MatchParam("crn_pattern", regexp.QuoteMeta(crnPattern)).
 		BasicAuth(testerApp.config.ConfluentCloudApiUserName, testerApp.config.ConfluentCloudApiPassword).
 		Reply(200).
-		JSON(map[string]string{"id": "not-used"})
+		JSON(map[string]any{"data": []any{}})
 }
 
 func setupListKeysHTTPMock(resourceId string, serviceAccountId models.ServiceAccountId, totalSize int) {
@@ -234,7 +252,7 @@ func TestCreateServiceAccountProcess(t *testing.T) {
 	//ensureServiceAccountSchemaRegistryAccessStep
 	setupListKeysHTTPMock(string(testerApp.dbSeedVariables.DevelopmentSchemaRegistryId), createServiceAccountVariables.ServiceAccountId, 0)                                                                          // Check if the api key has already been created
 	setupCreateApiKeyMock(string(testerApp.dbSeedVariables.DevelopmentSchemaRegistryId), createServiceAccountVariables.ServiceAccountId, createdSchemaRegistryApiKey.Username, createdSchemaRegistryApiKey.Password) // Then we create an API key for the schema registry
-	setupRoleBindingHTTPMock(string(createServiceAccountVariables.ServiceAccountId), testerApp.dbSeedVariables.GetDevelopmentClusterValues())                                                                        // Then we create a role binding for the service account
+	setupRoleBindingHTTPMock(string(createServiceAccountVariables.ServiceAccountId), createServiceAccountVariables.CapabilityId, testerApp.dbSeedVariables.GetDevelopmentClusterValues())                            // Then we create a role binding for the service account
 
 	err = process.Process(context.Background(), input)
 	require.NoError(t, err)
@@ -303,7 +321,7 @@ func TestCreateServiceAccountWithExistingServiceAccountNameProcess(t *testing.T)
 	//ensureServiceAccountSchemaRegistryAccessStep
 	setupListKeysHTTPMock(string(testerApp.dbSeedVariables.DevelopmentSchemaRegistryId), createServiceAccountVariables.ServiceAccountId, 0)                                                                          // Check if the api key has already been created
 	setupCreateApiKeyMock(string(testerApp.dbSeedVariables.DevelopmentSchemaRegistryId), createServiceAccountVariables.ServiceAccountId, createdSchemaRegistryApiKey.Username, createdSchemaRegistryApiKey.Password) // Then we create an API key for the schema registry
-	setupRoleBindingHTTPMock(string(createServiceAccountVariables.ServiceAccountId), testerApp.dbSeedVariables.GetDevelopmentClusterValues())                                                                        // Then we create a role binding for the service account
+	setupRoleBindingHTTPMock(string(createServiceAccountVariables.ServiceAccountId), createServiceAccountVariables.CapabilityId, testerApp.dbSeedVariables.GetDevelopmentClusterValues())                            // Then we create a role binding for the service account
 
 	err = process.Process(context.Background(), input)
 	require.NoError(t, err)
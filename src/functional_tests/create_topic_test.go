@@ -96,9 +96,9 @@ func TestCreateTopicProcess(t *testing.T) {
 
 	process := create.NewProcess(testerApp.logger, testerApp.db, testerApp.confluentClient, func(repository create.OutboxRepository) create.Outbox {
 		return outboxFactory(repository)
-	})
+	}, create.DefaultNamingPolicy())
 	topicDescription := models.TopicDescription{
-		Name:       "topic-name-1234",
+		Name:       string(createTopicVariables.CapabilityId) + ".topic-name-1234",
 		Partitions: 1,
 		Retention:  time.Hour * 24,
 	}
@@ -35,7 +35,7 @@ func (d *Database) AddMockServiceAccountWithClusterAccess(serviceAccountId model
 	capabilityId models.CapabilityId) (*models.ServiceAccount, error) {
 
 	clusterAccess := []models.ClusterAccess{
-		*models.NewClusterAccess(serviceAccountId, userAccountId, clusterId, capabilityId),
+		*models.NewClusterAccess(serviceAccountId, userAccountId, clusterId, capabilityId, models.DefaultAclTemplate(clusterId)),
 	}
 
 	newServiceAccount := &models.ServiceAccount{
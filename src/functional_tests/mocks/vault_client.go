@@ -3,16 +3,19 @@ package mocks
 import (
 	"context"
 	"fmt"
+	"github.com/dfds/confluent-gateway/internal/models"
 	"github.com/dfds/confluent-gateway/internal/vault"
 )
 
 type vaultMock struct {
-	keys map[string]string
+	keys    map[string]string
+	apiKeys map[string]models.ApiKey
 }
 
 func NewVaultMock() vault.Vault {
 	return &vaultMock{
-		keys: map[string]string{},
+		keys:    map[string]string{},
+		apiKeys: map[string]models.ApiKey{},
 	}
 }
 
@@ -23,6 +26,10 @@ func getTestApiParameter(input vault.Input) string {
 		return fmt.Sprintf("/capabilities/%s/kafka/%s/credentials", input.CapabilityId, input.ClusterId)
 	case vault.OperationDestinationSchemaRegistry:
 		return fmt.Sprintf("/capabilities/%s/kafka/%s/schemaregistry-credentials", input.CapabilityId, input.ClusterId)
+	case vault.OperationDestinationClusterAdmin:
+		return fmt.Sprintf("/kafka/clusters/%s/admin-credentials", input.ClusterId)
+	case vault.OperationDestinationSchemaRegistryAdmin:
+		return fmt.Sprintf("/kafka/clusters/%s/schemaregistry-admin-credentials", input.ClusterId)
 	}
 	return ""
 }
@@ -33,6 +40,9 @@ func getTestVaultInput(input vault.Input) string {
 
 func (v *vaultMock) StoreApiKey(ctx context.Context, input vault.Input) error {
 	v.keys[getTestApiParameter(input)] = getTestVaultInput(input)
+	if input.StoringInput != nil {
+		v.apiKeys[getTestApiParameter(input)] = input.StoringInput.ApiKey
+	}
 	return nil
 }
 
@@ -43,5 +53,14 @@ func (v *vaultMock) QueryApiKey(ctx context.Context, input vault.Input) (bool, e
 
 func (v *vaultMock) DeleteApiKey(ctx context.Context, input vault.Input) error {
 	delete(v.keys, getTestApiParameter(input))
+	delete(v.apiKeys, getTestApiParameter(input))
 	return nil
 }
+
+func (v *vaultMock) GetApiKey(ctx context.Context, input vault.Input) (models.ApiKey, error) {
+	apiKey, ok := v.apiKeys[getTestApiParameter(input)]
+	if !ok {
+		return models.ApiKey{}, vault.ErrApiKeyNotFound
+	}
+	return apiKey, nil
+}
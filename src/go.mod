@@ -5,8 +5,10 @@ go 1.23.2
 require (
 	github.com/aws/aws-sdk-go-v2 v1.30.5
 	github.com/aws/aws-sdk-go-v2/config v1.27.33
+	github.com/aws/aws-sdk-go-v2/service/kms v1.35.7
 	github.com/aws/aws-sdk-go-v2/service/ssm v1.52.8
 	github.com/gofrs/uuid v4.0.0+incompatible
+	github.com/golang-jwt/jwt/v5 v5.2.1
 	github.com/h2non/gock v1.2.0
 	github.com/prometheus/client_golang v1.20.3
 	github.com/rs/zerolog v1.33.0
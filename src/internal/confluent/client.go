@@ -8,11 +8,14 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/dfds/confluent-gateway/internal/models"
 	"github.com/dfds/confluent-gateway/logging"
+	"golang.org/x/sync/errgroup"
 )
 
 type CloudApiAccess struct {
@@ -23,10 +26,11 @@ type CloudApiAccess struct {
 }
 
 var ErrSchemaRegistryIdIsEmpty = errors.New("schema registry id is not found, manually add id to cluster table")
-var ErrMissingSchemaRegistryIds = errors.New("unable to create schema registry role binding: cluster table has any or all missing ids: organization_id, environment_id, schema_registry_id")
+var ErrMissingSchemaRegistryIds = errors.New("unable to create schema registry role bindings: cluster table has any or all missing ids: organization_id, environment_id, schema_registry_id")
 var ErrApiKeyNotFoundForDeletion = errors.New("unable to delete api key: key not found in confluent")
 var ErrFoundExistingServiceAccount = errors.New("unable to create service account, service name already in use")
 var ErrNoServiceAccountFound = errors.New("unable to find requested service account")
+var ErrAclFilterWithoutPrincipal = errors.New("unable to delete acls: the filter has no principal")
 
 func (a *CloudApiAccess) ApiKey() models.ApiKey {
 	return models.ApiKey{Username: a.Username, Password: a.Password}
@@ -43,20 +47,38 @@ type Client struct {
 }
 
 type ConfluentClient interface {
-	ListSchemas(ctx context.Context, subjectPrefix string, clusterId models.ClusterId) ([]models.Schema, error)
+	ListSchemas(ctx context.Context, subjectPrefix string, clusterId models.ClusterId, offset int, limit int) ([]models.Schema, error)
+	ListSubjects(ctx context.Context, clusterId models.ClusterId, subjectPrefix string) ([]string, error)
+	ListSubjectVersions(ctx context.Context, clusterId models.ClusterId, subject string) ([]int, error)
+	GetSchemaVersion(ctx context.Context, clusterId models.ClusterId, subject string, version string) (*models.Schema, error)
+	GetCompatibilityLevel(ctx context.Context, clusterId models.ClusterId, subject string) (string, error)
+	SetCompatibilityLevel(ctx context.Context, clusterId models.ClusterId, subject string, level models.CompatibilityLevel) error
 	CreateServiceAccount(ctx context.Context, name string, description string) (models.ServiceAccountId, error)
 	GetServiceAccount(ctx context.Context, displayName string) (models.ServiceAccountId, error)
 	CreateACLEntry(ctx context.Context, clusterId models.ClusterId, userAccountId models.UserAccountId, entry models.AclDefinition) error
+	CreateACLEntries(ctx context.Context, clusterId models.ClusterId, userAccountId models.UserAccountId, entries []models.AclDefinition) ([]models.AclCreationResult, error)
+	DeleteACLEntry(ctx context.Context, clusterId models.ClusterId, userAccountId models.UserAccountId, entry models.AclDefinition) error
+	ListACLs(ctx context.Context, clusterId models.ClusterId, filter models.AclFilter) ([]models.AclBinding, error)
+	DeleteACLs(ctx context.Context, clusterId models.ClusterId, filter models.AclFilter) ([]models.AclBinding, error)
 	CreateClusterApiKey(ctx context.Context, clusterId models.ClusterId, serviceAccountId models.ServiceAccountId) (models.ApiKey, error)
 	CreateSchemaRegistryApiKey(ctx context.Context, clusterId models.ClusterId, serviceAccountId models.ServiceAccountId) (models.ApiKey, error)
 	DeleteClusterApiKey(ctx context.Context, clusterId models.ClusterId, serviceAccountId models.ServiceAccountId) error
 	DeleteSchemaRegistryApiKey(ctx context.Context, clusterId models.ClusterId, serviceAccountId models.ServiceAccountId) error
-	CreateServiceAccountRoleBinding(ctx context.Context, serviceAccount models.ServiceAccountId, clusterId models.ClusterId) error
+	CreateRoleBinding(ctx context.Context, serviceAccount models.ServiceAccountId, definition models.RoleBindingDefinition) (string, error)
+	EnsureRoleBinding(ctx context.Context, serviceAccount models.ServiceAccountId, definition models.RoleBindingDefinition) (string, error)
+	ListRoleBindings(ctx context.Context, serviceAccount models.ServiceAccountId, crnPattern string) ([]models.ConfluentRoleBinding, error)
+	DeleteRoleBinding(ctx context.Context, roleBindingId string) error
+	RevokeRoleBinding(ctx context.Context, serviceAccount models.ServiceAccountId, definition models.RoleBindingDefinition) error
 	CreateTopic(ctx context.Context, clusterId models.ClusterId, name string, partitions int, retention int64) error
 	DeleteTopic(ctx context.Context, clusterId models.ClusterId, topicName string) error
+	GetTopic(ctx context.Context, clusterId models.ClusterId, topicName string) (*models.KafkaTopic, error)
 	GetConfluentInternalUsers(ctx context.Context) ([]models.ConfluentInternalUser, error)
-	RegisterSchema(ctx context.Context, clusterId models.ClusterId, subject string, schema string, version int32) error
+	RegisterSchema(ctx context.Context, clusterId models.ClusterId, subject string, schema models.SchemaDefinition, version int32) error
+	LookupSchemaVersion(ctx context.Context, clusterId models.ClusterId, subject string, schema models.SchemaDefinition) (int32, error)
+	CheckSchemaCompatibility(ctx context.Context, clusterId models.ClusterId, subject string, schema models.SchemaDefinition) (*models.CompatibilityResult, error)
 	DeleteSchema(ctx context.Context, clusterId models.ClusterId, subject string, schema string, version string) error
+	DeleteSubject(ctx context.Context, clusterId models.ClusterId, subject string, permanent bool) error
+	DeleteSchemaVersion(ctx context.Context, clusterId models.ClusterId, subject string, version string, permanent bool) error
 	CountClusterApiKeys(ctx context.Context, serviceAccountId models.ServiceAccountId, clusterId models.ClusterId) (int, error)
 	CountSchemaRegistryApiKeys(ctx context.Context, serviceAccountId models.ServiceAccountId, clusterId models.ClusterId) (int, error)
 }
@@ -136,47 +158,140 @@ type listApiKeysResponse struct {
 	} `json:"data"`
 }
 
-type createRoleBindingResponse struct {
-	Id string `json:"id"`
+type roleBindingPayload struct {
+	Id         string `json:"id,omitempty"`
+	Principal  string `json:"principal"`
+	RoleName   string `json:"role_name"`
+	CrnPattern string `json:"crn_pattern"`
 }
 
-func (c *Client) ListSchemas(ctx context.Context, subjectPrefix string, clusterId models.ClusterId) ([]models.Schema, error) {
-	cluster, err := c.clusters.Get(clusterId)
+type listRoleBindingsResponse struct {
+	Metadata struct {
+		Next string `json:"next"`
+	} `json:"metadata"`
+	Data []roleBindingPayload `json:"data"`
+}
 
-	if err != nil {
+func (c *Client) ListSchemas(ctx context.Context, subjectPrefix string, clusterId models.ClusterId, offset int, limit int) ([]models.Schema, error) {
+	query := url.Values{}
+	if subjectPrefix != "" {
+		query.Set("subjectPrefix", subjectPrefix)
+	}
+	if offset > 0 {
+		query.Set("offset", strconv.Itoa(offset))
+	}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+
+	var schemas []models.Schema
+	if err := c.getFromSchemaRegistry(ctx, clusterId, "/schemas", query, &schemas); err != nil {
 		return nil, err
 	}
 
-	if len(cluster.SchemaRegistryApiEndpoint) == 0 {
-		return nil, ErrNoSchemaRegistry
+	return schemas, nil
+}
+
+func (c *Client) ListSubjects(ctx context.Context, clusterId models.ClusterId, subjectPrefix string) ([]string, error) {
+	query := url.Values{}
+	if subjectPrefix != "" {
+		query.Set("subjectPrefix", subjectPrefix)
 	}
 
-	url := fmt.Sprintf("%s/schemas", cluster.SchemaRegistryApiEndpoint)
+	var subjects []string
+	if err := c.getFromSchemaRegistry(ctx, clusterId, "/subjects", query, &subjects); err != nil {
+		return nil, err
+	}
 
-	/*
-		ignore subjectPrefix for now
-		if subjectPrefix != "" {
-		  url += "?subjectPrefix=" + subjectPrefix
-		}
-	*/
+	return subjects, nil
+}
 
-	response, err := c.get(ctx, url, cluster.SchemaRegistryApiKey)
+func (c *Client) ListSubjectVersions(ctx context.Context, clusterId models.ClusterId, subject string) ([]int, error) {
+	var versions []int
+	if err := c.getFromSchemaRegistry(ctx, clusterId, fmt.Sprintf("/subjects/%s/versions", url.PathEscape(subject)), nil, &versions); err != nil {
+		return nil, err
+	}
 
-	if err != nil {
+	return versions, nil
+}
+
+func (c *Client) GetSchemaVersion(ctx context.Context, clusterId models.ClusterId, subject string, version string) (*models.Schema, error) {
+	var schema models.Schema
+	if err := c.getFromSchemaRegistry(ctx, clusterId, fmt.Sprintf("/subjects/%s/versions/%s", url.PathEscape(subject), url.PathEscape(version)), nil, &schema); err != nil {
 		return nil, err
 	}
+
+	return &schema, nil
+}
+
+func (c *Client) GetCompatibilityLevel(ctx context.Context, clusterId models.ClusterId, subject string) (string, error) {
+	query := url.Values{}
+	query.Set("defaultToGlobal", "true")
+
+	var config compatibilityConfigResponse
+	if err := c.getFromSchemaRegistry(ctx, clusterId, fmt.Sprintf("/config/%s", url.PathEscape(subject)), query, &config); err != nil {
+		return "", err
+	}
+
+	return config.CompatibilityLevel, nil
+}
+
+type compatibilityConfigPayload struct {
+	Compatibility models.CompatibilityLevel `json:"compatibility"`
+}
+
+func (c *Client) SetCompatibilityLevel(ctx context.Context, clusterId models.ClusterId, subject string, level models.CompatibilityLevel) error {
+	cluster, err := c.clusters.Get(clusterId)
+	if err != nil {
+		return err
+	}
+
+	if cluster == nil || len(cluster.SchemaRegistryApiEndpoint) == 0 {
+		return ErrNoSchemaRegistry
+	}
+
+	url := fmt.Sprintf("%s/config/%s", cluster.SchemaRegistryApiEndpoint, url.PathEscape(subject))
+
+	payload, err := json.Marshal(compatibilityConfigPayload{Compatibility: level})
+	if err != nil {
+		return err
+	}
+
+	response, err := c.put(ctx, url, string(payload), cluster.SchemaRegistryApiKey)
+	if err != nil {
+		return err
+	}
 	defer response.Body.Close()
 
-	if response.StatusCode != http.StatusOK {
-		return nil, errors.New("failed to fetch schemas: " + response.Status)
+	return nil
+}
+
+type compatibilityConfigResponse struct {
+	CompatibilityLevel string `json:"compatibilityLevel"`
+}
+
+func (c *Client) getFromSchemaRegistry(ctx context.Context, clusterId models.ClusterId, path string, query url.Values, target any) error {
+	cluster, err := c.clusters.Get(clusterId)
+	if err != nil {
+		return err
 	}
 
-	var schemas []models.Schema
-	if err := json.NewDecoder(response.Body).Decode(&schemas); err != nil {
-		return nil, err
+	if len(cluster.SchemaRegistryApiEndpoint) == 0 {
+		return ErrNoSchemaRegistry
 	}
 
-	return schemas, nil
+	url := cluster.SchemaRegistryApiEndpoint + path
+	if len(query) > 0 {
+		url += "?" + query.Encode()
+	}
+
+	response, err := c.get(ctx, url, cluster.SchemaRegistryApiKey)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	return json.NewDecoder(response.Body).Decode(target)
 }
 
 func (c *Client) CreateServiceAccount(ctx context.Context, name string, description string) (models.ServiceAccountId, error) {
@@ -239,6 +354,14 @@ func (c *Client) post(ctx context.Context, url string, payload string, apiKey mo
 	return c.getResponseReader(request, payload)
 }
 
+func (c *Client) put(ctx context.Context, url string, payload string, apiKey models.ApiKey) (*http.Response, error) {
+	request, _ := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewBuffer([]byte(payload)))
+	request.Header.Set("Content-Type", "application/json")
+	request.SetBasicAuth(apiKey.Username, apiKey.Password)
+
+	return c.getResponseReader(request, payload)
+}
+
 func (c *Client) getResponseReader(request *http.Request, payload string) (*http.Response, error) {
 	url := request.URL.String()
 	start := time.Now()
@@ -297,6 +420,179 @@ func (c *Client) CreateACLEntry(ctx context.Context, clusterId models.ClusterId,
 	return nil
 }
 
+// aclConcurrency is the number of ACLs created at the same time on clusters without the batch endpoint.
+const aclConcurrency = 4
+
+type createAclRequest struct {
+	ResourceType string `json:"resource_type"`
+	ResourceName string `json:"resource_name"`
+	PatternType  string `json:"pattern_type"`
+	Principal    string `json:"principal"`
+	Host         string `json:"host"`
+	Operation    string `json:"operation"`
+	Permission   string `json:"permission"`
+}
+
+type createAclBatchRequest struct {
+	Data []createAclRequest `json:"data"`
+}
+
+// CreateACLEntries creates the ACLs with a single call to the batch endpoint. When the cluster has no batch endpoint,
+// the ACLs are created one by one, a few at a time, so that the results tell which of them failed. The results are
+// in the order of the entries.
+func (c *Client) CreateACLEntries(ctx context.Context, clusterId models.ClusterId, userAccountId models.UserAccountId, entries []models.AclDefinition) ([]models.AclCreationResult, error) {
+	cluster, err := c.clusters.Get(clusterId)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]models.AclCreationResult, len(entries))
+	for i, entry := range entries {
+		results[i] = models.AclCreationResult{AclDefinition: entry}
+	}
+	if len(entries) == 0 {
+		return results, nil
+	}
+
+	batch := createAclBatchRequest{}
+	for _, entry := range entries {
+		batch.Data = append(batch.Data, createAclRequest{
+			ResourceType: string(entry.ResourceType),
+			ResourceName: entry.ResourceName,
+			PatternType:  string(entry.PatternType),
+			Principal:    string(userAccountId),
+			Host:         "*",
+			Operation:    string(entry.OperationType),
+			Permission:   string(entry.PermissionType),
+		})
+	}
+
+	payload, err := json.Marshal(batch)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/kafka/v3/clusters/%s/acls:batch", cluster.AdminApiEndpoint, clusterId)
+
+	response, err := c.post(ctx, url, string(payload), cluster.AdminApiKey)
+	if err == nil {
+		response.Body.Close()
+		return results, nil
+	}
+
+	var clientError *ClientError
+	if !errors.As(err, &clientError) || !isBatchUnsupported(clientError.Status) {
+		return nil, err
+	}
+
+	c.logger.Debug("Batch creation of ACLs on cluster {ClusterId} failed with status code {StatusCode}, creating them one by one", string(clusterId), strconv.Itoa(clientError.Status))
+
+	g := errgroup.Group{}
+	g.SetLimit(aclConcurrency)
+
+	for i := range results {
+		g.Go(func() error {
+			results[i].Err = c.CreateACLEntry(ctx, clusterId, userAccountId, results[i].AclDefinition)
+			return nil
+		})
+	}
+
+	return results, g.Wait()
+}
+
+// isBatchUnsupported tells whether the status code of a batch call means the cluster has no batch endpoint.
+func isBatchUnsupported(statusCode int) bool {
+	return statusCode == http.StatusNotFound || statusCode == http.StatusMethodNotAllowed
+}
+
+// DeleteACLEntry removes the ACL matching the definition exactly, and succeeds when there is none.
+func (c *Client) DeleteACLEntry(ctx context.Context, clusterId models.ClusterId, userAccountId models.UserAccountId, entry models.AclDefinition) error {
+	filter := models.NewAclFilter(userAccountId, entry)
+
+	_, err := c.DeleteACLs(ctx, clusterId, filter)
+	return err
+}
+
+type aclResponse struct {
+	ResourceType string `json:"resource_type"`
+	ResourceName string `json:"resource_name"`
+	PatternType  string `json:"pattern_type"`
+	Principal    string `json:"principal"`
+	Host         string `json:"host"`
+	Operation    string `json:"operation"`
+	Permission   string `json:"permission"`
+}
+
+type listAclsResponse struct {
+	Data []aclResponse `json:"data"`
+}
+
+// ListACLs returns the ACLs on the cluster that match the filter.
+func (c *Client) ListACLs(ctx context.Context, clusterId models.ClusterId, filter models.AclFilter) ([]models.AclBinding, error) {
+	return c.acls(ctx, clusterId, filter, c.get)
+}
+
+// DeleteACLs removes the ACLs on the cluster that match the filter and returns them. The filter must name a
+// principal, so that a filter left empty by mistake cannot remove the ACLs of every service account.
+func (c *Client) DeleteACLs(ctx context.Context, clusterId models.ClusterId, filter models.AclFilter) ([]models.AclBinding, error) {
+	if filter.Principal == "" {
+		return nil, ErrAclFilterWithoutPrincipal
+	}
+
+	return c.acls(ctx, clusterId, filter, c.delete)
+}
+
+func (c *Client) acls(ctx context.Context, clusterId models.ClusterId, filter models.AclFilter, send func(context.Context, string, models.ApiKey) (*http.Response, error)) ([]models.AclBinding, error) {
+	cluster, err := c.clusters.Get(clusterId)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	setIfNotEmpty := func(key string, value string) {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	setIfNotEmpty("resource_type", string(filter.ResourceType))
+	setIfNotEmpty("resource_name", filter.ResourceName)
+	setIfNotEmpty("pattern_type", string(filter.PatternType))
+	setIfNotEmpty("principal", string(filter.Principal))
+	setIfNotEmpty("operation", string(filter.OperationType))
+	setIfNotEmpty("permission", string(filter.PermissionType))
+	query.Set("host", "*")
+
+	url := fmt.Sprintf("%s/kafka/v3/clusters/%s/acls?%s", cluster.AdminApiEndpoint, clusterId, query.Encode())
+
+	response, err := send(ctx, url, cluster.AdminApiKey)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	var acls listAclsResponse
+	if err := json.NewDecoder(response.Body).Decode(&acls); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	bindings := []models.AclBinding{}
+	for _, acl := range acls.Data {
+		bindings = append(bindings, models.AclBinding{
+			Principal: models.UserAccountId(acl.Principal),
+			Host:      acl.Host,
+			AclDefinition: models.AclDefinition{
+				ResourceType:   models.ResourceType(acl.ResourceType),
+				ResourceName:   acl.ResourceName,
+				PatternType:    models.PatternType(acl.PatternType),
+				OperationType:  models.OperationType(acl.Operation),
+				PermissionType: models.PermissionType(acl.Permission),
+			},
+		})
+	}
+
+	return bindings, nil
+}
+
 func (c *Client) getSchemaRegistryId(clusterId models.ClusterId) (models.SchemaRegistryId, error) {
 	cluster, err := c.clusters.Get(clusterId)
 	if err != nil {
@@ -438,40 +734,124 @@ func (c *Client) DeleteSchemaRegistryApiKey(ctx context.Context, clusterId model
 	return c.findResourceAndDeleteApiKey(ctx, serviceAccountId, resourceId)
 }
 
-func (c *Client) CreateServiceAccountRoleBinding(ctx context.Context, serviceAccount models.ServiceAccountId, clusterId models.ClusterId) error {
+// CreateRoleBinding binds a role to the service account and returns the id Confluent gave the role binding.
+func (c *Client) CreateRoleBinding(ctx context.Context, serviceAccount models.ServiceAccountId, definition models.RoleBindingDefinition) (string, error) {
+	url := c.cloudApiAccess.ApiEndpoint + "/iam/v2/role-bindings"
 
-	cluster, err := c.clusters.Get(clusterId)
+	payload, err := json.Marshal(roleBindingPayload{
+		Principal:  fmt.Sprintf("User:%s", serviceAccount),
+		RoleName:   string(definition.RoleName),
+		CrnPattern: definition.CrnPattern,
+	})
 	if err != nil {
-		return err
+		return "", err
+	}
+
+	response, err := c.post(ctx, url, string(payload), c.cloudApiAccess.ApiKey())
+	if err != nil {
+		return "", err
 	}
+	defer response.Body.Close()
 
-	if cluster.OrganizationId == "" ||
-		cluster.EnvironmentId == "" ||
-		cluster.SchemaRegistryId == "" {
-		return ErrMissingSchemaRegistryIds
+	created := roleBindingPayload{}
+	if err := json.NewDecoder(response.Body).Decode(&created); err != nil {
+		return "", err
 	}
 
-	url := c.cloudApiAccess.ApiEndpoint + "/iam/v2/role-bindings"
-	payload := fmt.Sprintf(`{
-		"principal": "User:%s",
-		"role_name": "DeveloperRead",
-		"crn_pattern": "crn://confluent.cloud/organization=%s/environment=%s/schema-registry=%s/subject=*"
-	}`, serviceAccount, cluster.OrganizationId, cluster.EnvironmentId, cluster.SchemaRegistryId)
+	return created.Id, nil
+}
 
-	response, err := c.post(ctx, url, payload, c.cloudApiAccess.ApiKey())
+// EnsureRoleBinding creates a role binding unless the service account already has it, and returns its id. Listing
+// first keeps a retried creation from binding the role twice.
+func (c *Client) EnsureRoleBinding(ctx context.Context, serviceAccount models.ServiceAccountId, definition models.RoleBindingDefinition) (string, error) {
+	existing, err := c.ListRoleBindings(ctx, serviceAccount, definition.CrnPattern)
 	if err != nil {
+		return "", err
+	}
+
+	for _, roleBinding := range existing {
+		if roleBinding.RoleBindingDefinition == definition {
+			return roleBinding.Id, nil
+		}
+	}
+
+	return c.CreateRoleBinding(ctx, serviceAccount, definition)
+}
+
+// ListRoleBindings returns the role bindings of the service account on the resources matching the pattern, following
+// the pages Confluent splits the list into.
+func (c *Client) ListRoleBindings(ctx context.Context, serviceAccount models.ServiceAccountId, crnPattern string) ([]models.ConfluentRoleBinding, error) {
+	query := url.Values{}
+	query.Set("principal", fmt.Sprintf("User:%s", serviceAccount))
+	query.Set("crn_pattern", crnPattern)
+
+	var roleBindings []models.ConfluentRoleBinding
+	for page := c.cloudApiAccess.ApiEndpoint + "/iam/v2/role-bindings?" + query.Encode(); page != ""; {
+		listed, err := c.listRoleBindingsPage(ctx, page)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, data := range listed.Data {
+			roleBindings = append(roleBindings, models.ConfluentRoleBinding{
+				Id:                    data.Id,
+				Principal:             data.Principal,
+				RoleBindingDefinition: models.RoleBindingDefinition{RoleName: models.RoleName(data.RoleName), CrnPattern: data.CrnPattern},
+			})
+		}
+
+		page = listed.Metadata.Next
+	}
+
+	return roleBindings, nil
+}
+
+func (c *Client) listRoleBindingsPage(ctx context.Context, url string) (*listRoleBindingsResponse, error) {
+	response, err := c.get(ctx, url, c.cloudApiAccess.ApiKey())
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	listed := &listRoleBindingsResponse{}
+	if err := json.NewDecoder(response.Body).Decode(listed); err != nil {
+		return nil, err
+	}
+
+	return listed, nil
+}
+
+// DeleteRoleBinding removes a role binding, which is already gone when Confluent does not know it.
+func (c *Client) DeleteRoleBinding(ctx context.Context, roleBindingId string) error {
+	url := fmt.Sprintf("%s/iam/v2/role-bindings/%s", c.cloudApiAccess.ApiEndpoint, roleBindingId)
+
+	response, err := c.delete(ctx, url, c.cloudApiAccess.ApiKey())
+	if err != nil {
+		var clientError *ClientError
+		if errors.As(err, &clientError) && clientError.Status == http.StatusNotFound {
+			return nil
+		}
 		return err
 	}
 	defer response.Body.Close()
 
+	return nil
+}
+
+// RevokeRoleBinding removes the role binding from the service account wherever Confluent lists it, whether or not the
+// gateway has recorded it.
+func (c *Client) RevokeRoleBinding(ctx context.Context, serviceAccount models.ServiceAccountId, definition models.RoleBindingDefinition) error {
+	existing, err := c.ListRoleBindings(ctx, serviceAccount, definition.CrnPattern)
 	if err != nil {
 		return err
 	}
 
-	roleBindingResponse := &createRoleBindingResponse{}
-	derr := json.NewDecoder(response.Body).Decode(roleBindingResponse)
-	if derr != nil {
-		return derr
+	for _, roleBinding := range existing {
+		if roleBinding.RoleBindingDefinition == definition {
+			if err := c.DeleteRoleBinding(ctx, roleBinding.Id); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
@@ -495,13 +875,12 @@ func (c *Client) CreateTopic(ctx context.Context, clusterId models.ClusterId, na
 	}`
 
 	response, err := c.post(ctx, url, payload, cluster.AdminApiKey)
-	defer response.Body.Close()
-
 	if err != nil {
-		// log
+		return err
 	}
+	defer response.Body.Close()
 
-	return err
+	return nil
 }
 
 func (c *Client) GetConfluentInternalUsers(ctx context.Context) ([]models.ConfluentInternalUser, error) {
@@ -546,6 +925,64 @@ func (c *Client) DeleteTopic(ctx context.Context, clusterId models.ClusterId, to
 	return err
 }
 
+type getTopicResponse struct {
+	TopicName         string `json:"topic_name"`
+	PartitionsCount   int    `json:"partitions_count"`
+	ReplicationFactor int    `json:"replication_factor"`
+}
+
+type listTopicConfigsResponse struct {
+	Data []struct {
+		Name  string  `json:"name"`
+		Value *string `json:"value"`
+	} `json:"data"`
+}
+
+func (c *Client) GetTopic(ctx context.Context, clusterId models.ClusterId, topicName string) (*models.KafkaTopic, error) {
+	cluster, err := c.clusters.Get(clusterId)
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("%s/kafka/v3/clusters/%s/topics/%s", cluster.AdminApiEndpoint, clusterId, topicName)
+
+	response, err := c.get(ctx, url, cluster.AdminApiKey)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	var topic getTopicResponse
+	if err := json.NewDecoder(response.Body).Decode(&topic); err != nil {
+		return nil, err
+	}
+
+	configsResponse, err := c.get(ctx, url+"/configs", cluster.AdminApiKey)
+	if err != nil {
+		return nil, err
+	}
+	defer configsResponse.Body.Close()
+
+	var configs listTopicConfigsResponse
+	if err := json.NewDecoder(configsResponse.Body).Decode(&configs); err != nil {
+		return nil, err
+	}
+
+	result := &models.KafkaTopic{
+		Name:              topic.TopicName,
+		PartitionsCount:   topic.PartitionsCount,
+		ReplicationFactor: topic.ReplicationFactor,
+		Configs:           map[string]string{},
+	}
+
+	for _, config := range configs.Data {
+		if config.Value != nil {
+			result.Configs[config.Name] = *config.Value
+		}
+	}
+
+	return result, nil
+}
+
 func (c *Client) delete(ctx context.Context, url string, apiKey models.ApiKey) (*http.Response, error) {
 	request, _ := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
 	request.Header.Set("Accept", "application/json")
@@ -555,12 +992,57 @@ func (c *Client) delete(ctx context.Context, url string, apiKey models.ApiKey) (
 }
 
 type schemaPayload struct {
-	Version    int32  `json:"version"`
-	SchemaType string `json:"schemaType"`
-	Schema     string `json:"schema"`
+	Version int32 `json:"version"`
+	models.SchemaDefinition
+}
+
+type compatibilityResponse struct {
+	IsCompatible bool     `json:"is_compatible"`
+	Messages     []string `json:"messages"`
+}
+
+// CheckSchemaCompatibility tests a schema against the latest version of a subject. A subject without any versions
+// yet accepts any schema.
+func (c *Client) CheckSchemaCompatibility(ctx context.Context, clusterId models.ClusterId, subject string, schema models.SchemaDefinition) (*models.CompatibilityResult, error) {
+	cluster, err := c.clusters.Get(clusterId)
+	if err != nil {
+		return nil, err
+	}
+
+	if cluster == nil || len(cluster.SchemaRegistryApiEndpoint) == 0 {
+		return nil, ErrNoSchemaRegistry
+	}
+
+	url := fmt.Sprintf("%s/compatibility/subjects/%s/versions/latest?verbose=true", cluster.SchemaRegistryApiEndpoint, url.PathEscape(subject))
+
+	payload, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.post(ctx, url, string(payload), cluster.SchemaRegistryApiKey)
+	if err != nil {
+		var clientError *ClientError
+		if errors.As(err, &clientError) && clientError.Status == http.StatusNotFound {
+			return &models.CompatibilityResult{Subject: subject, IsCompatible: true}, nil
+		}
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	var result compatibilityResponse
+	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	return &models.CompatibilityResult{
+		Subject:      subject,
+		IsCompatible: result.IsCompatible,
+		Messages:     result.Messages,
+	}, nil
 }
 
-func (c *Client) RegisterSchema(ctx context.Context, clusterId models.ClusterId, subject string, schema string, version int32) error {
+func (c *Client) RegisterSchema(ctx context.Context, clusterId models.ClusterId, subject string, schema models.SchemaDefinition, version int32) error {
 	cluster, err := c.clusters.Get(clusterId)
 
 	if err != nil {
@@ -571,12 +1053,11 @@ func (c *Client) RegisterSchema(ctx context.Context, clusterId models.ClusterId,
 		return ErrNoSchemaRegistry
 	}
 
-	url := fmt.Sprintf("%s/subjects/%s/versions", cluster.SchemaRegistryApiEndpoint, subject)
+	url := fmt.Sprintf("%s/subjects/%s/versions", cluster.SchemaRegistryApiEndpoint, url.PathEscape(subject))
 
 	payload, err := json.Marshal(schemaPayload{
-		Version:    version,
-		SchemaType: "JSON",
-		Schema:     schema,
+		Version:          version,
+		SchemaDefinition: schema,
 	})
 	if err != nil {
 		return err
@@ -597,6 +1078,43 @@ func (c *Client) RegisterSchema(ctx context.Context, clusterId models.ClusterId,
 	return err
 }
 
+type schemaLookupResponse struct {
+	Version int32 `json:"version"`
+}
+
+// LookupSchemaVersion returns the version of the subject the schema is registered as, which is assigned by the schema
+// registry and need not match the version of the message contract.
+func (c *Client) LookupSchemaVersion(ctx context.Context, clusterId models.ClusterId, subject string, schema models.SchemaDefinition) (int32, error) {
+	cluster, err := c.clusters.Get(clusterId)
+	if err != nil {
+		return 0, err
+	}
+
+	if cluster == nil || len(cluster.SchemaRegistryApiEndpoint) == 0 {
+		return 0, ErrNoSchemaRegistry
+	}
+
+	url := fmt.Sprintf("%s/subjects/%s", cluster.SchemaRegistryApiEndpoint, url.PathEscape(subject))
+
+	payload, err := json.Marshal(schema)
+	if err != nil {
+		return 0, err
+	}
+
+	response, err := c.post(ctx, url, string(payload), cluster.SchemaRegistryApiKey)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	var result schemaLookupResponse
+	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return 0, err
+	}
+
+	return result.Version, nil
+}
+
 func (c *Client) DeleteSchema(ctx context.Context, clusterId models.ClusterId, subject string, schema string, version string) error {
 
 	cluster, err := c.clusters.Get(clusterId)
@@ -616,6 +1134,54 @@ func (c *Client) DeleteSchema(ctx context.Context, clusterId models.ClusterId, s
 	return err
 }
 
+// DeleteSubject deletes all versions of a subject. A permanent delete soft deletes the subject first, as the schema
+// registry requires.
+func (c *Client) DeleteSubject(ctx context.Context, clusterId models.ClusterId, subject string, permanent bool) error {
+	return c.deleteFromSchemaRegistry(ctx, clusterId, fmt.Sprintf("/subjects/%s", url.PathEscape(subject)), permanent)
+}
+
+// DeleteSchemaVersion deletes a single version of a subject. A permanent delete soft deletes the version first, as
+// the schema registry requires.
+func (c *Client) DeleteSchemaVersion(ctx context.Context, clusterId models.ClusterId, subject string, version string, permanent bool) error {
+	return c.deleteFromSchemaRegistry(ctx, clusterId, fmt.Sprintf("/subjects/%s/versions/%s", url.PathEscape(subject), url.PathEscape(version)), permanent)
+}
+
+// deleteFromSchemaRegistry treats subjects and versions that are already gone as deleted, so a permanent delete can
+// follow an earlier soft delete.
+func (c *Client) deleteFromSchemaRegistry(ctx context.Context, clusterId models.ClusterId, path string, permanent bool) error {
+	cluster, err := c.clusters.Get(clusterId)
+	if err != nil {
+		return err
+	}
+
+	if cluster == nil || len(cluster.SchemaRegistryApiEndpoint) == 0 {
+		return ErrNoSchemaRegistry
+	}
+
+	endpoint := cluster.SchemaRegistryApiEndpoint + path
+
+	_, err = c.delete(ctx, endpoint, cluster.SchemaRegistryApiKey)
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+
+	if !permanent {
+		return nil
+	}
+
+	_, err = c.delete(ctx, endpoint+"?permanent=true", cluster.SchemaRegistryApiKey)
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+func isNotFound(err error) bool {
+	var clientError *ClientError
+	return errors.As(err, &clientError) && clientError.Status == http.StatusNotFound
+}
+
 type ClientError struct {
 	Url     string
 	Status  int
@@ -630,4 +1196,31 @@ func NewClientError(url string, status int, message string) error {
 	return &ClientError{Url: url, Status: status, Message: message}
 }
 
+// ClassifyError tells what caused a call to Confluent to fail, and whether the failure is permanent. Confluent
+// rejecting a request is permanent, apart from timeouts and throttling, while failing to reach Confluent or to be
+// served by it is transient and worth retrying.
+func ClassifyError(err error) (models.FailureCode, bool) {
+	var clientError *ClientError
+	if !errors.As(err, &clientError) {
+		return models.FailureCodeUnavailable, false
+	}
+
+	switch status := clientError.Status; {
+	case status == http.StatusRequestTimeout || status == http.StatusTooManyRequests || status >= 500 || status < 400:
+		return models.FailureCodeUnavailable, false
+	case status == http.StatusPaymentRequired || strings.Contains(strings.ToLower(clientError.Message), "quota"):
+		return models.FailureCodeQuotaExceeded, true
+	case status == http.StatusBadRequest || status == http.StatusUnprocessableEntity:
+		return models.FailureCodeInvalidConfig, true
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return models.FailureCodeNotAuthorized, true
+	case status == http.StatusNotFound:
+		return models.FailureCodeNotFound, true
+	case status == http.StatusConflict:
+		return models.FailureCodeAlreadyExists, true
+	default:
+		return models.FailureCodeRejected, true
+	}
+}
+
 var ErrNoSchemaRegistry = errors.New("no schema registry")
@@ -4,11 +4,14 @@ import (
 	"context"
 	b64 "encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/dfds/confluent-gateway/internal/models"
@@ -49,7 +52,7 @@ func TestListSchemas(t *testing.T) {
 						Mode:      "STRICT",
 						Type:      "type1",
 						Tags:      []string{"tag1", "tag2"},
-						Params:    models.Params{Property1: "p1", Property2: "p2"},
+						Params:    models.Params{"property1": "p1", "property2": "p2"},
 						Expr:      "expr1",
 						OnSuccess: "success",
 						OnFailure: "failure",
@@ -112,13 +115,260 @@ func TestListSchemas(t *testing.T) {
 	}
 
 	// Act
-	schemas, err := stubClient.ListSchemas(context.TODO(), "", stubClusterId)
+	schemas, err := stubClient.ListSchemas(context.TODO(), "", stubClusterId, 0, 0)
 
 	// Assert
 	assert.NoError(t, err)
 	assert.Equal(t, expectedSchemas, schemas)
 }
 
+func TestListSchemasFiltersBySubjectPrefixAndPaginates(t *testing.T) {
+	stubClusterId := models.ClusterId("test-cluster-id")
+	usedQuery := ""
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		usedQuery = r.URL.RawQuery
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	stubClient := Client{
+		logger:         logging.NilLogger(),
+		cloudApiAccess: CloudApiAccess{},
+		clusters:       &clustersStub{Cluster: models.Cluster{ClusterId: stubClusterId, SchemaRegistryApiEndpoint: server.URL}},
+	}
+
+	_, err := stubClient.ListSchemas(context.TODO(), "some-capability", stubClusterId, 20, 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "limit=10&offset=20&subjectPrefix=some-capability", usedQuery)
+}
+
+func TestSchemaRegistryBrowsingCallsExpectedEndpoints(t *testing.T) {
+	stubClusterId := models.ClusterId("test-cluster-id")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.RequestURI() {
+		case "/subjects?subjectPrefix=some-capability":
+			w.Write([]byte(`["some-capability.topic-value"]`))
+		case "/subjects/some-capability.topic-value/versions":
+			w.Write([]byte(`[1,2]`))
+		case "/subjects/some-capability.topic-value/versions/latest":
+			w.Write([]byte(`{"subject":"some-capability.topic-value","version":2,"id":7,"schema":"{}"}`))
+		case "/config/some-capability.topic-value?defaultToGlobal=true":
+			w.Write([]byte(`{"compatibilityLevel":"BACKWARD"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error_code":40401,"message":"Subject not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	stubClient := Client{
+		logger:         logging.NilLogger(),
+		cloudApiAccess: CloudApiAccess{},
+		clusters:       &clustersStub{Cluster: models.Cluster{ClusterId: stubClusterId, SchemaRegistryApiEndpoint: server.URL}},
+	}
+
+	subjects, err := stubClient.ListSubjects(context.TODO(), stubClusterId, "some-capability")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"some-capability.topic-value"}, subjects)
+
+	versions, err := stubClient.ListSubjectVersions(context.TODO(), stubClusterId, "some-capability.topic-value")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, versions)
+
+	schema, err := stubClient.GetSchemaVersion(context.TODO(), stubClusterId, "some-capability.topic-value", "latest")
+	assert.NoError(t, err)
+	assert.Equal(t, &models.Schema{Subject: "some-capability.topic-value", Version: 2, ID: 7, Schema: "{}"}, schema)
+
+	level, err := stubClient.GetCompatibilityLevel(context.TODO(), stubClusterId, "some-capability.topic-value")
+	assert.NoError(t, err)
+	assert.Equal(t, "BACKWARD", level)
+
+	_, err = stubClient.ListSubjectVersions(context.TODO(), stubClusterId, "unknown")
+	var clientError *ClientError
+	assert.ErrorAs(t, err, &clientError)
+	assert.Equal(t, http.StatusNotFound, clientError.Status)
+}
+
+func TestCheckSchemaCompatibility(t *testing.T) {
+	stubClusterId := models.ClusterId("test-cluster-id")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST request, got %s", r.Method)
+		}
+
+		switch r.URL.EscapedPath() {
+		case "/compatibility/subjects/existing-subject/versions/latest", "/compatibility/subjects/existing%2Fsubject/versions/latest":
+			w.Write([]byte(`{"is_compatible":false,"messages":["property removed"]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error_code":40401,"message":"Subject not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	stubClient := Client{
+		logger:         logging.NilLogger(),
+		cloudApiAccess: CloudApiAccess{},
+		clusters:       &clustersStub{Cluster: models.Cluster{ClusterId: stubClusterId, SchemaRegistryApiEndpoint: server.URL}},
+	}
+
+	result, err := stubClient.CheckSchemaCompatibility(context.TODO(), stubClusterId, "existing-subject", models.NewSchemaDefinition(models.SchemaTypeJson, "{}", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, &models.CompatibilityResult{Subject: "existing-subject", IsCompatible: false, Messages: []string{"property removed"}}, result)
+
+	result, err = stubClient.CheckSchemaCompatibility(context.TODO(), stubClusterId, "new-subject", models.NewSchemaDefinition(models.SchemaTypeJson, "{}", nil))
+	assert.NoError(t, err)
+	assert.True(t, result.IsCompatible)
+
+	result, err = stubClient.CheckSchemaCompatibility(context.TODO(), stubClusterId, "existing/subject", models.NewSchemaDefinition(models.SchemaTypeJson, "{}", nil))
+	assert.NoError(t, err)
+	assert.False(t, result.IsCompatible)
+}
+
+func TestRegisterSchemaSendsSchemaTypeAndReferences(t *testing.T) {
+	stubClusterId := models.ClusterId("test-cluster-id")
+	usedPayload := ""
+	usedPath := ""
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		usedPayload = string(body)
+		usedPath = r.URL.EscapedPath()
+		w.Write([]byte(`{"id":1}`))
+	}))
+	defer server.Close()
+
+	stubClient := Client{
+		logger:         logging.NilLogger(),
+		cloudApiAccess: CloudApiAccess{},
+		clusters:       &clustersStub{Cluster: models.Cluster{ClusterId: stubClusterId, SchemaRegistryApiEndpoint: server.URL}},
+	}
+
+	definition := models.NewSchemaDefinition(models.SchemaTypeAvro, `"string"`, []models.Reference{{Name: "other.avsc", Subject: "other-value", Version: 2}})
+
+	err := stubClient.RegisterSchema(context.TODO(), stubClusterId, "some/subject", definition, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "/subjects/some%2Fsubject/versions", usedPath)
+	assert.JSONEq(t, `{"version":1,"schemaType":"AVRO","schema":"\"string\"","references":[{"name":"other.avsc","subject":"other-value","version":2}]}`, usedPayload)
+}
+
+func TestLookupSchemaVersionReturnsVersionInSubject(t *testing.T) {
+	stubClusterId := models.ClusterId("test-cluster-id")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/subjects/some%2Fsubject", r.URL.EscapedPath())
+		w.Write([]byte(`{"subject":"some/subject","id":42,"version":3,"schema":"{}"}`))
+	}))
+	defer server.Close()
+
+	stubClient := Client{
+		logger:         logging.NilLogger(),
+		cloudApiAccess: CloudApiAccess{},
+		clusters:       &clustersStub{Cluster: models.Cluster{ClusterId: stubClusterId, SchemaRegistryApiEndpoint: server.URL}},
+	}
+
+	version, err := stubClient.LookupSchemaVersion(context.TODO(), stubClusterId, "some/subject", models.NewSchemaDefinition(models.SchemaTypeJson, "{}", nil))
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), version)
+}
+
+func TestRegisterSchemaSendsMetadataAndRuleSet(t *testing.T) {
+	stubClusterId := models.ClusterId("test-cluster-id")
+	usedPayload := ""
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		usedPayload = string(body)
+		w.Write([]byte(`{"id":1}`))
+	}))
+	defer server.Close()
+
+	stubClient := Client{
+		logger:         logging.NilLogger(),
+		cloudApiAccess: CloudApiAccess{},
+		clusters:       &clustersStub{Cluster: models.Cluster{ClusterId: stubClusterId, SchemaRegistryApiEndpoint: server.URL}},
+	}
+
+	metadata := &models.Metadata{Tags: map[string][]string{"email": {"PII"}}, Sensitive: []string{"email"}}
+	ruleSet := &models.RuleSet{DomainRules: []models.Rule{{Name: "checkEmail", Kind: "CONDITION", Mode: "WRITE", Type: "CEL_FIELD", Params: models.Params{"someKey": "someValue"}, Expr: "size(value) > 0"}}}
+	definition := models.NewSchemaDefinition(models.SchemaTypeJson, `{}`, nil).WithRules(metadata, ruleSet)
+
+	err := stubClient.RegisterSchema(context.TODO(), stubClusterId, "some-subject", definition, 1)
+
+	assert.NoError(t, err)
+
+	var payload map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(usedPayload), &payload))
+	assert.Equal(t, map[string]any{"tags": map[string]any{"email": []any{"PII"}}, "properties": nil, "sensitive": []any{"email"}}, payload["metadata"])
+	assert.Equal(t, "checkEmail", payload["ruleSet"].(map[string]any)["domainRules"].([]any)[0].(map[string]any)["name"])
+	assert.Equal(t, map[string]any{"someKey": "someValue"}, payload["ruleSet"].(map[string]any)["domainRules"].([]any)[0].(map[string]any)["params"])
+}
+
+func TestSetCompatibilityLevelSendsExpectedPayload(t *testing.T) {
+	stubClusterId := models.ClusterId("test-cluster-id")
+	usedMethod := ""
+	usedPath := ""
+	usedPayload := ""
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		usedMethod = r.Method
+		usedPath = r.URL.EscapedPath()
+		usedPayload = string(body)
+		w.Write([]byte(`{"compatibility":"FULL_TRANSITIVE"}`))
+	}))
+	defer server.Close()
+
+	stubClient := Client{
+		logger:         logging.NilLogger(),
+		cloudApiAccess: CloudApiAccess{},
+		clusters:       &clustersStub{Cluster: models.Cluster{ClusterId: stubClusterId, SchemaRegistryApiEndpoint: server.URL}},
+	}
+
+	err := stubClient.SetCompatibilityLevel(context.TODO(), stubClusterId, "some/subject", models.CompatibilityFullTransitive)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodPut, usedMethod)
+	assert.Equal(t, "/config/some%2Fsubject", usedPath)
+	assert.JSONEq(t, `{"compatibility":"FULL_TRANSITIVE"}`, usedPayload)
+}
+
+func TestDeleteSubjectSoftDeletesBeforePermanentDelete(t *testing.T) {
+	stubClusterId := models.ClusterId("test-cluster-id")
+	var usedRequests []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		usedRequests = append(usedRequests, r.Method+" "+r.URL.RequestURI())
+		if r.URL.Query().Get("permanent") == "" {
+			// already soft deleted
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error_code":40404,"message":"Subject 'some-subject' was soft deleted."}`))
+			return
+		}
+		w.Write([]byte(`[1,2]`))
+	}))
+	defer server.Close()
+
+	stubClient := Client{
+		logger:         logging.NilLogger(),
+		cloudApiAccess: CloudApiAccess{},
+		clusters:       &clustersStub{Cluster: models.Cluster{ClusterId: stubClusterId, SchemaRegistryApiEndpoint: server.URL}},
+	}
+
+	err := stubClient.DeleteSubject(context.TODO(), stubClusterId, "some-subject", true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"DELETE /subjects/some-subject", "DELETE /subjects/some-subject?permanent=true"}, usedRequests)
+}
+
 func TestCreateTopicCallsExpectedClusterAdminEndpoint(t *testing.T) {
 	tests := []string{"foo", "bar", "baz", "qux"}
 
@@ -654,6 +904,370 @@ func TestCreateACLEntryUsesExpectedApiKey(t *testing.T) {
 	assert.Equal(t, expected, usedApiKey)
 }
 
+func TestDeleteACLEntrySendsExpectedFilter(t *testing.T) {
+	usedMethod, usedEndpointUrl := "", ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		usedMethod = r.Method
+		usedEndpointUrl = r.RequestURI
+	}))
+	defer server.Close()
+
+	stubCluster := models.Cluster{
+		ClusterId:        models.ClusterId("foo"),
+		AdminApiEndpoint: server.URL,
+	}
+
+	stubClient := Client{
+		logger:         logging.NilLogger(),
+		cloudApiAccess: CloudApiAccess{},
+		clusters:       &clustersStub{Cluster: stubCluster},
+	}
+
+	// act
+	err := stubClient.DeleteACLEntry(context.TODO(), stubCluster.ClusterId, someUserAccountId, models.AclDefinition{
+		ResourceType:   models.ResourceTypeTopic,
+		ResourceName:   "pub.",
+		PatternType:    models.PatternTypePrefix,
+		OperationType:  models.OperationTypeRead,
+		PermissionType: models.PermissionTypeAllow,
+	})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodDelete, usedMethod)
+	assert.Equal(t, "/kafka/v3/clusters/foo/acls?host=%2A&operation=READ&pattern_type=PREFIXED&permission=ALLOW&principal=User%3A1234&resource_name=pub.&resource_type=TOPIC", usedEndpointUrl)
+}
+
+func TestCreateACLEntriesUsesBatchEndpoint(t *testing.T) {
+	usedEndpoints, usedBody := []string{}, ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		usedEndpoints = append(usedEndpoints, r.RequestURI)
+		body, _ := io.ReadAll(r.Body)
+		usedBody = string(body)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	stubCluster := models.Cluster{
+		ClusterId:        models.ClusterId("foo"),
+		AdminApiEndpoint: server.URL,
+	}
+
+	stubClient := Client{
+		logger:         logging.NilLogger(),
+		cloudApiAccess: CloudApiAccess{},
+		clusters:       &clustersStub{Cluster: stubCluster},
+	}
+
+	entries := models.CreateAclDefinitions("some-cap")[:2]
+
+	// act
+	results, err := stubClient.CreateACLEntries(context.TODO(), stubCluster.ClusterId, someUserAccountId, entries)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/kafka/v3/clusters/foo/acls:batch"}, usedEndpoints)
+	assert.Contains(t, usedBody, `"principal":"User:1234"`)
+	assert.Equal(t, []models.AclCreationResult{{AclDefinition: entries[0]}, {AclDefinition: entries[1]}}, results)
+}
+
+func TestCreateACLEntriesFallsBackToSingleEntries(t *testing.T) {
+	var mu sync.Mutex
+	singleCalls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, ":batch") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		mu.Lock()
+		singleCalls++
+		mu.Unlock()
+
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), `"GROUP"`) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	stubCluster := models.Cluster{
+		ClusterId:        models.ClusterId("foo"),
+		AdminApiEndpoint: server.URL,
+	}
+
+	stubClient := Client{
+		logger:         logging.NilLogger(),
+		cloudApiAccess: CloudApiAccess{},
+		clusters:       &clustersStub{Cluster: stubCluster},
+	}
+
+	entries := models.CreateAclDefinitions("some-cap")
+
+	// act
+	results, err := stubClient.CreateACLEntries(context.TODO(), stubCluster.ClusterId, someUserAccountId, entries)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, len(entries), singleCalls)
+	for i, result := range results {
+		assert.Equal(t, entries[i], result.AclDefinition)
+		if entries[i].ResourceType == models.ResourceTypeGroup {
+			assert.Error(t, result.Err)
+		} else {
+			assert.NoError(t, result.Err)
+		}
+	}
+}
+
+func TestCreateACLEntriesReturnsRejectedBatch(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	stubCluster := models.Cluster{
+		ClusterId:        models.ClusterId("foo"),
+		AdminApiEndpoint: server.URL,
+	}
+
+	stubClient := Client{
+		logger:         logging.NilLogger(),
+		cloudApiAccess: CloudApiAccess{},
+		clusters:       &clustersStub{Cluster: stubCluster},
+	}
+
+	// act
+	_, err := stubClient.CreateACLEntries(context.TODO(), stubCluster.ClusterId, someUserAccountId, models.CreateAclDefinitions("some-cap"))
+
+	// assert
+	var clientError *ClientError
+	assert.ErrorAs(t, err, &clientError)
+	assert.Equal(t, http.StatusBadRequest, clientError.Status)
+	assert.Equal(t, []string{"POST /kafka/v3/clusters/foo/acls:batch"}, requests)
+}
+
+func TestListACLsSendsFilterAndReadsAcls(t *testing.T) {
+	usedMethod, usedEndpointUrl := "", ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		usedMethod = r.Method
+		usedEndpointUrl = r.RequestURI
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{
+  "kind": "KafkaAclList",
+  "data": [
+    {
+      "kind": "KafkaAcl",
+      "cluster_id": "foo",
+      "resource_type": "TOPIC",
+      "resource_name": "pub.",
+      "pattern_type": "PREFIXED",
+      "principal": "User:1234",
+      "host": "*",
+      "operation": "READ",
+      "permission": "ALLOW"
+    }
+  ]
+}`))
+	}))
+	defer server.Close()
+
+	stubCluster := models.Cluster{
+		ClusterId:        models.ClusterId("foo"),
+		AdminApiEndpoint: server.URL,
+	}
+
+	stubClient := Client{
+		logger:         logging.NilLogger(),
+		cloudApiAccess: CloudApiAccess{},
+		clusters:       &clustersStub{Cluster: stubCluster},
+	}
+
+	// act
+	acls, err := stubClient.ListACLs(context.TODO(), stubCluster.ClusterId, models.AclFilter{Principal: someUserAccountId, ResourceType: models.ResourceTypeTopic})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodGet, usedMethod)
+	assert.Equal(t, "/kafka/v3/clusters/foo/acls?host=%2A&principal=User%3A1234&resource_type=TOPIC", usedEndpointUrl)
+	assert.Equal(t, []models.AclBinding{{
+		Principal: someUserAccountId,
+		Host:      "*",
+		AclDefinition: models.AclDefinition{
+			ResourceType:   models.ResourceTypeTopic,
+			ResourceName:   "pub.",
+			PatternType:    models.PatternTypePrefix,
+			OperationType:  models.OperationTypeRead,
+			PermissionType: models.PermissionTypeAllow,
+		},
+	}}, acls)
+}
+
+func TestEnsureRoleBindingCreatesMissingRoleBinding(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.RequestURI)
+		if r.Method == http.MethodGet {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"data": [{"id": "rb-1", "principal": "User:sa-1", "role_name": "DeveloperRead", "crn_pattern": "crn://some/topic=foo*"}]}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": "rb-2", "principal": "User:sa-1", "role_name": "DeveloperWrite", "crn_pattern": "crn://some/topic=foo*"}`))
+	}))
+	defer server.Close()
+
+	stubClient := Client{
+		logger:         logging.NilLogger(),
+		cloudApiAccess: CloudApiAccess{ApiEndpoint: server.URL},
+	}
+
+	// act
+	existing, err := stubClient.EnsureRoleBinding(context.TODO(), "sa-1", models.RoleBindingDefinition{RoleName: models.RoleNameDeveloperRead, CrnPattern: "crn://some/topic=foo*"})
+	assert.NoError(t, err)
+	created, err := stubClient.EnsureRoleBinding(context.TODO(), "sa-1", models.RoleBindingDefinition{RoleName: models.RoleNameDeveloperWrite, CrnPattern: "crn://some/topic=foo*"})
+	assert.NoError(t, err)
+
+	// assert
+	assert.Equal(t, "rb-1", existing)
+	assert.Equal(t, "rb-2", created)
+	assert.Equal(t, []string{
+		"GET /iam/v2/role-bindings?crn_pattern=crn%3A%2F%2Fsome%2Ftopic%3Dfoo%2A&principal=User%3Asa-1",
+		"GET /iam/v2/role-bindings?crn_pattern=crn%3A%2F%2Fsome%2Ftopic%3Dfoo%2A&principal=User%3Asa-1",
+		"POST /iam/v2/role-bindings",
+	}, requests)
+}
+
+func TestListRoleBindingsFollowsPages(t *testing.T) {
+	var requests []string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.RequestURI)
+		w.WriteHeader(http.StatusOK)
+		if r.URL.Query().Get("page_token") == "" {
+			w.Write([]byte(`{"metadata": {"next": "` + server.URL + `/iam/v2/role-bindings?page_token=2"}, "data": [{"id": "rb-1", "principal": "User:sa-1", "role_name": "DeveloperRead", "crn_pattern": "crn://some/topic=foo*"}]}`))
+			return
+		}
+		w.Write([]byte(`{"metadata": {}, "data": [{"id": "rb-2", "principal": "User:sa-1", "role_name": "DeveloperWrite", "crn_pattern": "crn://some/topic=foo*"}]}`))
+	}))
+	defer server.Close()
+
+	stubClient := Client{
+		logger:         logging.NilLogger(),
+		cloudApiAccess: CloudApiAccess{ApiEndpoint: server.URL},
+	}
+
+	// act
+	roleBindings, err := stubClient.ListRoleBindings(context.TODO(), "sa-1", "crn://some/topic=foo*")
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, []models.ConfluentRoleBinding{
+		{Id: "rb-1", Principal: "User:sa-1", RoleBindingDefinition: models.RoleBindingDefinition{RoleName: models.RoleNameDeveloperRead, CrnPattern: "crn://some/topic=foo*"}},
+		{Id: "rb-2", Principal: "User:sa-1", RoleBindingDefinition: models.RoleBindingDefinition{RoleName: models.RoleNameDeveloperWrite, CrnPattern: "crn://some/topic=foo*"}},
+	}, roleBindings)
+	assert.Equal(t, []string{
+		"GET /iam/v2/role-bindings?crn_pattern=crn%3A%2F%2Fsome%2Ftopic%3Dfoo%2A&principal=User%3Asa-1",
+		"GET /iam/v2/role-bindings?page_token=2",
+	}, requests)
+}
+
+func TestDeleteRoleBindingIgnoresMissingRoleBinding(t *testing.T) {
+	usedEndpointUrl := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		usedEndpointUrl = r.RequestURI
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	stubClient := Client{
+		logger:         logging.NilLogger(),
+		cloudApiAccess: CloudApiAccess{ApiEndpoint: server.URL},
+	}
+
+	// act
+	err := stubClient.DeleteRoleBinding(context.TODO(), "rb-1")
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "/iam/v2/role-bindings/rb-1", usedEndpointUrl)
+}
+
+func TestRevokeRoleBindingDeletesMatchingRoleBindings(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.RequestURI)
+		if r.Method == http.MethodGet {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"data": [
+				{"id": "rb-1", "principal": "User:sa-1", "role_name": "DeveloperRead", "crn_pattern": "crn://some/subject=*"},
+				{"id": "rb-2", "principal": "User:sa-1", "role_name": "DeveloperWrite", "crn_pattern": "crn://some/subject=*"}
+			]}`))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	stubClient := Client{
+		logger:         logging.NilLogger(),
+		cloudApiAccess: CloudApiAccess{ApiEndpoint: server.URL},
+	}
+
+	// act
+	err := stubClient.RevokeRoleBinding(context.TODO(), "sa-1", models.RoleBindingDefinition{RoleName: models.RoleNameDeveloperRead, CrnPattern: "crn://some/subject=*"})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"GET /iam/v2/role-bindings?crn_pattern=crn%3A%2F%2Fsome%2Fsubject%3D%2A&principal=User%3Asa-1",
+		"DELETE /iam/v2/role-bindings/rb-1",
+	}, requests)
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		wantCode      models.FailureCode
+		wantPermanent bool
+	}{
+		{name: "not reached", err: errors.New("connection refused"), wantCode: models.FailureCodeUnavailable},
+		{name: "server error", err: NewClientError("some-url", http.StatusBadGateway, ""), wantCode: models.FailureCodeUnavailable},
+		{name: "throttled", err: NewClientError("some-url", http.StatusTooManyRequests, ""), wantCode: models.FailureCodeUnavailable},
+		{name: "invalid config", err: NewClientError("some-url", http.StatusBadRequest, "invalid retention.ms"), wantCode: models.FailureCodeInvalidConfig, wantPermanent: true},
+		{name: "quota exceeded", err: NewClientError("some-url", http.StatusBadRequest, "partition quota exceeded"), wantCode: models.FailureCodeQuotaExceeded, wantPermanent: true},
+		{name: "already exists", err: fmt.Errorf("wrapped: %w", NewClientError("some-url", http.StatusConflict, "")), wantCode: models.FailureCodeAlreadyExists, wantPermanent: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, permanent := ClassifyError(tt.err)
+
+			assert.Equal(t, tt.wantCode, code)
+			assert.Equal(t, tt.wantPermanent, permanent)
+		})
+	}
+}
+
+func TestDeleteACLsRequiresPrincipal(t *testing.T) {
+	stubClient := Client{
+		logger:         logging.NilLogger(),
+		cloudApiAccess: CloudApiAccess{},
+		clusters:       &clustersStub{Cluster: models.Cluster{ClusterId: "foo"}},
+	}
+
+	// act
+	_, err := stubClient.DeleteACLs(context.TODO(), "foo", models.AclFilter{ResourceType: models.ResourceTypeTopic})
+
+	// assert
+	assert.ErrorIs(t, err, ErrAclFilterWithoutPrincipal)
+}
+
 // ---------------------------------------------------------------------------------------------------------
 
 func TestGetUsers(t *testing.T) {
@@ -711,6 +1325,68 @@ func TestGetUsers(t *testing.T) {
 
 // ---------------------------------------------------------------------------------------------------------
 
+func TestGetTopicMergesTopicAndConfigs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/kafka/v3/clusters/dummy/topics/foo":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"topic_name": "foo", "partitions_count": 3, "replication_factor": 3}`))
+		case "/kafka/v3/clusters/dummy/topics/foo/configs":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"data": [{"name": "retention.ms", "value": "604800000"}, {"name": "cleanup.policy", "value": "delete"}, {"name": "unset", "value": null}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	defer server.Close()
+
+	stubClient := Client{
+		logger:         logging.NilLogger(),
+		cloudApiAccess: CloudApiAccess{},
+		clusters:       &clustersStub{Cluster: models.Cluster{ClusterId: "dummy", AdminApiEndpoint: server.URL}},
+	}
+
+	// act
+	topic, err := stubClient.GetTopic(context.TODO(), "dummy", "foo")
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, &models.KafkaTopic{
+		Name:              "foo",
+		PartitionsCount:   3,
+		ReplicationFactor: 3,
+		Configs: map[string]string{
+			"retention.ms":   "604800000",
+			"cleanup.policy": "delete",
+		},
+	}, topic)
+}
+
+func TestGetTopicReturnsClientErrorWhenNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+
+	defer server.Close()
+
+	stubClient := Client{
+		logger:         logging.NilLogger(),
+		cloudApiAccess: CloudApiAccess{},
+		clusters:       &clustersStub{Cluster: models.Cluster{ClusterId: "dummy", AdminApiEndpoint: server.URL}},
+	}
+
+	// act
+	_, err := stubClient.GetTopic(context.TODO(), "dummy", "foo")
+
+	// assert
+	var clientError *ClientError
+	assert.ErrorAs(t, err, &clientError)
+	assert.Equal(t, http.StatusNotFound, clientError.Status)
+}
+
+// ---------------------------------------------------------------------------------------------------------
+
 type clustersStub struct {
 	Cluster models.Cluster
 }
@@ -65,3 +65,19 @@ func (c *StepContext) RaiseTopicProvisionedEvent() error {
 	}
 	return c.outbox.Produce(event)
 }
+
+func (c *StepContext) MarkAsFailed() {
+	c.state.MarkAsFailed()
+}
+
+func (c *StepContext) RaiseTopicProvisioningFailedEvent(code models.FailureCode, reason string) error {
+	event := &TopicProvisioningFailed{
+		TopicId:      c.state.TopicId,
+		CapabilityId: string(c.state.CapabilityId),
+		ClusterId:    string(c.state.ClusterId),
+		TopicName:    c.state.TopicName,
+		Code:         code,
+		Reason:       reason,
+	}
+	return c.outbox.Produce(event)
+}
@@ -1,5 +1,7 @@
 package create
 
+import "github.com/dfds/confluent-gateway/internal/models"
+
 type TopicRequested struct {
 	TopicId          string `json:"topicId"`          // V1
 	CapabilityRootId string `json:"capabilityRootId"` // V1
@@ -68,3 +70,16 @@ type TopicProvisioningBegun struct {
 func (t *TopicProvisioningBegun) PartitionKey() string {
 	return t.TopicId
 }
+
+type TopicProvisioningFailed struct {
+	TopicId      string             `json:"topicId"`
+	CapabilityId string             `json:"capabilityRootId"`
+	ClusterId    string             `json:"clusterId"`
+	TopicName    string             `json:"topicName"`
+	Code         models.FailureCode `json:"code"`
+	Reason       string             `json:"reason"`
+}
+
+func (t *TopicProvisioningFailed) PartitionKey() string {
+	return t.TopicId
+}
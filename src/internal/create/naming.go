@@ -0,0 +1,73 @@
+package create
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/dfds/confluent-gateway/internal/models"
+)
+
+var ErrTopicNameRejected = errors.New("topic name rejected")
+
+// maxTopicNameLength is the longest topic name Kafka accepts.
+const maxTopicNameLength = 249
+
+var defaultAllowedCharacters = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
+
+// NamingPolicy is the set of rules a topic name is checked against before the topic is provisioned.
+type NamingPolicy struct {
+	// RequireCapabilityPrefix requires the name to start with "<capability>." or "pub.<capability>.", which are the
+	// prefixes the ACLs of the capability grant access to.
+	RequireCapabilityPrefix bool
+	AllowedCharacters       *regexp.Regexp
+	MaxLength               int
+	// ReservedNames are rejected both as the whole topic name and as the name following the capability prefix.
+	ReservedNames []string
+}
+
+func DefaultNamingPolicy() NamingPolicy {
+	return NamingPolicy{
+		RequireCapabilityPrefix: true,
+		AllowedCharacters:       defaultAllowedCharacters,
+		MaxLength:               maxTopicNameLength,
+	}
+}
+
+// Validate returns ErrTopicNameRejected, with the reason, when the topic name breaks a rule of the policy.
+func (p NamingPolicy) Validate(capabilityId models.CapabilityId, topicName string) error {
+	if topicName == "" {
+		return fmt.Errorf("%w: topic name is empty", ErrTopicNameRejected)
+	}
+
+	if p.MaxLength > 0 && len(topicName) > p.MaxLength {
+		return fmt.Errorf("%w: %q is longer than %d characters", ErrTopicNameRejected, topicName, p.MaxLength)
+	}
+
+	if p.AllowedCharacters != nil && !p.AllowedCharacters.MatchString(topicName) {
+		return fmt.Errorf("%w: %q must match %s", ErrTopicNameRejected, topicName, p.AllowedCharacters)
+	}
+
+	name, hasPrefix := trimCapabilityPrefix(capabilityId, topicName)
+	if p.RequireCapabilityPrefix && !hasPrefix {
+		return fmt.Errorf("%w: %q must start with %q or %q", ErrTopicNameRejected, topicName, string(capabilityId)+".", "pub."+string(capabilityId)+".")
+	}
+
+	for _, reserved := range p.ReservedNames {
+		if strings.EqualFold(topicName, reserved) || (hasPrefix && strings.EqualFold(name, reserved)) {
+			return fmt.Errorf("%w: %q is a reserved name", ErrTopicNameRejected, topicName)
+		}
+	}
+
+	return nil
+}
+
+func trimCapabilityPrefix(capabilityId models.CapabilityId, topicName string) (string, bool) {
+	for _, prefix := range []string{string(capabilityId) + ".", "pub." + string(capabilityId) + "."} {
+		if name, found := strings.CutPrefix(topicName, prefix); found && name != "" {
+			return name, true
+		}
+	}
+	return topicName, false
+}
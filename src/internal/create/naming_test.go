@@ -0,0 +1,46 @@
+package create
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamingPolicy_Validate(t *testing.T) {
+	policy := DefaultNamingPolicy()
+	policy.ReservedNames = []string{"test"}
+
+	tests := []struct {
+		name      string
+		topicName string
+		wantErr   assert.ErrorAssertionFunc
+	}{
+		{name: "private topic", topicName: "some-capability-id.orders", wantErr: assert.NoError},
+		{name: "public topic", topicName: "pub.some-capability-id.orders", wantErr: assert.NoError},
+		{name: "empty", topicName: "", wantErr: assert.Error},
+		{name: "missing prefix", topicName: "orders", wantErr: assert.Error},
+		{name: "prefix of another capability", topicName: "some-capability-id-2.orders", wantErr: assert.Error},
+		{name: "only prefix", topicName: "some-capability-id.", wantErr: assert.Error},
+		{name: "not allowed characters", topicName: "some-capability-id.ord/ers", wantErr: assert.Error},
+		{name: "too long", topicName: "some-capability-id." + strings.Repeat("a", 231), wantErr: assert.Error},
+		{name: "reserved", topicName: "some-capability-id.TEST", wantErr: assert.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Validate(someCapabilityId, tt.topicName)
+
+			tt.wantErr(t, err)
+			if err != nil {
+				assert.ErrorIs(t, err, ErrTopicNameRejected)
+			}
+		})
+	}
+}
+
+func TestNamingPolicy_ValidateWithoutPrefix(t *testing.T) {
+	policy := DefaultNamingPolicy()
+	policy.RequireCapabilityPrefix = false
+
+	assert.NoError(t, policy.Validate(someCapabilityId, "orders"))
+}
@@ -4,27 +4,39 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/dfds/confluent-gateway/internal/confluent"
 	"github.com/dfds/confluent-gateway/internal/models"
 	. "github.com/dfds/confluent-gateway/internal/process"
 	"github.com/dfds/confluent-gateway/internal/storage"
 	"github.com/dfds/confluent-gateway/logging"
+	uuid "github.com/satori/go.uuid"
+	"strings"
+	"time"
 )
 
 var ErrMissingServiceAccount = errors.New("no service account for capability to provision topic")
 
+// ResumableProcess is a process that can also be continued from a state it left incomplete.
+type ResumableProcess interface {
+	Process
+	Resume(ctx context.Context, processId uuid.UUID) error
+}
+
 type process struct {
 	logger    logging.Logger
 	database  models.Database
 	confluent Confluent
 	factory   OutboxFactory
+	policy    NamingPolicy
 }
 
-func NewProcess(logger logging.Logger, database models.Database, confluent Confluent, factory OutboxFactory) Process {
+func NewProcess(logger logging.Logger, database models.Database, confluent Confluent, factory OutboxFactory, policy NamingPolicy) ResumableProcess {
 	return &process{
 		logger:    logger,
 		database:  database,
 		confluent: confluent,
 		factory:   factory,
+		policy:    policy,
 	}
 }
 
@@ -44,15 +56,53 @@ func (p *process) Process(ctx context.Context, input ProcessInput) error {
 			// topic already exists => skip
 			return nil
 		}
+		if errors.Is(err, ErrTopicNameRejected) {
+			// rejection has been raised => skip
+			return nil
+		}
 
 		return err
 	}
 
+	return p.run(ctx, session, state)
+}
+
+// Resume continues the process from the state it was left in.
+func (p *process) Resume(ctx context.Context, processId uuid.UUID) error {
+	session := p.database.NewSession(ctx)
+
+	state := &models.CreateProcess{Id: processId}
+	if err := session.Transaction(func(tx models.Transaction) error { return tx.LockCreateProcessState(state) }); err != nil {
+		return err
+	}
+
+	if state.IsCompleted() || state.IsFailed() {
+		// finished in the meantime => skip
+		return nil
+	}
+
+	return p.run(ctx, session, state)
+}
+
+func (p *process) run(ctx context.Context, session models.Session, state *models.CreateProcess) error {
+	// the steps start over from the locked process state on every attempt, so failing calls to Confluent can safely be
+	// retried
+	retry := WithRetry(3, ExponentialBackoff(time.Second, 5*time.Second))
+
 	return PrepareSteps[*StepContext]().
-		Step(ensureHasValidServiceAccount).
-		Step(ensureTopicIsCreated).
+		Step(ensureHasValidServiceAccount, retry).
+		Step(ensureTopicIsCreated, retry).
+		RecordTo(NewStepRecorder(session, "create-topic", state.Id.String())).
 		Run(func(step func(*StepContext) error) error {
 			return session.Transaction(func(tx models.Transaction) error {
+				// another run of the process waits for this step and continues from its outcome
+				if err := tx.LockCreateProcessState(state); err != nil {
+					return err
+				}
+				if state.IsCompleted() || state.IsFailed() {
+					return nil
+				}
+
 				stepContext := p.getStepContext(ctx, tx, state)
 
 				err := step(stepContext)
@@ -67,15 +117,22 @@ func (p *process) Process(ctx context.Context, input ProcessInput) error {
 
 func (p *process) prepareProcessState(session models.Session, input ProcessInput) (*models.CreateProcess, error) {
 	var s *models.CreateProcess
+	var rejection error
 
 	err := session.Transaction(func(tx models.Transaction) error {
 		outbox := p.factory(tx)
 
 		if err := ensureNewTopic(tx, input); err != nil {
-			p.logger.Warning("{Topic} on {Cluster} for {Capability} already exists", input.Topic.Name, string(input.CapabilityId), string(input.ClusterId))
+			p.logger.Warning("{Topic} on {Cluster} for {Capability} already exists", input.Topic.Name, string(input.ClusterId), string(input.CapabilityId))
 			return err
 		}
 
+		if rejection = p.policy.Validate(input.CapabilityId, input.Topic.Name); rejection != nil {
+			p.logger.Warning("{Topic} on {Cluster} for {Capability} is rejected: {Reason}", input.Topic.Name, string(input.ClusterId), string(input.CapabilityId), rejection.Error())
+			// the transaction is committed to keep the rejection in the outbox
+			return rejectTopic(tx, outbox, input, rejection)
+		}
+
 		state, err := getOrCreateProcessState(tx, outbox, input)
 		if err != nil {
 			return err
@@ -86,6 +143,10 @@ func (p *process) prepareProcessState(session models.Session, input ProcessInput
 		return nil
 	})
 
+	if err == nil && rejection != nil {
+		return nil, rejection
+	}
+
 	return s, err
 }
 
@@ -118,7 +179,7 @@ func getOrCreateProcessState(repo stateRepository, outbox Outbox, input ProcessI
 		return nil, err
 	}
 
-	if state != nil && !state.IsCompleted() {
+	if state != nil && !state.IsCompleted() && !state.IsFailed() {
 		// is process is unfinished => continue
 		fmt.Println("Process is unfinished, continuing")
 		return state, nil
@@ -144,6 +205,35 @@ func getOrCreateProcessState(repo stateRepository, outbox Outbox, input ProcessI
 	return state, nil
 }
 
+type rejectionRepository interface {
+	GetCreateProcessState(capabilityId models.CapabilityId, clusterId models.ClusterId, topicName string) (*models.CreateProcess, error)
+	UpdateCreateProcessState(state *models.CreateProcess) error
+}
+
+// rejectTopic raises the rejection of a topic, and gives up on an unfinished process of the topic so it is not resumed.
+func rejectTopic(repo rejectionRepository, outbox Outbox, input ProcessInput, rejection error) error {
+	state, err := repo.GetCreateProcessState(input.CapabilityId, input.ClusterId, input.Topic.Name)
+	if err != nil {
+		return err
+	}
+
+	if state != nil && !state.IsCompleted() && !state.IsFailed() {
+		state.MarkAsFailed()
+		if err := repo.UpdateCreateProcessState(state); err != nil {
+			return err
+		}
+	}
+
+	return outbox.Produce(&TopicProvisioningFailed{
+		TopicId:      input.TopicId,
+		CapabilityId: string(input.CapabilityId),
+		ClusterId:    string(input.ClusterId),
+		TopicName:    input.Topic.Name,
+		Code:         models.FailureCodeInvalidName,
+		Reason:       rejection.Error(),
+	})
+}
+
 func (p *process) getStepContext(ctx context.Context, tx models.Transaction, state *models.CreateProcess) *StepContext {
 	logger := p.logger
 	newAccountService := NewAccountService(ctx, tx)
@@ -181,7 +271,9 @@ type EnsureTopicIsCreatedStep interface {
 	IsCompleted() bool
 	CreateTopic() error
 	MarkAsCompleted()
+	MarkAsFailed()
 	RaiseTopicProvisionedEvent() error
+	RaiseTopicProvisioningFailedEvent(models.FailureCode, string) error
 }
 
 func ensureTopicIsCreatedStep(step EnsureTopicIsCreatedStep) error {
@@ -190,8 +282,15 @@ func ensureTopicIsCreatedStep(step EnsureTopicIsCreatedStep) error {
 	}
 
 	err := step.CreateTopic()
-	if err != nil {
-		return err
+	if err != nil && !isTopicAlreadyCreated(err) {
+		code, permanent := confluent.ClassifyError(err)
+		if !permanent {
+			return err
+		}
+
+		// retrying will not help => give up
+		step.MarkAsFailed()
+		return step.RaiseTopicProvisioningFailedEvent(code, err.Error())
 	}
 
 	step.MarkAsCompleted()
@@ -199,4 +298,16 @@ func ensureTopicIsCreatedStep(step EnsureTopicIsCreatedStep) error {
 	return step.RaiseTopicProvisionedEvent()
 }
 
+// isTopicAlreadyCreated tells whether creating the topic failed because the topic exists already, as it does when an
+// earlier run created it in Confluent but rolled back before recording it.
+func isTopicAlreadyCreated(err error) bool {
+	code, _ := confluent.ClassifyError(err)
+	if code == models.FailureCodeAlreadyExists {
+		return true
+	}
+
+	var clientError *confluent.ClientError
+	return errors.As(err, &clientError) && strings.Contains(strings.ToLower(clientError.Message), "already exists")
+}
+
 // endregion
@@ -2,9 +2,11 @@ package create
 
 import (
 	"errors"
+	"net/http"
 	"testing"
 	"time"
 
+	"github.com/dfds/confluent-gateway/internal/confluent"
 	"github.com/dfds/confluent-gateway/internal/models"
 	"github.com/dfds/confluent-gateway/messaging"
 	"github.com/dfds/confluent-gateway/mocks"
@@ -172,6 +174,46 @@ func (m *mock) SaveCreateProcessState(*models.CreateProcess) error {
 	return nil
 }
 
+func Test_rejectTopic(t *testing.T) {
+	state := models.NewCreateProcess(someCapabilityId, someClusterId, someTopicId, models.TopicDescription{Name: someTopicName})
+	spy := &rejectionSpy{ReturnProcessState: state}
+	input := ProcessInput{TopicId: someTopicId, CapabilityId: someCapabilityId, ClusterId: someClusterId, Topic: models.TopicDescription{Name: someTopicName}}
+
+	err := rejectTopic(spy, spy, input, DefaultNamingPolicy().Validate(someCapabilityId, someTopicName))
+
+	assert.NoError(t, err)
+	assert.True(t, state.IsFailed())
+	assert.Equal(t, state, spy.GotUpdatedState)
+	assert.Equal(t, &TopicProvisioningFailed{
+		TopicId:      someTopicId,
+		CapabilityId: string(someCapabilityId),
+		ClusterId:    string(someClusterId),
+		TopicName:    someTopicName,
+		Code:         models.FailureCodeInvalidName,
+		Reason:       `topic name rejected: "some-topic-name" must start with "some-capability-id." or "pub.some-capability-id."`,
+	}, spy.EventProduced)
+}
+
+type rejectionSpy struct {
+	ReturnProcessState *models.CreateProcess
+	GotUpdatedState    *models.CreateProcess
+	EventProduced      messaging.OutgoingMessage
+}
+
+func (s *rejectionSpy) GetCreateProcessState(models.CapabilityId, models.ClusterId, string) (*models.CreateProcess, error) {
+	return s.ReturnProcessState, nil
+}
+
+func (s *rejectionSpy) UpdateCreateProcessState(state *models.CreateProcess) error {
+	s.GotUpdatedState = state
+	return nil
+}
+
+func (s *rejectionSpy) Produce(msg messaging.OutgoingMessage) error {
+	s.EventProduced = msg
+	return nil
+}
+
 func Test_ensureTopicIsCreated(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -179,6 +221,7 @@ func Test_ensureTopicIsCreated(t *testing.T) {
 		wantErr     assert.ErrorAssertionFunc
 		marked      bool
 		eventRaised bool
+		failed      bool
 	}{
 		{
 			name:        "ok",
@@ -194,6 +237,35 @@ func Test_ensureTopicIsCreated(t *testing.T) {
 			marked:      false,
 			eventRaised: false,
 		},
+		{
+			name:        "transient confluent error",
+			context:     &mocks.StepContextMock{OnCreateTopicError: confluent.NewClientError("some-url", http.StatusServiceUnavailable, "unavailable")},
+			wantErr:     assert.Error,
+			marked:      false,
+			eventRaised: false,
+		},
+		{
+			name:        "permanent confluent error",
+			context:     &mocks.StepContextMock{OnCreateTopicError: confluent.NewClientError("some-url", http.StatusBadRequest, "invalid config")},
+			wantErr:     assert.NoError,
+			marked:      false,
+			eventRaised: false,
+			failed:      true,
+		},
+		{
+			name:        "topic already exists",
+			context:     &mocks.StepContextMock{OnCreateTopicError: confluent.NewClientError("some-url", http.StatusBadRequest, `{"error_code":40002,"message":"Topic 'some-topic' already exists."}`)},
+			wantErr:     assert.NoError,
+			marked:      true,
+			eventRaised: true,
+		},
+		{
+			name:        "topic already exists conflict",
+			context:     &mocks.StepContextMock{OnCreateTopicError: confluent.NewClientError("some-url", http.StatusConflict, "conflict")},
+			wantErr:     assert.NoError,
+			marked:      true,
+			eventRaised: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -201,6 +273,8 @@ func Test_ensureTopicIsCreated(t *testing.T) {
 
 			assert.Equal(t, tt.marked, tt.context.MarkAsCompletedWasCalled)
 			assert.Equal(t, tt.eventRaised, tt.context.TopicProvisionedEventWasRaised)
+			assert.Equal(t, tt.failed, tt.context.MarkAsFailedWasCalled)
+			assert.Equal(t, tt.failed, tt.context.TopicProvisioningFailedWasRaised)
 		})
 	}
 }
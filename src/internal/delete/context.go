@@ -60,3 +60,16 @@ func (c *StepContext) RaiseTopicDeletedEvent() error {
 	}
 	return c.outbox.Produce(event)
 }
+
+func (c *StepContext) MarkAsFailed() {
+	c.state.MarkAsFailed()
+}
+
+func (c *StepContext) RaiseTopicDeletionFailedEvent(code models.FailureCode, reason string) error {
+	event := &TopicDeletionFailed{
+		TopicId: c.state.TopicId,
+		Code:    code,
+		Reason:  reason,
+	}
+	return c.outbox.Produce(event)
+}
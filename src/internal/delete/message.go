@@ -1,5 +1,7 @@
 package delete
 
+import "github.com/dfds/confluent-gateway/internal/models"
+
 type TopicDeletionRequested struct {
 	TopicId string `json:"kafkaTopicId"`
 }
@@ -11,3 +13,13 @@ type TopicDeleted struct {
 func (t *TopicDeleted) PartitionKey() string {
 	return t.TopicId
 }
+
+type TopicDeletionFailed struct {
+	TopicId string             `json:"kafkaTopicId"`
+	Code    models.FailureCode `json:"code"`
+	Reason  string             `json:"reason"`
+}
+
+func (t *TopicDeletionFailed) PartitionKey() string {
+	return t.TopicId
+}
@@ -4,13 +4,22 @@ import (
 	"context"
 	"errors"
 	"strings"
+	"time"
 
+	"github.com/dfds/confluent-gateway/internal/confluent"
 	"github.com/dfds/confluent-gateway/internal/models"
 	. "github.com/dfds/confluent-gateway/internal/process"
 	"github.com/dfds/confluent-gateway/internal/storage"
 	"github.com/dfds/confluent-gateway/logging"
+	uuid "github.com/satori/go.uuid"
 )
 
+// ResumableProcess is a process that can also be continued from a state it left incomplete.
+type ResumableProcess interface {
+	Process
+	Resume(ctx context.Context, processId uuid.UUID) error
+}
+
 type process struct {
 	logger    logging.Logger
 	database  models.Database
@@ -18,7 +27,7 @@ type process struct {
 	factory   OutboxFactory
 }
 
-func NewProcess(logger logging.Logger, database models.Database, confluent Confluent, factory OutboxFactory) Process {
+func NewProcess(logger logging.Logger, database models.Database, confluent Confluent, factory OutboxFactory) ResumableProcess {
 	return &process{
 		logger:    logger,
 		database:  database,
@@ -50,11 +59,45 @@ func (p *process) Process(ctx context.Context, input ProcessInput) error {
 		return nil
 	}
 
+	return p.run(ctx, session, state)
+}
+
+// Resume continues the process from the state it was left in.
+func (p *process) Resume(ctx context.Context, processId uuid.UUID) error {
+	session := p.database.NewSession(ctx)
+
+	state := &models.DeleteProcess{Id: processId}
+	if err := session.Transaction(func(tx models.Transaction) error { return tx.LockDeleteProcessState(state) }); err != nil {
+		return err
+	}
+
+	if state.IsCompleted() || state.IsFailed() {
+		// finished in the meantime => skip
+		return nil
+	}
+
+	return p.run(ctx, session, state)
+}
+
+func (p *process) run(ctx context.Context, session models.Session, state *models.DeleteProcess) error {
+	// the steps start over from the locked process state on every attempt, so failing calls to Confluent can safely be
+	// retried
+	retry := WithRetry(3, ExponentialBackoff(time.Second, 5*time.Second))
+
 	return PrepareSteps[*StepContext]().
-		Step(ensureTopicSchemasAreDeleted).
-		Step(ensureTopicIsDeleted).
+		Step(ensureTopicSchemasAreDeleted, retry).
+		Step(ensureTopicIsDeleted, retry).
+		RecordTo(NewStepRecorder(session, "delete-topic", state.Id.String())).
 		Run(func(step func(*StepContext) error) error {
 			return session.Transaction(func(tx models.Transaction) error {
+				// another run of the process waits for this step and continues from its outcome
+				if err := tx.LockDeleteProcessState(state); err != nil {
+					return err
+				}
+				if state.IsCompleted() || state.IsFailed() {
+					return nil
+				}
+
 				stepContext := p.getStepContext(ctx, tx, state)
 
 				err := step(stepContext)
@@ -112,7 +155,7 @@ func getOrCreateProcessState(repo stateRepository, input ProcessInput) (*models.
 		return nil, err
 	}
 
-	if state != nil && !state.IsCompleted() {
+	if state != nil && !state.IsCompleted() && !state.IsFailed() {
 		// is process is unfinished => continue
 		return state, nil
 	}
@@ -146,7 +189,9 @@ type EnsureTopicIsDeletedStep interface {
 	IsCompleted() bool
 	DeleteTopic() error
 	MarkAsCompleted()
+	MarkAsFailed()
 	RaiseTopicDeletedEvent() error
+	RaiseTopicDeletionFailedEvent(models.FailureCode, string) error
 }
 
 func ensureTopicIsDeletedStep(step EnsureTopicIsDeletedStep) error {
@@ -155,10 +200,15 @@ func ensureTopicIsDeletedStep(step EnsureTopicIsDeletedStep) error {
 	}
 
 	err := step.DeleteTopic()
-	if err != nil {
-		if !strings.Contains(strings.ToLower(err.Error()), "this server does not host this topic-partition") {
+	if err != nil && !isTopicGone(err) {
+		code, permanent := confluent.ClassifyError(err)
+		if !permanent {
 			return err
 		}
+
+		// retrying will not help => give up
+		step.MarkAsFailed()
+		return step.RaiseTopicDeletionFailedEvent(code, err.Error())
 	}
 
 	step.MarkAsCompleted()
@@ -166,6 +216,16 @@ func ensureTopicIsDeletedStep(step EnsureTopicIsDeletedStep) error {
 	return step.RaiseTopicDeletedEvent()
 }
 
+// isTopicGone tells whether deleting the topic failed because the topic does not exist anymore.
+func isTopicGone(err error) bool {
+	if strings.Contains(strings.ToLower(err.Error()), "this server does not host this topic-partition") {
+		return true
+	}
+
+	code, _ := confluent.ClassifyError(err)
+	return code == models.FailureCodeNotFound
+}
+
 // endregion
 
 // region Schema deletion
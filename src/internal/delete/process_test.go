@@ -2,9 +2,11 @@ package delete
 
 import (
 	"errors"
+	"github.com/dfds/confluent-gateway/internal/confluent"
 	"github.com/dfds/confluent-gateway/internal/models"
 	"github.com/dfds/confluent-gateway/mocks"
 	"github.com/stretchr/testify/assert"
+	"net/http"
 	"testing"
 	"time"
 )
@@ -90,6 +92,7 @@ func Test_ensureTopicIsDeleted(t *testing.T) {
 		wantErr     assert.ErrorAssertionFunc
 		marked      bool
 		eventRaised bool
+		failed      bool
 	}{
 		{
 			name:        "ok",
@@ -105,6 +108,28 @@ func Test_ensureTopicIsDeleted(t *testing.T) {
 			marked:      false,
 			eventRaised: false,
 		},
+		{
+			name:        "transient confluent error",
+			context:     &mocks.StepContextMock{OnDeleteTopicError: confluent.NewClientError("some-url", http.StatusServiceUnavailable, "unavailable")},
+			wantErr:     assert.Error,
+			marked:      false,
+			eventRaised: false,
+		},
+		{
+			name:        "permanent confluent error",
+			context:     &mocks.StepContextMock{OnDeleteTopicError: confluent.NewClientError("some-url", http.StatusBadRequest, "invalid config")},
+			wantErr:     assert.NoError,
+			marked:      false,
+			eventRaised: false,
+			failed:      true,
+		},
+		{
+			name:        "topic already gone",
+			context:     &mocks.StepContextMock{OnDeleteTopicError: confluent.NewClientError("some-url", http.StatusNotFound, "topic not found")},
+			wantErr:     assert.NoError,
+			marked:      true,
+			eventRaised: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -112,6 +137,8 @@ func Test_ensureTopicIsDeleted(t *testing.T) {
 
 			assert.Equal(t, tt.marked, tt.context.MarkAsCompletedWasCalled)
 			assert.Equal(t, tt.eventRaised, tt.context.TopicDeletedEventWasRaised)
+			assert.Equal(t, tt.failed, tt.context.MarkAsFailedWasCalled)
+			assert.Equal(t, tt.failed, tt.context.TopicDeletionFailedWasRaised)
 		})
 	}
 }
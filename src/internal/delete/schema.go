@@ -13,6 +13,7 @@ type schemaService struct {
 
 type schemaRepository interface {
 	SelectSchemaProcessStatesByTopicId(topicId string) ([]models.SchemaProcess, error)
+	SelectSchemaProcessStatesBySubject(clusterId models.ClusterId, subject string) ([]models.SchemaProcess, error)
 	DeleteSchemaProcessStateById(schemaId string) error
 }
 
@@ -31,6 +32,15 @@ func (p *schemaService) DeleteSchemasByTopicId(topicId string) error {
 	}
 
 	for _, schemaProcess := range schemaProcesses {
+		owned, err := p.ownsSubject(schemaProcess)
+		if err != nil {
+			return err
+		}
+
+		if !owned {
+			continue
+		}
+
 		err = p.confluent.DeleteSchema(p.context, schemaProcess.ClusterId, schemaProcess.Subject, schemaProcess.Schema, "latest")
 		if err != nil {
 			return err
@@ -46,3 +56,25 @@ func (p *schemaService) DeleteSchemasByTopicId(topicId string) error {
 
 	return nil
 }
+
+// ownsSubject tells whether the subject of a schema belongs to its topic alone. With record name strategies a
+// subject can be shared with other topics, in which case it is left in the schema registry. Schemas deleted along
+// with their message contract are already gone.
+func (p *schemaService) ownsSubject(schemaProcess models.SchemaProcess) (bool, error) {
+	if schemaProcess.Subject == "" || schemaProcess.IsDeleted() {
+		return false, nil
+	}
+
+	others, err := p.repo.SelectSchemaProcessStatesBySubject(schemaProcess.ClusterId, schemaProcess.Subject)
+	if err != nil {
+		return false, err
+	}
+
+	for _, other := range others {
+		if other.TopicId != schemaProcess.TopicId {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
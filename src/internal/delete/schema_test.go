@@ -0,0 +1,52 @@
+package delete
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/dfds/confluent-gateway/mocks"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaService_DeleteSchemasByTopicId(t *testing.T) {
+	owned := models.SchemaProcess{Id: uuid.NewV4(), ClusterId: someClusterId, TopicId: someTopicId, Subject: someTopicName + "-value"}
+	shared := models.SchemaProcess{Id: uuid.NewV4(), ClusterId: someClusterId, TopicId: someTopicId, Subject: "com.example.SomeRecord"}
+	withoutSubject := models.SchemaProcess{Id: uuid.NewV4(), ClusterId: someClusterId, TopicId: someTopicId}
+
+	confluentSpy := &mocks.MockClient{}
+	repoSpy := &schemaRepositoryMock{
+		SchemaProcesses: []models.SchemaProcess{owned, shared, withoutSubject},
+		BySubject: map[string][]models.SchemaProcess{
+			owned.Subject:  {owned},
+			shared.Subject: {shared, {TopicId: "another-topic-id", Subject: shared.Subject}},
+		},
+	}
+
+	sut := NewSchemaService(context.TODO(), confluentSpy, repoSpy)
+	err := sut.DeleteSchemasByTopicId(someTopicId)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{owned.Subject}, confluentSpy.GotSubjects)
+	assert.Equal(t, []string{owned.Id.String(), shared.Id.String(), withoutSubject.Id.String()}, repoSpy.GotDeletedIds)
+}
+
+type schemaRepositoryMock struct {
+	SchemaProcesses []models.SchemaProcess
+	BySubject       map[string][]models.SchemaProcess
+	GotDeletedIds   []string
+}
+
+func (m *schemaRepositoryMock) SelectSchemaProcessStatesByTopicId(string) ([]models.SchemaProcess, error) {
+	return m.SchemaProcesses, nil
+}
+
+func (m *schemaRepositoryMock) SelectSchemaProcessStatesBySubject(_ models.ClusterId, subject string) ([]models.SchemaProcess, error) {
+	return m.BySubject[subject], nil
+}
+
+func (m *schemaRepositoryMock) DeleteSchemaProcessStateById(schemaId string) error {
+	m.GotDeletedIds = append(m.GotDeletedIds, schemaId)
+	return nil
+}
@@ -0,0 +1,187 @@
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dfds/confluent-gateway/internal/models"
+)
+
+// prefix marks the values encrypted by an Encryptor, so that values written before encryption was introduced can
+// still be read.
+const prefix = "enc:v1:"
+
+var ErrNoKeyProvider = errors.New("no key provider is configured")
+var ErrInvalidCiphertext = errors.New("invalid encrypted value")
+
+// KeyProvider hands out the data keys values are encrypted with, and keeps them encrypted under a key of its own.
+type KeyProvider interface {
+	// GenerateDataKey returns a new data key, in plain and encrypted form.
+	GenerateDataKey(ctx context.Context) (plaintext []byte, encrypted []byte, err error)
+	// DecryptDataKey returns the plain form of a data key returned by GenerateDataKey.
+	DecryptDataKey(ctx context.Context, encrypted []byte) ([]byte, error)
+}
+
+// Encryptor applies envelope encryption: every value is encrypted with a data key of its own, which is stored
+// next to the value encrypted by the key provider.
+type Encryptor struct {
+	provider KeyProvider
+}
+
+// NewEncryptor returns an Encryptor using the key provider. Without a key provider values can only be read when
+// they are not encrypted.
+func NewEncryptor(provider KeyProvider) *Encryptor {
+	return &Encryptor{provider: provider}
+}
+
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+func (e *Encryptor) Encrypt(ctx context.Context, plaintext string) (string, error) {
+	if e.provider == nil {
+		return "", ErrNoKeyProvider
+	}
+
+	dataKey, encryptedDataKey, err := e.provider.GenerateDataKey(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	ciphertext, err := seal(dataKey, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+
+	return prefix + base64.StdEncoding.EncodeToString(encryptedDataKey) + ":" + base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// Decrypt returns the plain form of a value returned by Encrypt. Values that are not encrypted are returned as is.
+func (e *Encryptor) Decrypt(ctx context.Context, value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	if e.provider == nil {
+		return "", ErrNoKeyProvider
+	}
+
+	encodedDataKey, encodedCiphertext, ok := strings.Cut(strings.TrimPrefix(value, prefix), ":")
+	if !ok {
+		return "", ErrInvalidCiphertext
+	}
+
+	encryptedDataKey, err := base64.StdEncoding.DecodeString(encodedDataKey)
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(encodedCiphertext)
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+
+	dataKey, err := e.provider.DecryptDataKey(ctx, encryptedDataKey)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := open(dataKey, ciphertext)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+// EncryptCluster encrypts the api keys kept in the cluster table, leaving the ones already encrypted or empty as
+// they are.
+func (e *Encryptor) EncryptCluster(ctx context.Context, cluster *models.Cluster) (bool, error) {
+	changed := false
+
+	for _, field := range apiKeyFields(cluster) {
+		if *field == "" || IsEncrypted(*field) {
+			continue
+		}
+
+		encrypted, err := e.Encrypt(ctx, *field)
+		if err != nil {
+			return false, fmt.Errorf("unable to encrypt api keys of cluster %s: %w", cluster.ClusterId, err)
+		}
+
+		*field = encrypted
+		changed = true
+	}
+
+	return changed, nil
+}
+
+// DecryptCluster decrypts the api keys kept in the cluster table.
+func (e *Encryptor) DecryptCluster(ctx context.Context, cluster *models.Cluster) error {
+	for _, field := range apiKeyFields(cluster) {
+		decrypted, err := e.Decrypt(ctx, *field)
+		if err != nil {
+			return fmt.Errorf("unable to decrypt api keys of cluster %s: %w", cluster.ClusterId, err)
+		}
+
+		*field = decrypted
+	}
+
+	return nil
+}
+
+func apiKeyFields(cluster *models.Cluster) []*string {
+	return []*string{
+		&cluster.AdminApiKey.Username,
+		&cluster.AdminApiKey.Password,
+		&cluster.SchemaRegistryApiKey.Username,
+		&cluster.SchemaRegistryApiKey.Password,
+	}
+}
+
+// seal encrypts with AES-GCM, prepending the nonce to the ciphertext.
+func seal(key []byte, plaintext []byte) ([]byte, error) {
+	aead, err := newAead(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func open(key []byte, ciphertext []byte) ([]byte, error) {
+	aead, err := newAead(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, ErrInvalidCiphertext
+	}
+
+	nonce, ciphertext := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+
+	return plaintext, nil
+}
+
+func newAead(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
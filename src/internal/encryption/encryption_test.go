@@ -0,0 +1,104 @@
+package encryption
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestEncryptor(t *testing.T) *Encryptor {
+	provider, err := NewLocalKeyProvider(make([]byte, keySize))
+	assert.NoError(t, err)
+	return NewEncryptor(provider)
+}
+
+func TestEncryptor_RoundTrip(t *testing.T) {
+	sut := newTestEncryptor(t)
+
+	encrypted, err := sut.Encrypt(context.TODO(), "admin_pass")
+	assert.NoError(t, err)
+	assert.True(t, IsEncrypted(encrypted))
+	assert.NotContains(t, encrypted, "admin_pass")
+
+	decrypted, err := sut.Decrypt(context.TODO(), encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, "admin_pass", decrypted)
+}
+
+func TestEncryptor_UsesNewDataKeyForEveryValue(t *testing.T) {
+	sut := newTestEncryptor(t)
+
+	first, _ := sut.Encrypt(context.TODO(), "admin_pass")
+	second, _ := sut.Encrypt(context.TODO(), "admin_pass")
+
+	assert.NotEqual(t, first, second)
+}
+
+func TestEncryptor_DecryptReturnsPlaintextAsIs(t *testing.T) {
+	sut := NewEncryptor(nil)
+
+	decrypted, err := sut.Decrypt(context.TODO(), "admin_pass")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "admin_pass", decrypted)
+}
+
+func TestEncryptor_DecryptWithoutKeyProvider(t *testing.T) {
+	encrypted, _ := newTestEncryptor(t).Encrypt(context.TODO(), "admin_pass")
+
+	_, err := NewEncryptor(nil).Decrypt(context.TODO(), encrypted)
+
+	assert.ErrorIs(t, err, ErrNoKeyProvider)
+}
+
+func TestEncryptor_DecryptWithAnotherKey(t *testing.T) {
+	encrypted, _ := newTestEncryptor(t).Encrypt(context.TODO(), "admin_pass")
+	provider, _ := NewLocalKeyProvider([]byte("0123456789abcdef0123456789abcdef"))
+
+	_, err := NewEncryptor(provider).Decrypt(context.TODO(), encrypted)
+
+	assert.ErrorIs(t, err, ErrInvalidCiphertext)
+}
+
+func TestEncryptor_EncryptCluster(t *testing.T) {
+	sut := newTestEncryptor(t)
+	cluster := &models.Cluster{ClusterId: "abc-1234", AdminApiKey: models.ApiKey{Username: "admin_user", Password: "admin_pass"}}
+
+	changed, err := sut.EncryptCluster(context.TODO(), cluster)
+
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.True(t, IsEncrypted(cluster.AdminApiKey.Username))
+	assert.True(t, IsEncrypted(cluster.AdminApiKey.Password))
+	assert.Equal(t, models.ApiKey{}, cluster.SchemaRegistryApiKey)
+
+	changed, err = sut.EncryptCluster(context.TODO(), cluster)
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	assert.NoError(t, sut.DecryptCluster(context.TODO(), cluster))
+	assert.Equal(t, models.ApiKey{Username: "admin_user", Password: "admin_pass"}, cluster.AdminApiKey)
+}
+
+func TestNewLocalKeyProviderFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cluster.key")
+	_ = os.WriteFile(path, []byte(base64.StdEncoding.EncodeToString(make([]byte, keySize))+"\n"), 0600)
+
+	_, err := NewLocalKeyProviderFromFile(path)
+
+	assert.NoError(t, err)
+}
+
+func TestNewLocalKeyProviderFromFile_WrongKeySize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cluster.key")
+	_ = os.WriteFile(path, []byte(base64.StdEncoding.EncodeToString(make([]byte, 16))), 0600)
+
+	_, err := NewLocalKeyProviderFromFile(path)
+
+	assert.EqualError(t, err, "the key must be 32 bytes, got 16")
+}
@@ -0,0 +1,46 @@
+package encryption
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/aws/aws-sdk-go-v2/service/kms/types"
+)
+
+// KmsKeyProvider has AWS KMS generate the data keys and keep them encrypted under a KMS key.
+type KmsKeyProvider struct {
+	client *kms.Client
+	keyId  string
+}
+
+func NewKmsKeyProvider(cfg *aws.Config, keyId string) *KmsKeyProvider {
+	return &KmsKeyProvider{
+		client: kms.NewFromConfig(*cfg),
+		keyId:  keyId,
+	}
+}
+
+func (p *KmsKeyProvider) GenerateDataKey(ctx context.Context) ([]byte, []byte, error) {
+	output, err := p.client.GenerateDataKey(ctx, &kms.GenerateDataKeyInput{
+		KeyId:   aws.String(p.keyId),
+		KeySpec: types.DataKeySpecAes256,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return output.Plaintext, output.CiphertextBlob, nil
+}
+
+func (p *KmsKeyProvider) DecryptDataKey(ctx context.Context, encrypted []byte) ([]byte, error) {
+	output, err := p.client.Decrypt(ctx, &kms.DecryptInput{
+		KeyId:          aws.String(p.keyId),
+		CiphertextBlob: encrypted,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return output.Plaintext, nil
+}
@@ -0,0 +1,60 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+)
+
+const keySize = 32
+
+// LocalKeyProvider keeps the data keys encrypted with an AES-256 key of its own. It is meant for development, where
+// no KMS is at hand.
+type LocalKeyProvider struct {
+	key []byte
+}
+
+func NewLocalKeyProvider(key []byte) (*LocalKeyProvider, error) {
+	if len(key) != keySize {
+		return nil, fmt.Errorf("the key must be %d bytes, got %d", keySize, len(key))
+	}
+
+	return &LocalKeyProvider{key: key}, nil
+}
+
+// NewLocalKeyProviderFromFile reads the key from a file holding it base64 encoded, e.g. created with
+// "openssl rand -base64 32".
+func NewLocalKeyProviderFromFile(path string) (*LocalKeyProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(data)))
+	if err != nil {
+		return nil, fmt.Errorf("the key file %s must hold a base64 encoded key: %w", path, err)
+	}
+
+	return NewLocalKeyProvider(key)
+}
+
+func (p *LocalKeyProvider) GenerateDataKey(context.Context) ([]byte, []byte, error) {
+	dataKey := make([]byte, keySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, nil, err
+	}
+
+	encrypted, err := seal(p.key, dataKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return dataKey, encrypted, nil
+}
+
+func (p *LocalKeyProvider) DecryptDataKey(_ context.Context, encrypted []byte) ([]byte, error) {
+	return open(p.key, encrypted)
+}
@@ -0,0 +1,44 @@
+package grant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/dfds/confluent-gateway/messaging"
+)
+
+type Granter interface {
+	RequestGrant(ctx context.Context, request models.AccessGrantRequest) (*models.AccessGrant, error)
+}
+
+type handler struct {
+	granter Granter
+}
+
+// NewAccessGrantRequestedHandler handles requests for access grants. A request for a grant that already exists is
+// treated as handled, so that redelivered messages do not fail.
+func NewAccessGrantRequestedHandler(granter Granter) messaging.MessageHandler {
+	return &handler{granter: granter}
+}
+
+func (h *handler) Handle(ctx context.Context, msgContext messaging.MessageContext) error {
+	switch message := msgContext.Message().(type) {
+
+	case *AccessGrantRequested:
+		_, err := h.granter.RequestGrant(ctx, models.AccessGrantRequest{
+			ClusterId:           models.ClusterId(message.KafkaClusterId),
+			TopicName:           message.TopicName,
+			GranteeCapabilityId: models.CapabilityId(message.GranteeCapabilityId),
+			ConsumerGroup:       message.ConsumerGroup,
+		})
+		if errors.Is(err, models.ErrAccessGrantAlreadyExists) {
+			return nil
+		}
+		return err
+
+	default:
+		return fmt.Errorf("unknown message %#v", message)
+	}
+}
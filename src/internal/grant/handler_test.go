@@ -0,0 +1,74 @@
+package grant
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/dfds/confluent-gateway/messaging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessGrantRequestedHandler_Handle(t *testing.T) {
+	tests := []struct {
+		name        string
+		granter     *granterStub
+		msgContext  messaging.MessageContext
+		wantRequest models.AccessGrantRequest
+		wantErr     assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "grant ok",
+			granter: &granterStub{},
+			msgContext: messaging.NewMessageContext(map[string]string{}, &AccessGrantRequested{
+				KafkaClusterId:      "abc-1234",
+				TopicName:           "owner-cap.orders",
+				GranteeCapabilityId: "grantee-cap",
+				ConsumerGroup:       "grantee-cap.consumer",
+			}),
+			wantRequest: models.AccessGrantRequest{
+				ClusterId:           "abc-1234",
+				TopicName:           "owner-cap.orders",
+				GranteeCapabilityId: "grantee-cap",
+				ConsumerGroup:       "grantee-cap.consumer",
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name:       "grant exists",
+			granter:    &granterStub{err: models.ErrAccessGrantAlreadyExists},
+			msgContext: messaging.NewMessageContext(map[string]string{}, &AccessGrantRequested{}),
+			wantErr:    assert.NoError,
+		},
+		{
+			name:       "grant fail",
+			granter:    &granterStub{err: errors.New("fail")},
+			msgContext: messaging.NewMessageContext(map[string]string{}, &AccessGrantRequested{}),
+			wantErr:    assert.Error,
+		},
+		{
+			name:       "unknown message",
+			granter:    &granterStub{},
+			msgContext: messaging.NewMessageContext(map[string]string{}, "bad message"),
+			wantErr:    assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewAccessGrantRequestedHandler(tt.granter)
+			tt.wantErr(t, h.Handle(context.TODO(), tt.msgContext))
+			assert.Equal(t, tt.wantRequest, tt.granter.request)
+		})
+	}
+}
+
+type granterStub struct {
+	request models.AccessGrantRequest
+	err     error
+}
+
+func (g *granterStub) RequestGrant(_ context.Context, request models.AccessGrantRequest) (*models.AccessGrant, error) {
+	g.request = request
+	return &models.AccessGrant{}, g.err
+}
@@ -0,0 +1,38 @@
+package grant
+
+type AccessGrantRequested struct {
+	KafkaClusterId      string `json:"kafkaClusterId"`
+	TopicName           string `json:"topicName"`
+	GranteeCapabilityId string `json:"granteeCapabilityId"`
+	ConsumerGroup       string `json:"consumerGroup"`
+}
+
+func (r *AccessGrantRequested) PartitionKey() string {
+	return r.GranteeCapabilityId
+}
+
+type AccessGranted struct {
+	GrantId             string `json:"grantId"`
+	KafkaClusterId      string `json:"kafkaClusterId"`
+	TopicName           string `json:"topicName"`
+	ConsumerGroup       string `json:"consumerGroup"`
+	OwnerCapabilityId   string `json:"ownerCapabilityId"`
+	GranteeCapabilityId string `json:"granteeCapabilityId"`
+	OwnerApproved       bool   `json:"ownerApproved"`
+}
+
+func (r *AccessGranted) PartitionKey() string {
+	return r.GranteeCapabilityId
+}
+
+type AccessRevoked struct {
+	GrantId             string `json:"grantId"`
+	KafkaClusterId      string `json:"kafkaClusterId"`
+	TopicName           string `json:"topicName"`
+	OwnerCapabilityId   string `json:"ownerCapabilityId"`
+	GranteeCapabilityId string `json:"granteeCapabilityId"`
+}
+
+func (r *AccessRevoked) PartitionKey() string {
+	return r.GranteeCapabilityId
+}
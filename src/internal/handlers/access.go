@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/dfds/confluent-gateway/internal/services"
+	"github.com/dfds/confluent-gateway/internal/storage"
+)
+
+// GetCapabilityAccess godoc
+//
+//	@Summary		Get the access of a capability
+//	@Description	Get the service account of a capability, or of one of its applications, with its cluster accesses, acl entries and api key status.
+//	@Tags			access
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	models.AccessInfo
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/capabilities/{capabilityId}/access [get]
+//
+//	@Param			capabilityId	path	string	true	"Capability id"
+//	@Param			application		query	string	false	"Application name"
+func GetCapabilityAccess(h *Handler, w http.ResponseWriter, r *http.Request, capabilityId models.CapabilityId) {
+	applicationName := r.URL.Query().Get("application")
+	if err := models.ValidateApplicationName(applicationName); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	access, err := h.AccessService.GetAccess(h.Ctx, capabilityId, applicationName)
+	if err != nil {
+		writeAccessError(h, w, err, "Failed to get access")
+		return
+	}
+
+	writeJson(w, http.StatusOK, access)
+}
+
+// ListCapabilityRoleBindings godoc
+//
+//	@Summary		List the role bindings of a capability
+//	@Description	List the role bindings of all the service accounts of a capability, on the clusters in RBAC mode.
+//	@Tags			access
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{array}		models.RoleBindingInfo
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/capabilities/{capabilityId}/role-bindings [get]
+//
+//	@Param			capabilityId	path	string	true	"Capability id"
+func ListCapabilityRoleBindings(h *Handler, w http.ResponseWriter, r *http.Request, capabilityId models.CapabilityId) {
+	roleBindings, err := h.AccessService.ListRoleBindings(h.Ctx, capabilityId)
+	if err != nil {
+		writeAccessError(h, w, err, "Failed to list role bindings")
+		return
+	}
+
+	writeJson(w, http.StatusOK, roleBindings)
+}
+
+// DeleteCapabilityRoleBinding godoc
+//
+//	@Summary		Delete a role binding of a capability
+//	@Description	Delete a role binding from Confluent, keeping it in the database as deleted.
+//	@Tags			access
+//	@Accept			json
+//	@Produce		json
+//	@Success		204
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/capabilities/{capabilityId}/role-bindings/{roleBindingId} [delete]
+//
+//	@Param			capabilityId	path	string	true	"Capability id"
+//	@Param			roleBindingId	path	string	true	"Role binding id"
+func DeleteCapabilityRoleBinding(h *Handler, w http.ResponseWriter, r *http.Request, capabilityId models.CapabilityId, roleBindingId string) {
+	if err := h.AccessService.DeleteRoleBinding(h.Ctx, capabilityId, roleBindingId); err != nil {
+		writeAccessError(h, w, err, "Failed to delete role binding")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeAccessError(h *Handler, w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, storage.ErrServiceAccountNotFound):
+		writeError(w, http.StatusNotFound, "Service account not found")
+	case errors.Is(err, services.ErrRoleBindingNotFound):
+		writeError(w, http.StatusNotFound, "Role binding not found")
+	default:
+		h.Logger.Error(err, message)
+		writeError(w, http.StatusInternalServerError, message)
+	}
+}
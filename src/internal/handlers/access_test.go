@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dfds/confluent-gateway/internal/mocks"
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/dfds/confluent-gateway/internal/services"
+	"github.com/dfds/confluent-gateway/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetCapabilityAccess_Success(t *testing.T) {
+	// Arrange
+	mockService := new(mocks.MockAccessService)
+	mockLogger := new(mocks.MockLogger)
+	handler := &Handler{Ctx: context.Background(), Logger: mockLogger, AccessService: mockService}
+
+	access := &models.AccessInfo{
+		ServiceAccountId: "sa-123",
+		CapabilityId:     "some-capability",
+		ClusterAccesses: []models.ClusterAccessInfo{
+			{ClusterId: "abc-1234", ApiKeys: models.ApiKeyStatus{ClusterApiKeyInConfluent: true, ClusterApiKeyInVault: true}, Acl: []models.AclEntryInfo{}},
+		},
+	}
+
+	mockService.On("GetAccess", mock.Anything, models.CapabilityId("some-capability"), "").Return(access, nil)
+
+	req, err := http.NewRequest(http.MethodGet, "/capabilities/some-capability/access", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	// Act
+	GetCapabilityAccess(handler, rr, req, "some-capability")
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rr.Code)
+	expectedBody, _ := json.Marshal(access)
+	assert.JSONEq(t, string(expectedBody), rr.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestGetCapabilityAccess_NotFound(t *testing.T) {
+	// Arrange
+	mockService := new(mocks.MockAccessService)
+	mockLogger := new(mocks.MockLogger)
+	handler := &Handler{Ctx: context.Background(), Logger: mockLogger, AccessService: mockService}
+
+	mockService.On("GetAccess", mock.Anything, models.CapabilityId("some-capability"), "").Return(nil, storage.ErrServiceAccountNotFound)
+
+	req, err := http.NewRequest(http.MethodGet, "/capabilities/some-capability/access", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	// Act
+	GetCapabilityAccess(handler, rr, req, "some-capability")
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetCapabilityAccess_Application(t *testing.T) {
+	// Arrange
+	mockService := new(mocks.MockAccessService)
+	mockLogger := new(mocks.MockLogger)
+	handler := &Handler{Ctx: context.Background(), Logger: mockLogger, AccessService: mockService}
+
+	access := &models.AccessInfo{ServiceAccountId: "sa-456", CapabilityId: "some-capability", ApplicationName: "billing", ClusterAccesses: []models.ClusterAccessInfo{}}
+	mockService.On("GetAccess", mock.Anything, models.CapabilityId("some-capability"), "billing").Return(access, nil)
+
+	req, err := http.NewRequest(http.MethodGet, "/capabilities/some-capability/access?application=billing", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	// Act
+	GetCapabilityAccess(handler, rr, req, "some-capability")
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"applicationName":"billing"`)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetCapabilityAccess_InvalidApplication(t *testing.T) {
+	// Arrange
+	mockService := new(mocks.MockAccessService)
+	mockLogger := new(mocks.MockLogger)
+	handler := &Handler{Ctx: context.Background(), Logger: mockLogger, AccessService: mockService}
+
+	req, err := http.NewRequest(http.MethodGet, "/capabilities/some-capability/access?application=Not_Valid", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	// Act
+	GetCapabilityAccess(handler, rr, req, "some-capability")
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertNotCalled(t, "GetAccess", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestListCapabilityRoleBindings(t *testing.T) {
+	// Arrange
+	mockService := new(mocks.MockAccessService)
+	mockLogger := new(mocks.MockLogger)
+	handler := &Handler{Ctx: context.Background(), Logger: mockLogger, AccessService: mockService}
+
+	roleBindings := []models.RoleBindingInfo{{Id: "some-id", RoleName: models.RoleNameDeveloperRead, CrnPattern: "crn://some/topic=some-capability*", Status: models.AclEntryStatusCreated, ClusterId: "abc-1234"}}
+	mockService.On("ListRoleBindings", mock.Anything, models.CapabilityId("some-capability")).Return(roleBindings, nil)
+
+	req, err := http.NewRequest(http.MethodGet, "/capabilities/some-capability/role-bindings", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	// Act
+	ListCapabilityRoleBindings(handler, rr, req, "some-capability")
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rr.Code)
+	expectedBody, _ := json.Marshal(roleBindings)
+	assert.JSONEq(t, string(expectedBody), rr.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestDeleteCapabilityRoleBinding(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{"deleted", nil, http.StatusNoContent},
+		{"not found", services.ErrRoleBindingNotFound, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := new(mocks.MockAccessService)
+			mockLogger := new(mocks.MockLogger)
+			handler := &Handler{Ctx: context.Background(), Logger: mockLogger, AccessService: mockService}
+
+			mockService.On("DeleteRoleBinding", mock.Anything, models.CapabilityId("some-capability"), "some-id").Return(tt.err)
+
+			req, err := http.NewRequest(http.MethodDelete, "/capabilities/some-capability/role-bindings/some-id", nil)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+
+			// Act
+			DeleteCapabilityRoleBinding(handler, rr, req, "some-capability", "some-id")
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
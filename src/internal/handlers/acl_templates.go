@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/dfds/confluent-gateway/internal/services"
+)
+
+type AclTemplateRequest struct {
+	Entries []models.AclTemplateEntry `json:"entries"`
+}
+
+// GetAclTemplate godoc
+//
+//	@Summary		Get the ACL template of a cluster
+//	@Description	Get the current version of the ACL template capabilities get on a cluster. Resource names may contain the {capabilityId} placeholder.
+//	@Tags			acl-templates
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	models.AclTemplate
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/clusters/{clusterId}/acl-template [get]
+//
+//	@Param			clusterId	path	string	true	"Cluster id"
+func GetAclTemplate(h *Handler, w http.ResponseWriter, r *http.Request, clusterId models.ClusterId) {
+	template, err := h.AclTemplateService.GetAclTemplate(h.Ctx, clusterId)
+	if err != nil {
+		writeAclTemplateError(h, w, err, "Failed to get acl template")
+		return
+	}
+
+	writeJson(w, http.StatusOK, template)
+}
+
+// UpdateAclTemplate godoc
+//
+//	@Summary		Update the ACL template of a cluster
+//	@Description	Add a new version of the ACL template of a cluster. Existing cluster accesses are brought up to it by reapplying the template.
+//	@Tags			acl-templates
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		AclTemplateRequest	true	"ACL template"
+//	@Success		201		{object}	models.AclTemplate
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/clusters/{clusterId}/acl-template [put]
+//
+//	@Param			clusterId	path	string	true	"Cluster id"
+func UpdateAclTemplate(h *Handler, w http.ResponseWriter, r *http.Request, clusterId models.ClusterId) {
+	var request AclTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	template, err := h.AclTemplateService.UpdateAclTemplate(h.Ctx, clusterId, request.Entries)
+	if err != nil {
+		writeAclTemplateError(h, w, err, "Failed to update acl template")
+		return
+	}
+
+	writeJson(w, http.StatusCreated, template)
+}
+
+// ReapplyAclTemplate godoc
+//
+//	@Summary		Reapply the ACL template of a cluster
+//	@Description	Bring the ACL of every cluster access on the cluster up to the current version of the template, adding and removing entries.
+//	@Tags			acl-templates
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	models.AclTemplateReapplyResult
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		409	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/clusters/{clusterId}/acl-template/reapply [post]
+//
+//	@Param			clusterId	path	string	true	"Cluster id"
+func ReapplyAclTemplate(h *Handler, w http.ResponseWriter, r *http.Request, clusterId models.ClusterId) {
+	result, err := h.AclTemplateService.ReapplyAclTemplate(h.Ctx, clusterId)
+	if err != nil {
+		writeAclTemplateError(h, w, err, "Failed to reapply acl template")
+		return
+	}
+
+	writeJson(w, http.StatusOK, result)
+}
+
+func writeAclTemplateError(h *Handler, w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, models.ErrInvalidAclTemplate):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrClusterNotFound):
+		writeError(w, http.StatusNotFound, "Cluster not found")
+	case errors.Is(err, services.ErrClusterUsesRbac):
+		writeError(w, http.StatusConflict, err.Error())
+	default:
+		h.Logger.Error(err, message)
+		writeError(w, http.StatusInternalServerError, message)
+	}
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dfds/confluent-gateway/internal/mocks"
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUpdateAclTemplate_Success(t *testing.T) {
+	// Arrange
+	mockService := new(mocks.MockAclTemplateService)
+	mockLogger := new(mocks.MockLogger)
+	handler := &Handler{Ctx: context.Background(), Logger: mockLogger, AclTemplateService: mockService}
+
+	entries := []models.AclTemplateEntry{
+		{ResourceType: models.ResourceTypeTopic, ResourceName: "{capabilityId}", PatternType: models.PatternTypePrefix, OperationType: models.OperationTypeRead, PermissionType: models.PermissionTypeAllow},
+	}
+	template := &models.AclTemplate{ClusterId: "abc-1234", Version: 1, Entries: entries}
+
+	mockService.On("UpdateAclTemplate", mock.Anything, models.ClusterId("abc-1234"), entries).Return(template, nil)
+
+	body := `{"entries": [{"resourceType": "TOPIC", "resourceName": "{capabilityId}", "patternType": "PREFIXED", "operationType": "READ", "permissionType": "ALLOW"}]}`
+	req, err := http.NewRequest(http.MethodPut, "/clusters/abc-1234/acl-template", strings.NewReader(body))
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	// Act
+	UpdateAclTemplate(handler, rr, req, "abc-1234")
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"version":1`)
+	mockService.AssertExpectations(t)
+}
+
+func TestUpdateAclTemplate_Invalid(t *testing.T) {
+	// Arrange
+	mockService := new(mocks.MockAclTemplateService)
+	mockLogger := new(mocks.MockLogger)
+	handler := &Handler{Ctx: context.Background(), Logger: mockLogger, AclTemplateService: mockService}
+
+	mockService.On("UpdateAclTemplate", mock.Anything, models.ClusterId("abc-1234"), []models.AclTemplateEntry(nil)).Return(nil, models.ErrInvalidAclTemplate)
+
+	req, err := http.NewRequest(http.MethodPut, "/clusters/abc-1234/acl-template", strings.NewReader(`{}`))
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	// Act
+	UpdateAclTemplate(handler, rr, req, "abc-1234")
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertExpectations(t)
+}
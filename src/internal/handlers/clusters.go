@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/dfds/confluent-gateway/internal/services"
+)
+
+// ListClusters godoc
+//
+//	@Summary		List clusters
+//	@Description	Get the clusters known to the gateway, without their api keys.
+//	@Tags			clusters
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{array}		models.ClusterInfo
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/clusters [get]
+func ListClusters(h *Handler, w http.ResponseWriter, r *http.Request) {
+	clusters, err := h.ClusterService.ListClusters(h.Ctx)
+	if err != nil {
+		h.Logger.Error(err, "failed to list clusters")
+		writeError(w, http.StatusInternalServerError, "Failed to list clusters")
+		return
+	}
+
+	writeJson(w, http.StatusOK, clusters)
+}
+
+// GetCluster godoc
+//
+//	@Summary		Get a cluster
+//	@Description	Get a cluster known to the gateway, without its api keys.
+//	@Tags			clusters
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	models.ClusterInfo
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/clusters/{clusterId} [get]
+//
+//	@Param			clusterId	path	string	true	"Cluster id"
+func GetCluster(h *Handler, w http.ResponseWriter, r *http.Request, clusterId models.ClusterId) {
+	cluster, err := h.ClusterService.GetCluster(h.Ctx, clusterId)
+	if err != nil {
+		writeClusterError(h, w, err, "Failed to get cluster")
+		return
+	}
+
+	writeJson(w, http.StatusOK, cluster)
+}
+
+// CreateCluster godoc
+//
+//	@Summary		Add a cluster
+//	@Description	Add a cluster to the gateway. Its api keys are kept in the vault.
+//	@Tags			clusters
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		models.ClusterRequest	true	"Cluster"
+//	@Success		201		{object}	models.ClusterInfo
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		409		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/clusters [post]
+func CreateCluster(h *Handler, w http.ResponseWriter, r *http.Request) {
+	var request models.ClusterRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	cluster, err := h.ClusterService.CreateCluster(h.Ctx, request)
+	if err != nil {
+		writeClusterError(h, w, err, "Failed to add cluster")
+		return
+	}
+
+	writeJson(w, http.StatusCreated, cluster)
+}
+
+// UpdateCluster godoc
+//
+//	@Summary		Update a cluster
+//	@Description	Change the settings of a cluster. Api keys left out of the request are kept.
+//	@Tags			clusters
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		models.ClusterRequest	true	"Cluster"
+//	@Success		200		{object}	models.ClusterInfo
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/clusters/{clusterId} [put]
+//
+//	@Param			clusterId	path	string	true	"Cluster id"
+func UpdateCluster(h *Handler, w http.ResponseWriter, r *http.Request, clusterId models.ClusterId) {
+	var request models.ClusterRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	cluster, err := h.ClusterService.UpdateCluster(h.Ctx, clusterId, request)
+	if err != nil {
+		writeClusterError(h, w, err, "Failed to update cluster")
+		return
+	}
+
+	writeJson(w, http.StatusOK, cluster)
+}
+
+// DeleteCluster godoc
+//
+//	@Summary		Remove a cluster
+//	@Description	Remove a cluster and its api keys from the gateway. Clusters with topics or cluster accesses cannot be removed.
+//	@Tags			clusters
+//	@Accept			json
+//	@Produce		json
+//	@Success		204
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		409	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/clusters/{clusterId} [delete]
+//
+//	@Param			clusterId	path	string	true	"Cluster id"
+func DeleteCluster(h *Handler, w http.ResponseWriter, r *http.Request, clusterId models.ClusterId) {
+	if err := h.ClusterService.DeleteCluster(h.Ctx, clusterId); err != nil {
+		writeClusterError(h, w, err, "Failed to remove cluster")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeClusterError(h *Handler, w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, models.ErrInvalidCluster):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrClusterNotFound):
+		writeError(w, http.StatusNotFound, "Cluster not found")
+	case errors.Is(err, services.ErrClusterAlreadyExists), errors.Is(err, services.ErrClusterInUse):
+		writeError(w, http.StatusConflict, err.Error())
+	default:
+		h.Logger.Error(err, message)
+		writeError(w, http.StatusInternalServerError, message)
+	}
+}
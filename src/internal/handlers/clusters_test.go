@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dfds/confluent-gateway/internal/mocks"
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/dfds/confluent-gateway/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateCluster_Success(t *testing.T) {
+	// Arrange
+	mockService := new(mocks.MockClusterService)
+	mockLogger := new(mocks.MockLogger)
+	handler := &Handler{Ctx: context.Background(), Logger: mockLogger, ClusterService: mockService}
+
+	request := models.ClusterRequest{
+		ClusterId:         "abc-1234",
+		Name:              "prod",
+		AdminApiEndpoint:  "http://localhost:5051",
+		AdminApiKey:       &models.ApiKeyRequest{Key: "admin_user", Secret: "admin_pass"},
+		BootstrapEndpoint: "localhost:9092",
+	}
+	cluster := &models.ClusterInfo{ClusterId: "abc-1234", Name: "prod", AdminApiEndpoint: "http://localhost:5051", BootstrapEndpoint: "localhost:9092"}
+
+	mockService.On("CreateCluster", mock.Anything, request).Return(cluster, nil)
+
+	body := `{"id": "abc-1234", "name": "prod", "adminApiEndpoint": "http://localhost:5051", "adminApiKey": {"key": "admin_user", "secret": "admin_pass"}, "bootstrapEndpoint": "localhost:9092"}`
+	req, err := http.NewRequest(http.MethodPost, "/clusters", strings.NewReader(body))
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	// Act
+	CreateCluster(handler, rr, req)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "admin_pass")
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateCluster_AlreadyExists(t *testing.T) {
+	// Arrange
+	mockService := new(mocks.MockClusterService)
+	mockLogger := new(mocks.MockLogger)
+	handler := &Handler{Ctx: context.Background(), Logger: mockLogger, ClusterService: mockService}
+
+	mockService.On("CreateCluster", mock.Anything, mock.Anything).Return(nil, services.ErrClusterAlreadyExists)
+
+	req, err := http.NewRequest(http.MethodPost, "/clusters", strings.NewReader(`{"id": "abc-1234"}`))
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	// Act
+	CreateCluster(handler, rr, req)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestDeleteCluster_NotFound(t *testing.T) {
+	// Arrange
+	mockService := new(mocks.MockClusterService)
+	mockLogger := new(mocks.MockLogger)
+	handler := &Handler{Ctx: context.Background(), Logger: mockLogger, ClusterService: mockService}
+
+	mockService.On("DeleteCluster", mock.Anything, models.ClusterId("abc-1234")).Return(services.ErrClusterNotFound)
+
+	req, err := http.NewRequest(http.MethodDelete, "/clusters/abc-1234", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	// Act
+	DeleteCluster(handler, rr, req, "abc-1234")
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/dfds/confluent-gateway/internal/services"
+	"github.com/dfds/confluent-gateway/internal/storage"
+)
+
+// RequestAccessGrant godoc
+//
+//	@Summary		Grant a capability access to a private topic
+//	@Description	Let a capability consume a private topic of another capability with a consumer group. The grant waits for the owner of the topic to approve it before the ACL entries are created.
+//	@Tags			grants
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		models.AccessGrantRequest	true	"Access grant"
+//	@Success		201		{object}	models.AccessGrant
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		409		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/grants [post]
+func RequestAccessGrant(h *Handler, w http.ResponseWriter, r *http.Request) {
+	var request models.AccessGrantRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	grant, err := h.GrantService.RequestGrant(h.Ctx, request)
+	if err != nil {
+		writeGrantError(h, w, err, "Failed to request access grant")
+		return
+	}
+
+	writeJson(w, http.StatusCreated, grant)
+}
+
+// ListAccessGrants godoc
+//
+//	@Summary		List the access grants of a capability
+//	@Description	List the grants a capability has been given and the grants it has given on its topics.
+//	@Tags			grants
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{array}		models.AccessGrant
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/capabilities/{capabilityId}/grants [get]
+//
+//	@Param			capabilityId	path	string	true	"Capability id"
+func ListAccessGrants(h *Handler, w http.ResponseWriter, r *http.Request, capabilityId models.CapabilityId) {
+	grants, err := h.GrantService.ListGrants(h.Ctx, capabilityId)
+	if err != nil {
+		writeGrantError(h, w, err, "Failed to list access grants")
+		return
+	}
+
+	writeJson(w, http.StatusOK, grants)
+}
+
+// ApproveAccessGrant godoc
+//
+//	@Summary		Approve an access grant
+//	@Description	Approve a grant on behalf of the owner of the topic, creating its ACL entries.
+//	@Tags			grants
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	models.AccessGrant
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/grants/{grantId}/approve [post]
+//
+//	@Param			grantId	path	string	true	"Grant id"
+func ApproveAccessGrant(h *Handler, w http.ResponseWriter, r *http.Request, grantId string) {
+	grant, err := h.GrantService.ApproveGrant(h.Ctx, grantId)
+	if err != nil {
+		writeGrantError(h, w, err, "Failed to approve access grant")
+		return
+	}
+
+	writeJson(w, http.StatusOK, grant)
+}
+
+// RevokeAccessGrant godoc
+//
+//	@Summary		Revoke an access grant
+//	@Description	Revoke a grant, deleting its ACL entries.
+//	@Tags			grants
+//	@Accept			json
+//	@Produce		json
+//	@Success		204
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/grants/{grantId} [delete]
+//
+//	@Param			grantId	path	string	true	"Grant id"
+func RevokeAccessGrant(h *Handler, w http.ResponseWriter, r *http.Request, grantId string) {
+	if err := h.GrantService.RevokeGrant(h.Ctx, grantId); err != nil {
+		writeGrantError(h, w, err, "Failed to revoke access grant")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeGrantError(h *Handler, w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, models.ErrInvalidAccessGrant), errors.Is(err, services.ErrAccessGrantToOwner), errors.Is(err, services.ErrNoClusterAccess):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, models.ErrAccessGrantAlreadyExists):
+		writeError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, storage.ErrTopicNotFound):
+		writeError(w, http.StatusNotFound, "Topic not found")
+	case errors.Is(err, storage.ErrServiceAccountNotFound):
+		writeError(w, http.StatusNotFound, "Service account not found")
+	case errors.Is(err, services.ErrAccessGrantNotFound):
+		writeError(w, http.StatusNotFound, "Access grant not found")
+	default:
+		h.Logger.Error(err, message)
+		writeError(w, http.StatusInternalServerError, message)
+	}
+}
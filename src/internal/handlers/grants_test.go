@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dfds/confluent-gateway/internal/mocks"
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/dfds/confluent-gateway/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRequestAccessGrant_Success(t *testing.T) {
+	// Arrange
+	mockService := new(mocks.MockGrantService)
+	mockLogger := new(mocks.MockLogger)
+	handler := &Handler{Ctx: context.Background(), Logger: mockLogger, GrantService: mockService}
+
+	request := models.AccessGrantRequest{ClusterId: "abc-1234", TopicName: "owner-cap.orders", GranteeCapabilityId: "grantee-cap", ConsumerGroup: "grantee-cap.consumer"}
+	grant := &models.AccessGrant{Id: "some-grant-id", ClusterId: "abc-1234", TopicName: "owner-cap.orders"}
+
+	mockService.On("RequestGrant", mock.Anything, request).Return(grant, nil)
+
+	body := `{"clusterId": "abc-1234", "topicName": "owner-cap.orders", "granteeCapabilityId": "grantee-cap", "consumerGroup": "grantee-cap.consumer"}`
+	req, err := http.NewRequest(http.MethodPost, "/grants", strings.NewReader(body))
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	// Act
+	RequestAccessGrant(handler, rr, req)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"id":"some-grant-id"`)
+	mockService.AssertExpectations(t)
+}
+
+func TestRequestAccessGrant_AlreadyExists(t *testing.T) {
+	// Arrange
+	mockService := new(mocks.MockGrantService)
+	mockLogger := new(mocks.MockLogger)
+	handler := &Handler{Ctx: context.Background(), Logger: mockLogger, GrantService: mockService}
+
+	mockService.On("RequestGrant", mock.Anything, mock.Anything).Return(nil, models.ErrAccessGrantAlreadyExists)
+
+	req, err := http.NewRequest(http.MethodPost, "/grants", strings.NewReader(`{}`))
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	// Act
+	RequestAccessGrant(handler, rr, req)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestRevokeAccessGrant_NotFound(t *testing.T) {
+	// Arrange
+	mockService := new(mocks.MockGrantService)
+	mockLogger := new(mocks.MockLogger)
+	handler := &Handler{Ctx: context.Background(), Logger: mockLogger, GrantService: mockService}
+
+	mockService.On("RevokeGrant", mock.Anything, "some-grant-id").Return(services.ErrAccessGrantNotFound)
+
+	req, err := http.NewRequest(http.MethodDelete, "/grants/some-grant-id", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	// Act
+	RevokeAccessGrant(handler, rr, req, "some-grant-id")
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockService.AssertExpectations(t)
+}
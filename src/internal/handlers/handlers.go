@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
 
 	"github.com/dfds/confluent-gateway/internal/services"
 	"github.com/dfds/confluent-gateway/logging"
@@ -12,11 +14,19 @@ type ErrorResponse struct {
 }
 
 type Handler struct {
-	Ctx           context.Context
-	Logger        logging.Logger
-	SchemaService services.SchemaServiceInterface
+	Ctx                context.Context
+	Logger             logging.Logger
+	SchemaService      services.SchemaServiceInterface
+	TopicService       services.TopicServiceInterface
+	AccessService      services.AccessServiceInterface
+	PlanService        services.PlanServiceInterface
+	ClusterService     services.ClusterServiceInterface
+	AclTemplateService services.AclTemplateServiceInterface
+	GrantService       services.GrantServiceInterface
 }
 
+// NewHandler returns a handler with the schema service only. Handlers using the other services are built from a
+// Handler literal.
 func NewHandler(ctx context.Context, logger logging.Logger, schemaService services.SchemaServiceInterface) *Handler {
 	return &Handler{
 		Ctx:           ctx,
@@ -24,3 +34,13 @@ func NewHandler(ctx context.Context, logger logging.Logger, schemaService servic
 		SchemaService: schemaService,
 	}
 }
+
+func writeJson(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJson(w, status, ErrorResponse{Message: message})
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/dfds/confluent-gateway/internal/services"
+)
+
+// ListPlanEventTypes godoc
+//
+//	@Summary		List the events that can be planned
+//	@Description	List the event types accepted by the plan endpoint.
+//	@Tags			plans
+//	@Produce		json
+//	@Success		200	{array}	string
+//	@Router			/plans [get]
+func ListPlanEventTypes(h *Handler, w http.ResponseWriter, r *http.Request) {
+	writeJson(w, http.StatusOK, h.PlanService.EventTypes())
+}
+
+// CreatePlan godoc
+//
+//	@Summary		Plan the handling of an event
+//	@Description	Show the Confluent calls, vault changes, database changes and events the gateway would make when handling an event, without making them. The body is the data of the event.
+//	@Tags			plans
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	models.Plan
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/plans/{eventType} [post]
+//
+//	@Param			eventType	path	string	true	"Event type, e.g. topic-requested"
+func CreatePlan(h *Handler, w http.ResponseWriter, r *http.Request, eventType string) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	plan, err := h.PlanService.Plan(h.Ctx, eventType, data)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownEventType) {
+			writeError(w, http.StatusNotFound, "Unknown event type")
+			return
+		}
+		if errors.Is(err, services.ErrInvalidEvent) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.Logger.Error(err, "failed to plan event")
+		writeError(w, http.StatusInternalServerError, "Failed to plan event")
+		return
+	}
+
+	writeJson(w, http.StatusOK, plan)
+}
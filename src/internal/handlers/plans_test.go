@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dfds/confluent-gateway/internal/mocks"
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/dfds/confluent-gateway/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreatePlan_Success(t *testing.T) {
+	// Arrange
+	mockService := new(mocks.MockPlanService)
+	mockLogger := new(mocks.MockLogger)
+	handler := &Handler{Ctx: context.Background(), Logger: mockLogger, PlanService: mockService}
+
+	plan := &models.Plan{
+		EventType: "topic-requested",
+		Actions:   []models.PlanAction{{Kind: models.PlanActionConfluent, Operation: "CreateTopic", Arguments: map[string]any{"name": "some-topic"}}},
+	}
+
+	mockService.On("Plan", mock.Anything, "topic-requested", []byte(`{"topicName":"some-topic"}`)).Return(plan, nil)
+
+	req, err := http.NewRequest(http.MethodPost, "/plans/topic-requested", strings.NewReader(`{"topicName":"some-topic"}`))
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	// Act
+	CreatePlan(handler, rr, req, "topic-requested")
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"eventType": "topic-requested", "actions": [{"kind": "confluent", "operation": "CreateTopic", "arguments": {"name": "some-topic"}}]}`, rr.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestCreatePlan_UnknownEventType(t *testing.T) {
+	// Arrange
+	mockService := new(mocks.MockPlanService)
+	mockLogger := new(mocks.MockLogger)
+	handler := &Handler{Ctx: context.Background(), Logger: mockLogger, PlanService: mockService}
+
+	mockService.On("Plan", mock.Anything, "something-else", []byte(`{}`)).Return(nil, services.ErrUnknownEventType)
+
+	req, err := http.NewRequest(http.MethodPost, "/plans/something-else", strings.NewReader(`{}`))
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	// Act
+	CreatePlan(handler, rr, req, "something-else")
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockService.AssertExpectations(t)
+}
@@ -2,33 +2,298 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
+	"github.com/dfds/confluent-gateway/internal/confluent"
 	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/dfds/confluent-gateway/internal/schema"
+	"github.com/dfds/confluent-gateway/internal/services"
+	"github.com/dfds/confluent-gateway/internal/storage"
 )
 
 // ListSchemas godoc
 //
 //	@Summary		List schemas from Confluent Cloud
-//	@Description	Get a list of schemas from the Confluent Cloud API.
+//	@Description	Get a list of schemas from the schema registry of a cluster.
 //	@Tags			schemas
 //	@Accept			json
 //	@Produce		json
 //	@Success		200	{array}		models.Schema
+//	@Failure		404	{object}	ErrorResponse
 //	@Failure		500	{object}	ErrorResponse
-//	@Router			/schemas [get]
+//	@Router			/clusters/{clusterId}/schemas [get]
 //
+//	@Param			clusterId		path	string	true	"Cluster id"
 //	@Param			subjectPrefix	query	string	false	"Subject prefix to filter schemas by"
-func ListSchemas(h *Handler, w http.ResponseWriter, r *http.Request, subjectPrefix string, clusterId models.ClusterId) {
+//	@Param			offset			query	int		false	"Number of schemas to skip"
+//	@Param			limit			query	int		false	"Maximum number of schemas to return"
+func ListSchemas(h *Handler, w http.ResponseWriter, r *http.Request, subjectPrefix string, clusterId models.ClusterId, offset int, limit int) {
+	schemas, err := h.SchemaService.ListSchemas(h.Ctx, subjectPrefix, clusterId, offset, limit)
+	if err != nil {
+		h.Logger.Error(err, "failed to list schemas")
+		writeError(w, schemaRegistryErrorStatus(err), "Failed to list schemas")
+		return
+	}
 
-	schemas, err := h.SchemaService.ListSchemas(h.Ctx, subjectPrefix, clusterId)
+	writeJson(w, http.StatusOK, schemas)
+}
 
+// ListSubjects godoc
+//
+//	@Summary		List subjects
+//	@Description	Get the subjects in the schema registry of a cluster.
+//	@Tags			schemas
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{array}		string
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/clusters/{clusterId}/subjects [get]
+//
+//	@Param			clusterId		path	string	true	"Cluster id"
+//	@Param			subjectPrefix	query	string	false	"Subject prefix to filter subjects by"
+func ListSubjects(h *Handler, w http.ResponseWriter, r *http.Request, clusterId models.ClusterId, subjectPrefix string) {
+	subjects, err := h.SchemaService.ListSubjects(h.Ctx, clusterId, subjectPrefix)
 	if err != nil {
-		h.Logger.Error(err, "failed to list schemas")
-		http.Error(w, "Failed to list schemas", http.StatusInternalServerError)
+		h.Logger.Error(err, "failed to list subjects")
+		writeError(w, schemaRegistryErrorStatus(err), "Failed to list subjects")
+		return
+	}
+
+	writeJson(w, http.StatusOK, subjects)
+}
+
+// ListSubjectVersions godoc
+//
+//	@Summary		List the versions of a subject
+//	@Description	Get the registered versions of a subject in the schema registry of a cluster.
+//	@Tags			schemas
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{array}		int
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/clusters/{clusterId}/subjects/{subject}/versions [get]
+//
+//	@Param			clusterId	path	string	true	"Cluster id"
+//	@Param			subject		path	string	true	"Subject"
+func ListSubjectVersions(h *Handler, w http.ResponseWriter, r *http.Request, clusterId models.ClusterId, subject string) {
+	versions, err := h.SchemaService.ListSubjectVersions(h.Ctx, clusterId, subject)
+	if err != nil {
+		h.Logger.Error(err, "failed to list subject versions")
+		writeError(w, schemaRegistryErrorStatus(err), "Failed to list subject versions")
+		return
+	}
+
+	writeJson(w, http.StatusOK, versions)
+}
+
+// GetSchemaVersion godoc
+//
+//	@Summary		Get a version of a subject
+//	@Description	Get the schema registered as a specific version of a subject, or "latest".
+//	@Tags			schemas
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	models.Schema
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/clusters/{clusterId}/subjects/{subject}/versions/{version} [get]
+//
+//	@Param			clusterId	path	string	true	"Cluster id"
+//	@Param			subject		path	string	true	"Subject"
+//	@Param			version		path	string	true	"Version number or latest"
+func GetSchemaVersion(h *Handler, w http.ResponseWriter, r *http.Request, clusterId models.ClusterId, subject string, version string) {
+	schema, err := h.SchemaService.GetSchemaVersion(h.Ctx, clusterId, subject, version)
+	if err != nil {
+		h.Logger.Error(err, "failed to get schema version")
+		writeError(w, schemaRegistryErrorStatus(err), "Failed to get schema version")
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(schemas)
+	writeJson(w, http.StatusOK, schema)
+}
+
+// GetCompatibilityLevel godoc
+//
+//	@Summary		Get the compatibility level of a subject
+//	@Description	Get the compatibility level in effect for a subject, falling back to the global level.
+//	@Tags			schemas
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	models.SubjectCompatibility
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/clusters/{clusterId}/subjects/{subject}/compatibility [get]
+//
+//	@Param			clusterId	path	string	true	"Cluster id"
+//	@Param			subject		path	string	true	"Subject"
+func GetCompatibilityLevel(h *Handler, w http.ResponseWriter, r *http.Request, clusterId models.ClusterId, subject string) {
+	compatibility, err := h.SchemaService.GetCompatibilityLevel(h.Ctx, clusterId, subject)
+	if err != nil {
+		h.Logger.Error(err, "failed to get compatibility level")
+		writeError(w, schemaRegistryErrorStatus(err), "Failed to get compatibility level")
+		return
+	}
+
+	writeJson(w, http.StatusOK, compatibility)
+}
+
+// SetCompatibilityLevel godoc
+//
+//	@Summary		Set the compatibility level of a subject
+//	@Description	Override the compatibility level of a subject in the schema registry.
+//	@Tags			schemas
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		models.CompatibilityLevelRequest	true	"Compatibility level"
+//	@Success		200		{object}	models.SubjectCompatibility
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/clusters/{clusterId}/subjects/{subject}/compatibility [put]
+//
+//	@Param			clusterId	path	string	true	"Cluster id"
+//	@Param			subject		path	string	true	"Subject"
+func SetCompatibilityLevel(h *Handler, w http.ResponseWriter, r *http.Request, clusterId models.ClusterId, subject string) {
+	var request models.CompatibilityLevelRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	compatibility, err := h.SchemaService.SetCompatibilityLevel(h.Ctx, clusterId, subject, request.CompatibilityLevel)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCompatibilityLevel) {
+			writeError(w, http.StatusBadRequest, "Invalid compatibility level")
+			return
+		}
+		h.Logger.Error(err, "failed to set compatibility level")
+		writeError(w, schemaRegistryErrorStatus(err), "Failed to set compatibility level")
+		return
+	}
+
+	writeJson(w, http.StatusOK, compatibility)
+}
+
+// GetCapabilitySchemaSettings godoc
+//
+//	@Summary		Get the schema settings of a capability
+//	@Description	Get the defaults used when registering the message contracts of a capability.
+//	@Tags			schemas
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	models.CapabilitySchemaSettings
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/capabilities/{capabilityId}/schema-settings [get]
+//
+//	@Param			capabilityId	path	string	true	"Capability id"
+func GetCapabilitySchemaSettings(h *Handler, w http.ResponseWriter, r *http.Request, capabilityId models.CapabilityId) {
+	settings, err := h.SchemaService.GetCapabilitySchemaSettings(h.Ctx, capabilityId)
+	if err != nil {
+		h.Logger.Error(err, "failed to get schema settings")
+		writeError(w, http.StatusInternalServerError, "Failed to get schema settings")
+		return
+	}
+
+	writeJson(w, http.StatusOK, settings)
+}
+
+// UpdateCapabilitySchemaSettings godoc
+//
+//	@Summary		Update the schema settings of a capability
+//	@Description	Set the compatibility level applied to new subjects of a capability. An empty level falls back to the registry default.
+//	@Tags			schemas
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		models.CompatibilityLevelRequest	true	"Compatibility level"
+//	@Success		200		{object}	models.CapabilitySchemaSettings
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/capabilities/{capabilityId}/schema-settings [put]
+//
+//	@Param			capabilityId	path	string	true	"Capability id"
+func UpdateCapabilitySchemaSettings(h *Handler, w http.ResponseWriter, r *http.Request, capabilityId models.CapabilityId) {
+	var request models.CompatibilityLevelRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	settings, err := h.SchemaService.SaveCapabilitySchemaSettings(h.Ctx, capabilityId, request.CompatibilityLevel)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCompatibilityLevel) {
+			writeError(w, http.StatusBadRequest, "Invalid compatibility level")
+			return
+		}
+		h.Logger.Error(err, "failed to save schema settings")
+		writeError(w, http.StatusInternalServerError, "Failed to save schema settings")
+		return
+	}
+
+	writeJson(w, http.StatusOK, settings)
+}
+
+// CheckSchemaCompatibility godoc
+//
+//	@Summary		Check a schema before requesting a message contract
+//	@Description	Test a schema against the latest version of the subject it would be registered under, without registering it.
+//	@Tags			schemas
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		models.SchemaCompatibilityRequest	true	"Message contract to check"
+//	@Success		200		{object}	models.CompatibilityResult
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		422		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/schemas/compatibility [post]
+func CheckSchemaCompatibility(h *Handler, w http.ResponseWriter, r *http.Request) {
+	var request models.SchemaCompatibilityRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if request.TopicId == "" || request.MessageType == "" || request.Schema == "" {
+		writeError(w, http.StatusBadRequest, "kafkaTopicId, messageType and schema are required")
+		return
+	}
+
+	result, err := h.SchemaService.CheckCompatibility(h.Ctx, request)
+	if err != nil {
+		if errors.Is(err, storage.ErrTopicNotFound) {
+			writeError(w, http.StatusNotFound, "Topic not found")
+			return
+		}
+
+		if errors.Is(err, schema.ErrInvalidSchema) {
+			writeError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+
+		var clientError *confluent.ClientError
+		if errors.As(err, &clientError) && clientError.Status == http.StatusUnprocessableEntity {
+			writeError(w, http.StatusUnprocessableEntity, clientError.Message)
+			return
+		}
+
+		h.Logger.Error(err, "failed to check schema compatibility")
+		writeError(w, schemaRegistryErrorStatus(err), "Failed to check schema compatibility")
+		return
+	}
+
+	writeJson(w, http.StatusOK, result)
+}
+
+func schemaRegistryErrorStatus(err error) int {
+	var clientError *confluent.ClientError
+	if errors.As(err, &clientError) && clientError.Status == http.StatusNotFound {
+		return http.StatusNotFound
+	}
+	if errors.Is(err, confluent.ErrNoSchemaRegistry) {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
 }
@@ -6,10 +6,13 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/dfds/confluent-gateway/internal/confluent"
 	"github.com/dfds/confluent-gateway/internal/mocks"
 	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/dfds/confluent-gateway/internal/services"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -25,7 +28,7 @@ func TestListSchemas_Success(t *testing.T) {
 		{ID: 2, Subject: "Schema2"},
 	}
 
-	mockService.On("ListSchemas", mock.Anything, "", mock.Anything).Return(schemas, nil)
+	mockService.On("ListSchemas", mock.Anything, "", mock.Anything, 0, 0).Return(schemas, nil)
 
 	req, err := http.NewRequest(http.MethodGet, "/clusters/abc-1234/schemas", nil)
 	assert.NoError(t, err)
@@ -33,7 +36,7 @@ func TestListSchemas_Success(t *testing.T) {
 	rr := httptest.NewRecorder()
 
 	// Act
-	ListSchemas(handler, rr, req, "", "")
+	ListSchemas(handler, rr, req, "", "", 0, 0)
 
 	// Assert
 	assert.Equal(t, http.StatusOK, rr.Code)
@@ -50,7 +53,7 @@ func TestListSchemas_Error(t *testing.T) {
 
 	mockLogger.On("Error", mock.Anything, "failed to list schemas", mock.Anything).Return(nil)
 
-	mockService.On("ListSchemas", mock.Anything, "", mock.Anything).Return(nil, errors.New("failed to list schemas"))
+	mockService.On("ListSchemas", mock.Anything, "", mock.Anything, 0, 0).Return(nil, errors.New("failed to list schemas"))
 
 	req, err := http.NewRequest(http.MethodGet, "/clusters/abc-1234/schemas", nil)
 	assert.NoError(t, err)
@@ -58,9 +61,146 @@ func TestListSchemas_Error(t *testing.T) {
 	rr := httptest.NewRecorder()
 
 	// Act
-	ListSchemas(handler, rr, req, "", "")
+	ListSchemas(handler, rr, req, "", "", 0, 0)
 
 	// Assert
 	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.JSONEq(t, `{"message": "Failed to list schemas"}`, rr.Body.String())
 	mockService.AssertExpectations(t)
 }
+
+func TestGetSchemaVersion_NotFound(t *testing.T) {
+	// Arrange
+	mockService := new(mocks.MockSchemaService)
+	mockLogger := new(mocks.MockLogger)
+	handler := NewHandler(context.Background(), mockLogger, mockService)
+
+	mockLogger.On("Error", mock.Anything, "failed to get schema version", mock.Anything).Return(nil)
+
+	mockService.On("GetSchemaVersion", mock.Anything, models.ClusterId("abc-1234"), "some-subject", "3").Return(nil, confluent.NewClientError("/subjects/some-subject/versions/3", http.StatusNotFound, "not found"))
+
+	req, err := http.NewRequest(http.MethodGet, "/clusters/abc-1234/subjects/some-subject/versions/3", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	// Act
+	GetSchemaVersion(handler, rr, req, "abc-1234", "some-subject", "3")
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetCompatibilityLevel_Success(t *testing.T) {
+	// Arrange
+	mockService := new(mocks.MockSchemaService)
+	mockLogger := new(mocks.MockLogger)
+	handler := NewHandler(context.Background(), mockLogger, mockService)
+
+	compatibility := &models.SubjectCompatibility{Subject: "some-subject", CompatibilityLevel: "BACKWARD"}
+
+	mockService.On("GetCompatibilityLevel", mock.Anything, models.ClusterId("abc-1234"), "some-subject").Return(compatibility, nil)
+
+	req, err := http.NewRequest(http.MethodGet, "/clusters/abc-1234/subjects/some-subject/compatibility", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	// Act
+	GetCompatibilityLevel(handler, rr, req, "abc-1234", "some-subject")
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"subject": "some-subject", "compatibilityLevel": "BACKWARD"}`, rr.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestSetCompatibilityLevel_InvalidLevel(t *testing.T) {
+	// Arrange
+	mockService := new(mocks.MockSchemaService)
+	mockLogger := new(mocks.MockLogger)
+	handler := NewHandler(context.Background(), mockLogger, mockService)
+
+	mockService.On("SetCompatibilityLevel", mock.Anything, models.ClusterId("abc-1234"), "some-subject", models.CompatibilityLevel("SOMETIMES")).Return(nil, services.ErrInvalidCompatibilityLevel)
+
+	req, err := http.NewRequest(http.MethodPut, "/clusters/abc-1234/subjects/some-subject/compatibility", strings.NewReader(`{"compatibilityLevel":"SOMETIMES"}`))
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	// Act
+	SetCompatibilityLevel(handler, rr, req, "abc-1234", "some-subject")
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestUpdateCapabilitySchemaSettings_Success(t *testing.T) {
+	// Arrange
+	mockService := new(mocks.MockSchemaService)
+	mockLogger := new(mocks.MockLogger)
+	handler := NewHandler(context.Background(), mockLogger, mockService)
+
+	settings := &models.CapabilitySchemaSettings{CapabilityId: "some-capability", CompatibilityLevel: models.CompatibilityFull}
+
+	mockService.On("SaveCapabilitySchemaSettings", mock.Anything, models.CapabilityId("some-capability"), models.CompatibilityFull).Return(settings, nil)
+
+	req, err := http.NewRequest(http.MethodPut, "/capabilities/some-capability/schema-settings", strings.NewReader(`{"compatibilityLevel":"FULL"}`))
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	// Act
+	UpdateCapabilitySchemaSettings(handler, rr, req, "some-capability")
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"capabilityId": "some-capability", "compatibilityLevel": "FULL", "updatedAt": "0001-01-01T00:00:00Z"}`, rr.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestCheckSchemaCompatibility_Incompatible(t *testing.T) {
+	// Arrange
+	mockService := new(mocks.MockSchemaService)
+	mockLogger := new(mocks.MockLogger)
+	handler := NewHandler(context.Background(), mockLogger, mockService)
+
+	request := models.SchemaCompatibilityRequest{TopicId: "topic-id", MessageType: "some-event", Schema: "{}"}
+	result := &models.CompatibilityResult{Subject: "some-topic-some-event", IsCompatible: false, Messages: []string{"property removed"}}
+
+	mockService.On("CheckCompatibility", mock.Anything, request).Return(result, nil)
+
+	req, err := http.NewRequest(http.MethodPost, "/schemas/compatibility", strings.NewReader(`{"kafkaTopicId":"topic-id","messageType":"some-event","schema":"{}"}`))
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	// Act
+	CheckSchemaCompatibility(handler, rr, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"subject": "some-topic-some-event", "isCompatible": false, "messages": ["property removed"]}`, rr.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestCheckSchemaCompatibility_BadRequest(t *testing.T) {
+	// Arrange
+	mockService := new(mocks.MockSchemaService)
+	mockLogger := new(mocks.MockLogger)
+	handler := NewHandler(context.Background(), mockLogger, mockService)
+
+	req, err := http.NewRequest(http.MethodPost, "/schemas/compatibility", strings.NewReader(`{"kafkaTopicId":"topic-id"}`))
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	// Act
+	CheckSchemaCompatibility(handler, rr, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertNotCalled(t, "CheckCompatibility", mock.Anything, mock.Anything)
+}
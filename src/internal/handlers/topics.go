@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/dfds/confluent-gateway/internal/storage"
+)
+
+// ListCapabilityTopics godoc
+//
+//	@Summary		List topics owned by a capability
+//	@Description	Get the topics provisioned for a capability, optionally merged with live data from the cluster.
+//	@Tags			topics
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{array}		models.TopicInfo
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/capabilities/{capabilityId}/topics [get]
+//
+//	@Param			capabilityId	path	string	true	"Capability id"
+//	@Param			live			query	bool	false	"Include partitions and configs from the cluster"
+func ListCapabilityTopics(h *Handler, w http.ResponseWriter, r *http.Request, capabilityId models.CapabilityId, live bool) {
+	topics, err := h.TopicService.ListTopics(h.Ctx, capabilityId, live)
+	if err != nil {
+		h.Logger.Error(err, "failed to list topics")
+		writeError(w, http.StatusInternalServerError, "Failed to list topics")
+		return
+	}
+
+	writeJson(w, http.StatusOK, topics)
+}
+
+// GetClusterTopic godoc
+//
+//	@Summary		Get a topic on a cluster
+//	@Description	Get a provisioned topic by name, optionally merged with live data from the cluster.
+//	@Tags			topics
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	models.TopicInfo
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/clusters/{clusterId}/topics/{name} [get]
+//
+//	@Param			clusterId	path	string	true	"Cluster id"
+//	@Param			name		path	string	true	"Topic name"
+//	@Param			live		query	bool	false	"Include partitions and configs from the cluster"
+func GetClusterTopic(h *Handler, w http.ResponseWriter, r *http.Request, clusterId models.ClusterId, name string, live bool) {
+	topic, err := h.TopicService.GetTopic(h.Ctx, clusterId, name, live)
+	if err != nil {
+		if errors.Is(err, storage.ErrTopicNotFound) {
+			writeError(w, http.StatusNotFound, "Topic not found")
+			return
+		}
+		h.Logger.Error(err, "failed to get topic")
+		writeError(w, http.StatusInternalServerError, "Failed to get topic")
+		return
+	}
+
+	writeJson(w, http.StatusOK, topic)
+}
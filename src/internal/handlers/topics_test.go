@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dfds/confluent-gateway/internal/mocks"
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/dfds/confluent-gateway/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestListCapabilityTopics_Success(t *testing.T) {
+	// Arrange
+	mockService := new(mocks.MockTopicService)
+	mockLogger := new(mocks.MockLogger)
+	handler := &Handler{Ctx: context.Background(), Logger: mockLogger, TopicService: mockService}
+
+	topics := []models.TopicInfo{
+		{Id: "1", CapabilityId: "some-capability", Name: "some-capability.topic-1"},
+		{Id: "2", CapabilityId: "some-capability", Name: "some-capability.topic-2"},
+	}
+
+	mockService.On("ListTopics", mock.Anything, models.CapabilityId("some-capability"), true).Return(topics, nil)
+
+	req, err := http.NewRequest(http.MethodGet, "/capabilities/some-capability/topics?live=true", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	// Act
+	ListCapabilityTopics(handler, rr, req, "some-capability", true)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rr.Code)
+	expectedBody, _ := json.Marshal(topics)
+	assert.JSONEq(t, string(expectedBody), rr.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestListCapabilityTopics_Error(t *testing.T) {
+	// Arrange
+	mockService := new(mocks.MockTopicService)
+	mockLogger := new(mocks.MockLogger)
+	handler := &Handler{Ctx: context.Background(), Logger: mockLogger, TopicService: mockService}
+
+	mockLogger.On("Error", mock.Anything, "failed to list topics", mock.Anything).Return(nil)
+	mockService.On("ListTopics", mock.Anything, models.CapabilityId("some-capability"), false).Return(nil, errors.New("boom"))
+
+	req, err := http.NewRequest(http.MethodGet, "/capabilities/some-capability/topics", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	// Act
+	ListCapabilityTopics(handler, rr, req, "some-capability", false)
+
+	// Assert
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.JSONEq(t, `{"message": "Failed to list topics"}`, rr.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestGetClusterTopic_NotFound(t *testing.T) {
+	// Arrange
+	mockService := new(mocks.MockTopicService)
+	mockLogger := new(mocks.MockLogger)
+	handler := &Handler{Ctx: context.Background(), Logger: mockLogger, TopicService: mockService}
+
+	mockService.On("GetTopic", mock.Anything, models.ClusterId("abc-1234"), "some-topic", false).Return(nil, storage.ErrTopicNotFound)
+
+	req, err := http.NewRequest(http.MethodGet, "/clusters/abc-1234/topics/some-topic", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	// Act
+	GetClusterTopic(handler, rr, req, "abc-1234", "some-topic", false)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,37 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAccessService struct {
+	mock.Mock
+}
+
+func (m *MockAccessService) GetAccess(ctx context.Context, capabilityId models.CapabilityId, applicationName string) (*models.AccessInfo, error) {
+	args := m.Called(ctx, capabilityId, applicationName)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*models.AccessInfo), args.Error(1)
+}
+
+func (m *MockAccessService) ListRoleBindings(ctx context.Context, capabilityId models.CapabilityId) ([]models.RoleBindingInfo, error) {
+	args := m.Called(ctx, capabilityId)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]models.RoleBindingInfo), args.Error(1)
+}
+
+func (m *MockAccessService) DeleteRoleBinding(ctx context.Context, capabilityId models.CapabilityId, roleBindingId string) error {
+	args := m.Called(ctx, capabilityId, roleBindingId)
+	return args.Error(0)
+}
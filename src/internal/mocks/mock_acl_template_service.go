@@ -0,0 +1,42 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAclTemplateService struct {
+	mock.Mock
+}
+
+func (m *MockAclTemplateService) GetAclTemplate(ctx context.Context, clusterId models.ClusterId) (*models.AclTemplate, error) {
+	args := m.Called(ctx, clusterId)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*models.AclTemplate), args.Error(1)
+}
+
+func (m *MockAclTemplateService) UpdateAclTemplate(ctx context.Context, clusterId models.ClusterId, entries []models.AclTemplateEntry) (*models.AclTemplate, error) {
+	args := m.Called(ctx, clusterId, entries)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*models.AclTemplate), args.Error(1)
+}
+
+func (m *MockAclTemplateService) ReapplyAclTemplate(ctx context.Context, clusterId models.ClusterId) (*models.AclTemplateReapplyResult, error) {
+	args := m.Called(ctx, clusterId)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*models.AclTemplateReapplyResult), args.Error(1)
+}
@@ -11,8 +11,8 @@ type MockClient struct {
 	mock.Mock
 }
 
-func (m *MockClient) ListSchemas(ctx context.Context, subjectPrefix string, clusterId models.ClusterId) ([]models.Schema, error) {
-	args := m.Called(ctx, subjectPrefix, clusterId)
+func (m *MockClient) ListSchemas(ctx context.Context, subjectPrefix string, clusterId models.ClusterId, offset int, limit int) ([]models.Schema, error) {
+	args := m.Called(ctx, subjectPrefix, clusterId, offset, limit)
 
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -21,6 +21,41 @@ func (m *MockClient) ListSchemas(ctx context.Context, subjectPrefix string, clus
 	return args.Get(0).([]models.Schema), args.Error(1)
 }
 
+func (m *MockClient) ListSubjects(ctx context.Context, clusterId models.ClusterId, subjectPrefix string) ([]string, error) {
+	args := m.Called(ctx, clusterId, subjectPrefix)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockClient) ListSubjectVersions(ctx context.Context, clusterId models.ClusterId, subject string) ([]int, error) {
+	args := m.Called(ctx, clusterId, subject)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *MockClient) GetSchemaVersion(ctx context.Context, clusterId models.ClusterId, subject string, version string) (*models.Schema, error) {
+	args := m.Called(ctx, clusterId, subject, version)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*models.Schema), args.Error(1)
+}
+
+func (m *MockClient) GetCompatibilityLevel(ctx context.Context, clusterId models.ClusterId, subject string) (string, error) {
+	args := m.Called(ctx, clusterId, subject)
+	return args.String(0), args.Error(1)
+}
+
 func (m *MockClient) CreateServiceAccount(ctx context.Context, name string, description string) (models.ServiceAccountId, error) {
 	args := m.Called(ctx, name, description)
 	return args.Get(0).(models.ServiceAccountId), args.Error(1)
@@ -31,6 +66,21 @@ func (m *MockClient) GetServiceAccount(ctx context.Context, displayName string)
 	return args.Get(0).(models.ServiceAccountId), args.Error(1)
 }
 
+func (m *MockClient) DeleteACLEntry(ctx context.Context, clusterId models.ClusterId, userAccountId models.UserAccountId, entry models.AclDefinition) error {
+	args := m.Called(ctx, clusterId, userAccountId, entry)
+	return args.Error(0)
+}
+
+func (m *MockClient) ListACLs(ctx context.Context, clusterId models.ClusterId, filter models.AclFilter) ([]models.AclBinding, error) {
+	args := m.Called(ctx, clusterId, filter)
+	return args.Get(0).([]models.AclBinding), args.Error(1)
+}
+
+func (m *MockClient) DeleteACLs(ctx context.Context, clusterId models.ClusterId, filter models.AclFilter) ([]models.AclBinding, error) {
+	args := m.Called(ctx, clusterId, filter)
+	return args.Get(0).([]models.AclBinding), args.Error(1)
+}
+
 func (m *MockClient) CreateClusterApiKey(ctx context.Context, clusterId models.ClusterId, serviceAccountId models.ServiceAccountId) (models.ApiKey, error) {
 	args := m.Called(ctx, clusterId, serviceAccountId)
 	return args.Get(0).(models.ApiKey), args.Error(1)
@@ -51,8 +101,33 @@ func (m *MockClient) DeleteSchemaRegistryApiKey(ctx context.Context, clusterId m
 	return args.Error(0)
 }
 
-func (m *MockClient) CreateServiceAccountRoleBinding(ctx context.Context, serviceAccount models.ServiceAccountId, clusterId models.ClusterId) error {
-	args := m.Called(ctx, serviceAccount, clusterId)
+func (m *MockClient) CreateRoleBinding(ctx context.Context, serviceAccount models.ServiceAccountId, definition models.RoleBindingDefinition) (string, error) {
+	args := m.Called(ctx, serviceAccount, definition)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockClient) EnsureRoleBinding(ctx context.Context, serviceAccount models.ServiceAccountId, definition models.RoleBindingDefinition) (string, error) {
+	args := m.Called(ctx, serviceAccount, definition)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockClient) ListRoleBindings(ctx context.Context, serviceAccount models.ServiceAccountId, crnPattern string) ([]models.ConfluentRoleBinding, error) {
+	args := m.Called(ctx, serviceAccount, crnPattern)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]models.ConfluentRoleBinding), args.Error(1)
+}
+
+func (m *MockClient) DeleteRoleBinding(ctx context.Context, roleBindingId string) error {
+	args := m.Called(ctx, roleBindingId)
+	return args.Error(0)
+}
+
+func (m *MockClient) RevokeRoleBinding(ctx context.Context, serviceAccount models.ServiceAccountId, definition models.RoleBindingDefinition) error {
+	args := m.Called(ctx, serviceAccount, definition)
 	return args.Error(0)
 }
 
@@ -66,6 +141,16 @@ func (m *MockClient) DeleteTopic(ctx context.Context, clusterId models.ClusterId
 	return args.Error(0)
 }
 
+func (m *MockClient) GetTopic(ctx context.Context, clusterId models.ClusterId, topicName string) (*models.KafkaTopic, error) {
+	args := m.Called(ctx, clusterId, topicName)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*models.KafkaTopic), args.Error(1)
+}
+
 func (m *MockClient) GetConfluentInternalUsers(ctx context.Context) ([]models.ConfluentInternalUser, error) {
 	args := m.Called(ctx)
 	return args.Get(0).([]models.ConfluentInternalUser), args.Error(1)
@@ -81,17 +166,57 @@ func (m *MockClient) CountSchemaRegistryApiKeys(ctx context.Context, serviceAcco
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockClient) RegisterSchema(ctx context.Context, clusterId models.ClusterId, subject string, schema string, version int32) error {
+func (m *MockClient) SetCompatibilityLevel(ctx context.Context, clusterId models.ClusterId, subject string, level models.CompatibilityLevel) error {
+	args := m.Called(ctx, clusterId, subject, level)
+	return args.Error(0)
+}
+
+func (m *MockClient) CheckSchemaCompatibility(ctx context.Context, clusterId models.ClusterId, subject string, schema models.SchemaDefinition) (*models.CompatibilityResult, error) {
+	args := m.Called(ctx, clusterId, subject, schema)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*models.CompatibilityResult), args.Error(1)
+}
+
+func (m *MockClient) RegisterSchema(ctx context.Context, clusterId models.ClusterId, subject string, schema models.SchemaDefinition, version int32) error {
 	args := m.Called(ctx, clusterId, subject, schema, version)
 	return args.Error(0)
 }
 
+func (m *MockClient) LookupSchemaVersion(ctx context.Context, clusterId models.ClusterId, subject string, schema models.SchemaDefinition) (int32, error) {
+	args := m.Called(ctx, clusterId, subject, schema)
+	return args.Get(0).(int32), args.Error(1)
+}
+
 func (m *MockClient) DeleteSchema(ctx context.Context, clusterId models.ClusterId, subject string, schema string, version string) error {
 	args := m.Called(ctx, clusterId, subject, schema, version)
 	return args.Error(0)
 }
 
+func (m *MockClient) DeleteSubject(ctx context.Context, clusterId models.ClusterId, subject string, permanent bool) error {
+	args := m.Called(ctx, clusterId, subject, permanent)
+	return args.Error(0)
+}
+
+func (m *MockClient) DeleteSchemaVersion(ctx context.Context, clusterId models.ClusterId, subject string, version string, permanent bool) error {
+	args := m.Called(ctx, clusterId, subject, version, permanent)
+	return args.Error(0)
+}
+
 func (m *MockClient) CreateACLEntry(ctx context.Context, clusterId models.ClusterId, userAccountId models.UserAccountId, entry models.AclDefinition) error {
 	args := m.Called(ctx, clusterId, userAccountId, entry)
 	return args.Error(0)
 }
+
+func (m *MockClient) CreateACLEntries(ctx context.Context, clusterId models.ClusterId, userAccountId models.UserAccountId, entries []models.AclDefinition) ([]models.AclCreationResult, error) {
+	args := m.Called(ctx, clusterId, userAccountId, entries)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]models.AclCreationResult), args.Error(1)
+}
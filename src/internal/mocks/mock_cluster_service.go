@@ -0,0 +1,62 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockClusterService struct {
+	mock.Mock
+}
+
+func (m *MockClusterService) ListClusters(ctx context.Context) ([]models.ClusterInfo, error) {
+	args := m.Called(ctx)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]models.ClusterInfo), args.Error(1)
+}
+
+func (m *MockClusterService) GetCluster(ctx context.Context, clusterId models.ClusterId) (*models.ClusterInfo, error) {
+	args := m.Called(ctx, clusterId)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*models.ClusterInfo), args.Error(1)
+}
+
+func (m *MockClusterService) CreateCluster(ctx context.Context, request models.ClusterRequest) (*models.ClusterInfo, error) {
+	args := m.Called(ctx, request)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*models.ClusterInfo), args.Error(1)
+}
+
+func (m *MockClusterService) UpdateCluster(ctx context.Context, clusterId models.ClusterId, request models.ClusterRequest) (*models.ClusterInfo, error) {
+	args := m.Called(ctx, clusterId, request)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*models.ClusterInfo), args.Error(1)
+}
+
+func (m *MockClusterService) DeleteCluster(ctx context.Context, clusterId models.ClusterId) error {
+	args := m.Called(ctx, clusterId)
+	return args.Error(0)
+}
+
+func (m *MockClusterService) RefreshClusters(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
@@ -0,0 +1,58 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockGrantService struct {
+	mock.Mock
+}
+
+func (m *MockGrantService) RequestGrant(ctx context.Context, request models.AccessGrantRequest) (*models.AccessGrant, error) {
+	args := m.Called(ctx, request)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*models.AccessGrant), args.Error(1)
+}
+
+func (m *MockGrantService) GetGrant(ctx context.Context, grantId string) (*models.AccessGrant, error) {
+	args := m.Called(ctx, grantId)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*models.AccessGrant), args.Error(1)
+}
+
+func (m *MockGrantService) ApproveGrant(ctx context.Context, grantId string) (*models.AccessGrant, error) {
+	args := m.Called(ctx, grantId)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*models.AccessGrant), args.Error(1)
+}
+
+func (m *MockGrantService) RevokeGrant(ctx context.Context, grantId string) error {
+	args := m.Called(ctx, grantId)
+
+	return args.Error(0)
+}
+
+func (m *MockGrantService) ListGrants(ctx context.Context, capabilityId models.CapabilityId) ([]models.AccessGrant, error) {
+	args := m.Called(ctx, capabilityId)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]models.AccessGrant), args.Error(1)
+}
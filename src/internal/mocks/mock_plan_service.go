@@ -0,0 +1,27 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPlanService struct {
+	mock.Mock
+}
+
+func (m *MockPlanService) EventTypes() []string {
+	args := m.Called()
+	return args.Get(0).([]string)
+}
+
+func (m *MockPlanService) Plan(ctx context.Context, eventType string, data []byte) (*models.Plan, error) {
+	args := m.Called(ctx, eventType, data)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*models.Plan), args.Error(1)
+}
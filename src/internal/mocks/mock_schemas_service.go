@@ -11,8 +11,8 @@ type MockSchemaService struct {
 	mock.Mock
 }
 
-func (m *MockSchemaService) ListSchemas(ctx context.Context, subjectPrefix string, clusterId models.ClusterId) ([]models.Schema, error) {
-	args := m.Called(ctx, subjectPrefix, clusterId)
+func (m *MockSchemaService) ListSchemas(ctx context.Context, subjectPrefix string, clusterId models.ClusterId, offset int, limit int) ([]models.Schema, error) {
+	args := m.Called(ctx, subjectPrefix, clusterId, offset, limit)
 
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -20,3 +20,93 @@ func (m *MockSchemaService) ListSchemas(ctx context.Context, subjectPrefix strin
 
 	return args.Get(0).([]models.Schema), args.Error(1)
 }
+
+func (m *MockSchemaService) ListSubjects(ctx context.Context, clusterId models.ClusterId, subjectPrefix string) ([]string, error) {
+	args := m.Called(ctx, clusterId, subjectPrefix)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockSchemaService) ListSubjectVersions(ctx context.Context, clusterId models.ClusterId, subject string) ([]int, error) {
+	args := m.Called(ctx, clusterId, subject)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *MockSchemaService) GetSchemaVersion(ctx context.Context, clusterId models.ClusterId, subject string, version string) (*models.Schema, error) {
+	args := m.Called(ctx, clusterId, subject, version)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*models.Schema), args.Error(1)
+}
+
+func (m *MockSchemaService) GetCompatibilityLevel(ctx context.Context, clusterId models.ClusterId, subject string) (*models.SubjectCompatibility, error) {
+	args := m.Called(ctx, clusterId, subject)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*models.SubjectCompatibility), args.Error(1)
+}
+
+func (m *MockSchemaService) CheckCompatibility(ctx context.Context, request models.SchemaCompatibilityRequest) (*models.CompatibilityResult, error) {
+	args := m.Called(ctx, request)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*models.CompatibilityResult), args.Error(1)
+}
+
+func (m *MockSchemaService) SetCompatibilityLevel(ctx context.Context, clusterId models.ClusterId, subject string, level models.CompatibilityLevel) (*models.SubjectCompatibility, error) {
+	args := m.Called(ctx, clusterId, subject, level)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*models.SubjectCompatibility), args.Error(1)
+}
+
+func (m *MockSchemaService) GetCapabilitySchemaSettings(ctx context.Context, capabilityId models.CapabilityId) (*models.CapabilitySchemaSettings, error) {
+	args := m.Called(ctx, capabilityId)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*models.CapabilitySchemaSettings), args.Error(1)
+}
+
+func (m *MockSchemaService) SaveCapabilitySchemaSettings(ctx context.Context, capabilityId models.CapabilityId, level models.CompatibilityLevel) (*models.CapabilitySchemaSettings, error) {
+	args := m.Called(ctx, capabilityId, level)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*models.CapabilitySchemaSettings), args.Error(1)
+}
+
+func (m *MockSchemaService) GetSubjectOwner(ctx context.Context, clusterId models.ClusterId, subject string) (models.CapabilityId, error) {
+	args := m.Called(ctx, clusterId, subject)
+	return args.Get(0).(models.CapabilityId), args.Error(1)
+}
+
+func (m *MockSchemaService) GetTopicOwner(ctx context.Context, topicId string) (models.CapabilityId, error) {
+	args := m.Called(ctx, topicId)
+	return args.Get(0).(models.CapabilityId), args.Error(1)
+}
@@ -0,0 +1,32 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTopicService struct {
+	mock.Mock
+}
+
+func (m *MockTopicService) ListTopics(ctx context.Context, capabilityId models.CapabilityId, live bool) ([]models.TopicInfo, error) {
+	args := m.Called(ctx, capabilityId, live)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]models.TopicInfo), args.Error(1)
+}
+
+func (m *MockTopicService) GetTopic(ctx context.Context, clusterId models.ClusterId, name string, live bool) (*models.TopicInfo, error) {
+	args := m.Called(ctx, clusterId, name, live)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*models.TopicInfo), args.Error(1)
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"time"
+)
+
+type AclEntryStatus string
+
+const (
+	AclEntryStatusCreated AclEntryStatus = "created"
+	AclEntryStatusPending AclEntryStatus = "pending"
+
+	AclEntryStatusAwaitingApproval AclEntryStatus = "awaiting-approval"
+)
+
+// AccessInfo is the read model of a capability's service account and its cluster accesses.
+type AccessInfo struct {
+	ServiceAccountId ServiceAccountId    `json:"serviceAccountId"`
+	UserAccountId    UserAccountId       `json:"userAccountId"`
+	CapabilityId     CapabilityId        `json:"capabilityId"`
+	ApplicationName  string              `json:"applicationName,omitempty"`
+	CreatedAt        time.Time           `json:"createdAt"`
+	ClusterAccesses  []ClusterAccessInfo `json:"clusterAccesses"`
+}
+
+type ClusterAccessInfo struct {
+	Id        string         `json:"id"`
+	ClusterId ClusterId      `json:"clusterId"`
+	CreatedAt time.Time      `json:"createdAt"`
+	ApiKeys   ApiKeyStatus   `json:"apiKeys"`
+	Acl       []AclEntryInfo `json:"acl"`
+	// RoleBindings are the access of the service account on a cluster in RBAC mode.
+	RoleBindings []RoleBindingInfo `json:"roleBindings,omitempty"`
+}
+
+// ApiKeyStatus tells whether the cluster and schema registry api keys exist in Confluent and in the vault.
+type ApiKeyStatus struct {
+	ClusterApiKeyInConfluent        bool `json:"clusterApiKeyInConfluent"`
+	ClusterApiKeyInVault            bool `json:"clusterApiKeyInVault"`
+	SchemaRegistryApiKeyInConfluent bool `json:"schemaRegistryApiKeyInConfluent"`
+	SchemaRegistryApiKeyInVault     bool `json:"schemaRegistryApiKeyInVault"`
+}
+
+type AclEntryInfo struct {
+	Id             string         `json:"id"`
+	ResourceType   ResourceType   `json:"resourceType"`
+	ResourceName   string         `json:"resourceName"`
+	PatternType    PatternType    `json:"patternType"`
+	OperationType  OperationType  `json:"operationType"`
+	PermissionType PermissionType `json:"permissionType"`
+	Status         AclEntryStatus `json:"status"`
+	CreatedAt      *time.Time     `json:"createdAt"`
+	GrantId        *string        `json:"grantId,omitempty"`
+}
+
+func NewAccessInfo(serviceAccount *ServiceAccount) AccessInfo {
+	return AccessInfo{
+		ServiceAccountId: serviceAccount.Id,
+		UserAccountId:    serviceAccount.UserAccountId,
+		CapabilityId:     serviceAccount.CapabilityId,
+		ApplicationName:  serviceAccount.ApplicationName,
+		CreatedAt:        serviceAccount.CreatedAt,
+		ClusterAccesses:  []ClusterAccessInfo{},
+	}
+}
+
+func NewClusterAccessInfo(clusterAccess *ClusterAccess, apiKeys ApiKeyStatus) ClusterAccessInfo {
+	pending := map[string]bool{}
+	for _, entry := range clusterAccess.GetAclPendingCreation() {
+		pending[entry.Id.String()] = true
+	}
+
+	acl := make([]AclEntryInfo, len(clusterAccess.Acl))
+
+	for i, entry := range clusterAccess.Acl {
+		status := AclEntryStatusCreated
+		if pending[entry.Id.String()] {
+			status = AclEntryStatusPending
+		} else if entry.IsAwaitingApproval() {
+			status = AclEntryStatusAwaitingApproval
+		}
+
+		var grantId *string
+		if entry.IsGranted() {
+			id := entry.GrantId.String()
+			grantId = &id
+		}
+
+		acl[i] = AclEntryInfo{
+			Id:             entry.Id.String(),
+			ResourceType:   entry.ResourceType,
+			ResourceName:   entry.ResourceName,
+			PatternType:    entry.PatternType,
+			OperationType:  entry.OperationType,
+			PermissionType: entry.PermissionType,
+			Status:         status,
+			CreatedAt:      entry.CreatedAt,
+			GrantId:        grantId,
+		}
+	}
+
+	var roleBindings []RoleBindingInfo
+	for i := range clusterAccess.RoleBindings {
+		if clusterAccess.RoleBindings[i].IsDeleted() {
+			continue
+		}
+		roleBindings = append(roleBindings, NewRoleBindingInfo(&clusterAccess.RoleBindings[i]))
+	}
+
+	return ClusterAccessInfo{
+		Id:           clusterAccess.Id.String(),
+		ClusterId:    clusterAccess.ClusterId,
+		CreatedAt:    clusterAccess.CreatedAt,
+		ApiKeys:      apiKeys,
+		Acl:          acl,
+		RoleBindings: roleBindings,
+	}
+}
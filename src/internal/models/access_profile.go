@@ -0,0 +1,69 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var ErrUnknownAccessProfile = errors.New("unknown access profile")
+
+// AccessProfile adds the ACL entries a kind of client needs on top of the ACL template of the cluster.
+type AccessProfile string
+
+const (
+	// AccessProfileConnect lets a Kafka Connect cluster named connect-<capabilityId> keep its config, offset and
+	// status topics and run exactly-once source connectors.
+	AccessProfileConnect AccessProfile = "connect"
+)
+
+var AccessProfiles = []AccessProfile{AccessProfileConnect}
+
+// ParseAccessProfiles turns the names of profiles into profiles, leaving out duplicates.
+func ParseAccessProfiles(names []string) ([]AccessProfile, error) {
+	var profiles []AccessProfile
+
+	for _, name := range names {
+		profile := AccessProfile(name)
+		if !slices.Contains(AccessProfiles, profile) {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownAccessProfile, name)
+		}
+		if !slices.Contains(profiles, profile) {
+			profiles = append(profiles, profile)
+		}
+	}
+
+	return profiles, nil
+}
+
+// Render returns the ACL the profile adds for a capability.
+func (p AccessProfile) Render(capabilityId CapabilityId) []AclDefinition {
+	switch p {
+	case AccessProfileConnect:
+		return connectAclDefinitions(capabilityId)
+	default:
+		return nil
+	}
+}
+
+func connectAclDefinitions(capabilityId CapabilityId) []AclDefinition {
+	connect := "connect-" + string(capabilityId)
+
+	var definitions []AclDefinition
+
+	// for the config, offset and status topics of the connect cluster
+	for _, topic := range []string{connect + "-configs", connect + "-offsets", connect + "-status"} {
+		for _, operation := range []OperationType{OperationTypeRead, OperationTypeWrite, OperationTypeCreate, OperationTypeDescribe, OperationTypeDescribeConfigs} {
+			definitions = append(definitions, defineAcl(ResourceTypeTopic, topic, PatternTypeLiteral, operation, PermissionTypeAllow))
+		}
+	}
+
+	// for the transactions of exactly-once source connectors
+	definitions = append(definitions,
+		defineAcl(ResourceTypeTransactionalId, connect, PatternTypePrefix, OperationTypeWrite, PermissionTypeAllow),
+		defineAcl(ResourceTypeTransactionalId, connect, PatternTypePrefix, OperationTypeDescribe, PermissionTypeAllow),
+		defineAcl(ResourceTypeCluster, "kafka-cluster", PatternTypeLiteral, OperationTypeIdempotentWrite, PermissionTypeAllow),
+	)
+
+	return definitions
+}
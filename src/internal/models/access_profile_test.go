@@ -0,0 +1,48 @@
+package models
+
+import (
+	"testing"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAccessProfiles(t *testing.T) {
+	profiles, err := ParseAccessProfiles([]string{"connect", "connect"})
+	assert.NoError(t, err)
+	assert.Equal(t, []AccessProfile{AccessProfileConnect}, profiles)
+
+	_, err = ParseAccessProfiles([]string{"ksql"})
+	assert.ErrorIs(t, err, ErrUnknownAccessProfile)
+}
+
+func TestAccessProfileConnect_Render(t *testing.T) {
+	definitions := AccessProfileConnect.Render("some-cap")
+
+	assert.Len(t, definitions, 18)
+	assert.Contains(t, definitions, defineAcl(ResourceTypeTopic, "connect-some-cap-offsets", PatternTypeLiteral, OperationTypeWrite, PermissionTypeAllow))
+	assert.Contains(t, definitions, defineAcl(ResourceTypeTransactionalId, "connect-some-cap", PatternTypePrefix, OperationTypeWrite, PermissionTypeAllow))
+	assert.Contains(t, definitions, defineAcl(ResourceTypeCluster, "kafka-cluster", PatternTypeLiteral, OperationTypeIdempotentWrite, PermissionTypeAllow))
+}
+
+func TestClusterAccess_EnableProfiles(t *testing.T) {
+	sut := NewClusterAccess("sa-1234", "User:1234", "abc-1234", "some-cap", DefaultAclTemplate("abc-1234"))
+	assert.False(t, sut.HasProfiles([]AccessProfile{AccessProfileConnect}))
+
+	added := sut.EnableProfiles([]AccessProfile{AccessProfileConnect}, "some-cap")
+
+	assert.Len(t, added, 18)
+	assert.True(t, sut.HasProfiles([]AccessProfile{AccessProfileConnect}))
+	assert.Len(t, sut.GetAclPendingCreation(), 17+18)
+	assert.Empty(t, sut.EnableProfiles([]AccessProfile{AccessProfileConnect}, "some-cap"))
+}
+
+func TestClusterAccess_ApplyAclTemplate_KeepsProfiles(t *testing.T) {
+	sut := &ClusterAccess{Id: uuid.NewV4()}
+	sut.EnableProfiles([]AccessProfile{AccessProfileConnect}, "some-cap")
+
+	removed := sut.ApplyAclTemplate(DefaultAclTemplate("abc-1234"), "some-cap")
+
+	assert.Empty(t, removed)
+	assert.Len(t, sut.Acl, 17+18)
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewClusterAccessInfo_ReportsAclStatus(t *testing.T) {
+	now := time.Now()
+	created := AclEntry{Id: uuid.NewV4(), CreatedAt: &now}
+	pending := AclEntry{Id: uuid.NewV4()}
+
+	info := NewClusterAccessInfo(&ClusterAccess{Id: uuid.NewV4(), Acl: []AclEntry{created, pending}}, ApiKeyStatus{ClusterApiKeyInVault: true})
+
+	assert.Equal(t, []AclEntryStatus{AclEntryStatusCreated, AclEntryStatusPending}, []AclEntryStatus{info.Acl[0].Status, info.Acl[1].Status})
+	assert.Equal(t, &now, info.Acl[0].CreatedAt)
+	assert.True(t, info.ApiKeys.ClusterApiKeyInVault)
+}
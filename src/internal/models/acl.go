@@ -1,7 +1,6 @@
 package models
 
 import (
-	"fmt"
 	"strings"
 )
 
@@ -23,6 +22,41 @@ func (ad AclDefinition) String() string {
 	}, " | ")
 }
 
+// AclFilter selects the ACLs on a cluster. Fields left empty match any value.
+type AclFilter struct {
+	Principal      UserAccountId
+	ResourceType   ResourceType
+	ResourceName   string
+	PatternType    PatternType
+	OperationType  OperationType
+	PermissionType PermissionType
+}
+
+// NewAclFilter returns the filter that selects exactly the ACL of the principal with the definition.
+func NewAclFilter(principal UserAccountId, definition AclDefinition) AclFilter {
+	return AclFilter{
+		Principal:      principal,
+		ResourceType:   definition.ResourceType,
+		ResourceName:   definition.ResourceName,
+		PatternType:    definition.PatternType,
+		OperationType:  definition.OperationType,
+		PermissionType: definition.PermissionType,
+	}
+}
+
+// AclBinding is an ACL as it exists on a cluster.
+type AclBinding struct {
+	Principal UserAccountId
+	Host      string
+	AclDefinition
+}
+
+// AclCreationResult is the outcome of creating one ACL of a batch. Err is nil when the ACL was created.
+type AclCreationResult struct {
+	AclDefinition
+	Err error
+}
+
 type OperationType string
 
 const (
@@ -34,8 +68,11 @@ const (
 	OperationTypeAlter           OperationType = "ALTER"
 	OperationTypeAlterConfigs    OperationType = "ALTER_CONFIGS"
 	OperationTypeClusterAction   OperationType = "CLUSTER_ACTION"
+	OperationTypeIdempotentWrite OperationType = "IDEMPOTENT_WRITE"
 )
 
+var OperationTypes = []OperationType{OperationTypeCreate, OperationTypeRead, OperationTypeWrite, OperationTypeDescribe, OperationTypeDescribeConfigs, OperationTypeAlter, OperationTypeAlterConfigs, OperationTypeClusterAction, OperationTypeIdempotentWrite}
+
 type PatternType string
 
 const (
@@ -43,14 +80,20 @@ const (
 	PatternTypePrefix  PatternType = "PREFIXED"
 )
 
+var PatternTypes = []PatternType{PatternTypeLiteral, PatternTypePrefix}
+
 type ResourceType string
 
 const (
 	ResourceTypeTopic   ResourceType = "TOPIC"
 	ResourceTypeGroup   ResourceType = "GROUP"
 	ResourceTypeCluster ResourceType = "CLUSTER"
+
+	ResourceTypeTransactionalId ResourceType = "TRANSACTIONAL_ID"
 )
 
+var ResourceTypes = []ResourceType{ResourceTypeTopic, ResourceTypeGroup, ResourceTypeCluster, ResourceTypeTransactionalId}
+
 type PermissionType string
 
 const (
@@ -58,48 +101,11 @@ const (
 	PermissionTypeAllow PermissionType = "ALLOW"
 )
 
+var PermissionTypes = []PermissionType{PermissionTypeDeny, PermissionTypeAllow}
+
+// CreateAclDefinitions returns the ACL of a capability according to the default template.
 func CreateAclDefinitions(capabilityId CapabilityId) []AclDefinition {
-	const publicTopicPrefix = "pub."
-	const allTopics = "*"
-	const clusterResourceName = "kafka-cluster"
-
-	capabilityPrefix := string(capabilityId)
-	publicCapabilityPrefix := fmt.Sprintf("%s%s", publicTopicPrefix, capabilityPrefix)
-	connectPrefix := fmt.Sprintf("connect-%s", capabilityPrefix)
-
-	return []AclDefinition{
-		// deny create operations on all resource types
-		//defineAcl(ResourceTypeTopic, allTopics, PatternTypeLiteral, OperationTypeCreate, PermissionTypeDeny),
-
-		// for all private topics
-		defineAcl(ResourceTypeTopic, capabilityPrefix, PatternTypePrefix, OperationTypeRead, PermissionTypeAllow),
-		defineAcl(ResourceTypeTopic, capabilityPrefix, PatternTypePrefix, OperationTypeWrite, PermissionTypeAllow),
-		defineAcl(ResourceTypeTopic, capabilityPrefix, PatternTypePrefix, OperationTypeCreate, PermissionTypeAllow),
-		defineAcl(ResourceTypeTopic, capabilityPrefix, PatternTypePrefix, OperationTypeDescribe, PermissionTypeAllow),
-		defineAcl(ResourceTypeTopic, capabilityPrefix, PatternTypePrefix, OperationTypeDescribeConfigs, PermissionTypeAllow),
-
-		// for all public topics
-		defineAcl(ResourceTypeTopic, publicTopicPrefix, PatternTypePrefix, OperationTypeRead, PermissionTypeAllow),
-
-		// for own public topics
-		defineAcl(ResourceTypeTopic, publicCapabilityPrefix, PatternTypePrefix, OperationTypeWrite, PermissionTypeAllow),
-		defineAcl(ResourceTypeTopic, publicCapabilityPrefix, PatternTypePrefix, OperationTypeCreate, PermissionTypeAllow),
-
-		// for all connect groups
-		defineAcl(ResourceTypeGroup, connectPrefix, PatternTypePrefix, OperationTypeRead, PermissionTypeAllow),
-		defineAcl(ResourceTypeGroup, connectPrefix, PatternTypePrefix, OperationTypeWrite, PermissionTypeAllow),
-		defineAcl(ResourceTypeGroup, connectPrefix, PatternTypePrefix, OperationTypeCreate, PermissionTypeAllow),
-
-		// for all capability groups
-		defineAcl(ResourceTypeGroup, capabilityPrefix, PatternTypePrefix, OperationTypeRead, PermissionTypeAllow),
-		defineAcl(ResourceTypeGroup, capabilityPrefix, PatternTypePrefix, OperationTypeWrite, PermissionTypeAllow),
-		defineAcl(ResourceTypeGroup, capabilityPrefix, PatternTypePrefix, OperationTypeCreate, PermissionTypeAllow),
-
-		// for cluster
-		defineAcl(ResourceTypeCluster, clusterResourceName, PatternTypeLiteral, OperationTypeAlter, PermissionTypeDeny),
-		defineAcl(ResourceTypeCluster, clusterResourceName, PatternTypeLiteral, OperationTypeAlterConfigs, PermissionTypeDeny),
-		defineAcl(ResourceTypeCluster, clusterResourceName, PatternTypeLiteral, OperationTypeClusterAction, PermissionTypeDeny),
-	}
+	return DefaultAclTemplate("").Render(capabilityId)
 }
 
 func defineAcl(resourceType ResourceType, resourceName string, patternType PatternType, operationType OperationType, permissionType PermissionType) AclDefinition {
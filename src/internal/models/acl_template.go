@@ -0,0 +1,146 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// CapabilityIdPlaceholder is replaced by the capability id in the resource names of an ACL template.
+const CapabilityIdPlaceholder = "{capabilityId}"
+
+var ErrInvalidAclTemplate = errors.New("invalid acl template")
+
+// AclTemplate is the ACL every capability gets on a cluster. A cluster without templates uses the default template,
+// which is version 0.
+type AclTemplate struct {
+	ClusterId ClusterId          `gorm:"primarykey" json:"clusterId"`
+	Version   int                `gorm:"primarykey" json:"version"`
+	Entries   []AclTemplateEntry `gorm:"serializer:json" json:"entries"`
+	CreatedAt time.Time          `json:"createdAt"`
+}
+
+func (*AclTemplate) TableName() string {
+	return "acl_template"
+}
+
+type AclTemplateEntry struct {
+	ResourceType   ResourceType   `json:"resourceType"`
+	ResourceName   string         `json:"resourceName"`
+	PatternType    PatternType    `json:"patternType"`
+	OperationType  OperationType  `json:"operationType"`
+	PermissionType PermissionType `json:"permissionType"`
+}
+
+func NewAclTemplate(clusterId ClusterId, version int, entries []AclTemplateEntry) (*AclTemplate, error) {
+	var problems []string
+	if len(entries) == 0 {
+		problems = append(problems, "entries are required")
+	}
+	for i, entry := range entries {
+		problems = append(problems, entry.validate(i)...)
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidAclTemplate, strings.Join(problems, ", "))
+	}
+
+	return &AclTemplate{
+		ClusterId: clusterId,
+		Version:   version,
+		Entries:   entries,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+func (e AclTemplateEntry) validate(i int) []string {
+	var problems []string
+
+	if !slices.Contains(ResourceTypes, e.ResourceType) {
+		problems = append(problems, fmt.Sprintf("entries[%d].resourceType %q is unknown", i, e.ResourceType))
+	}
+	if e.ResourceName == "" {
+		problems = append(problems, fmt.Sprintf("entries[%d].resourceName is required", i))
+	}
+	if !slices.Contains(PatternTypes, e.PatternType) {
+		problems = append(problems, fmt.Sprintf("entries[%d].patternType %q is unknown", i, e.PatternType))
+	}
+	if !slices.Contains(OperationTypes, e.OperationType) {
+		problems = append(problems, fmt.Sprintf("entries[%d].operationType %q is unknown", i, e.OperationType))
+	}
+	if !slices.Contains(PermissionTypes, e.PermissionType) {
+		problems = append(problems, fmt.Sprintf("entries[%d].permissionType %q is unknown", i, e.PermissionType))
+	}
+
+	return problems
+}
+
+// Render returns the ACL of a capability according to the template.
+func (t *AclTemplate) Render(capabilityId CapabilityId) []AclDefinition {
+	definitions := make([]AclDefinition, len(t.Entries))
+
+	for i, entry := range t.Entries {
+		definitions[i] = defineAcl(
+			entry.ResourceType,
+			strings.ReplaceAll(entry.ResourceName, CapabilityIdPlaceholder, string(capabilityId)),
+			entry.PatternType,
+			entry.OperationType,
+			entry.PermissionType,
+		)
+	}
+
+	return definitions
+}
+
+// DefaultAclTemplate returns the template used for clusters without templates of their own.
+func DefaultAclTemplate(clusterId ClusterId) *AclTemplate {
+	const capability = CapabilityIdPlaceholder
+	const publicTopics = "pub."
+	const publicCapability = "pub." + CapabilityIdPlaceholder
+	const connect = "connect-" + CapabilityIdPlaceholder
+	const cluster = "kafka-cluster"
+
+	return &AclTemplate{
+		ClusterId: clusterId,
+		Version:   0,
+		Entries: []AclTemplateEntry{
+			// for all private topics
+			{ResourceTypeTopic, capability, PatternTypePrefix, OperationTypeRead, PermissionTypeAllow},
+			{ResourceTypeTopic, capability, PatternTypePrefix, OperationTypeWrite, PermissionTypeAllow},
+			{ResourceTypeTopic, capability, PatternTypePrefix, OperationTypeCreate, PermissionTypeAllow},
+			{ResourceTypeTopic, capability, PatternTypePrefix, OperationTypeDescribe, PermissionTypeAllow},
+			{ResourceTypeTopic, capability, PatternTypePrefix, OperationTypeDescribeConfigs, PermissionTypeAllow},
+
+			// for all public topics
+			{ResourceTypeTopic, publicTopics, PatternTypePrefix, OperationTypeRead, PermissionTypeAllow},
+
+			// for own public topics
+			{ResourceTypeTopic, publicCapability, PatternTypePrefix, OperationTypeWrite, PermissionTypeAllow},
+			{ResourceTypeTopic, publicCapability, PatternTypePrefix, OperationTypeCreate, PermissionTypeAllow},
+
+			// for all connect groups
+			{ResourceTypeGroup, connect, PatternTypePrefix, OperationTypeRead, PermissionTypeAllow},
+			{ResourceTypeGroup, connect, PatternTypePrefix, OperationTypeWrite, PermissionTypeAllow},
+			{ResourceTypeGroup, connect, PatternTypePrefix, OperationTypeCreate, PermissionTypeAllow},
+
+			// for all capability groups
+			{ResourceTypeGroup, capability, PatternTypePrefix, OperationTypeRead, PermissionTypeAllow},
+			{ResourceTypeGroup, capability, PatternTypePrefix, OperationTypeWrite, PermissionTypeAllow},
+			{ResourceTypeGroup, capability, PatternTypePrefix, OperationTypeCreate, PermissionTypeAllow},
+
+			// for cluster
+			{ResourceTypeCluster, cluster, PatternTypeLiteral, OperationTypeAlter, PermissionTypeDeny},
+			{ResourceTypeCluster, cluster, PatternTypeLiteral, OperationTypeAlterConfigs, PermissionTypeDeny},
+			{ResourceTypeCluster, cluster, PatternTypeLiteral, OperationTypeClusterAction, PermissionTypeDeny},
+		},
+	}
+}
+
+// AclTemplateReapplyResult tells which cluster accesses were brought up to the current version of a template.
+type AclTemplateReapplyResult struct {
+	ClusterId ClusterId               `json:"clusterId"`
+	Version   int                     `json:"version"`
+	Updated   []CapabilityId          `json:"updated"`
+	Failed    map[CapabilityId]string `json:"failed"`
+}
@@ -53,3 +53,12 @@ func mapToString(definitions []AclDefinition) []string {
 	}
 	return result
 }
+
+func TestNewAclTemplate_Invalid(t *testing.T) {
+	_, err := NewAclTemplate("some-cluster", 1, []AclTemplateEntry{
+		{ResourceType: ResourceTypeTopic, ResourceName: "pub.", PatternType: "SOMETIMES", OperationType: OperationTypeRead, PermissionType: PermissionTypeAllow},
+	})
+
+	assert.ErrorIs(t, err, ErrInvalidAclTemplate)
+	assert.EqualError(t, err, `invalid acl template: entries[0].patternType "SOMETIMES" is unknown`)
+}
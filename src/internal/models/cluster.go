@@ -1,5 +1,12 @@
 package models
 
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
 type ClusterId string
 type SchemaRegistryId string
 
@@ -14,13 +21,155 @@ type Cluster struct {
 	OrganizationId            string
 	EnvironmentId             string
 	SchemaRegistryId          SchemaRegistryId
+	SubjectNameStrategy       SubjectNameStrategy
+	AccessMode                AccessMode
 }
 
 func (*Cluster) TableName() string {
 	return "cluster"
 }
 
+// UsesRbac tells whether service accounts get access to the cluster through role bindings rather than ACLs.
+func (c *Cluster) UsesRbac() bool {
+	return c.AccessMode == AccessModeRbac
+}
+
+// KafkaCrn is the Confluent resource name of the cluster, which role bindings on its topics and groups extend.
+func (c *Cluster) KafkaCrn() string {
+	return fmt.Sprintf("crn://confluent.cloud/organization=%s/environment=%s/cloud-cluster=%s/kafka=%s", c.OrganizationId, c.EnvironmentId, c.ClusterId, c.ClusterId)
+}
+
+// HasSchemaRegistryIds tells whether the cluster knows enough of its schema registry to bind roles on its subjects.
+func (c *Cluster) HasSchemaRegistryIds() bool {
+	return c.OrganizationId != "" && c.EnvironmentId != "" && c.SchemaRegistryId != ""
+}
+
+// SchemaRegistryCrn is the Confluent resource name of the schema registry of the cluster.
+func (c *Cluster) SchemaRegistryCrn() string {
+	return fmt.Sprintf("crn://confluent.cloud/organization=%s/environment=%s/schema-registry=%s", c.OrganizationId, c.EnvironmentId, c.SchemaRegistryId)
+}
+
 type ApiKey struct {
 	Username string
 	Password string
 }
+
+// HasStoredApiKeys tells whether the admin api keys are kept in the cluster table. Clusters added through the API
+// keep them in the vault instead.
+func (c *Cluster) HasStoredApiKeys() bool {
+	return c.AdminApiKey.Password != ""
+}
+
+var ErrInvalidCluster = errors.New("invalid cluster")
+
+// ClusterRequest is the body of adding or updating a cluster. The api keys left out of an update are kept.
+type ClusterRequest struct {
+	ClusterId                 ClusterId           `json:"id"`
+	Name                      string              `json:"name"`
+	AdminApiEndpoint          string              `json:"adminApiEndpoint"`
+	AdminApiKey               *ApiKeyRequest      `json:"adminApiKey"`
+	BootstrapEndpoint         string              `json:"bootstrapEndpoint"`
+	SchemaRegistryApiEndpoint string              `json:"schemaRegistryApiEndpoint"`
+	SchemaRegistryApiKey      *ApiKeyRequest      `json:"schemaRegistryApiKey"`
+	OrganizationId            string              `json:"organizationId"`
+	EnvironmentId             string              `json:"environmentId"`
+	SchemaRegistryId          SchemaRegistryId    `json:"schemaRegistryId"`
+	SubjectNameStrategy       SubjectNameStrategy `json:"subjectNameStrategy"`
+	AccessMode                AccessMode          `json:"accessMode"`
+}
+
+type ApiKeyRequest struct {
+	Key    string `json:"key"`
+	Secret string `json:"secret"`
+}
+
+func (r *ApiKeyRequest) ApiKey() ApiKey {
+	return ApiKey{Username: r.Key, Password: r.Secret}
+}
+
+// Validate checks the request, where adding a cluster also requires its id and admin api key.
+func (r *ClusterRequest) Validate(isNew bool) error {
+	var problems []string
+
+	if isNew && r.ClusterId == "" {
+		problems = append(problems, "id is required")
+	}
+	if r.Name == "" {
+		problems = append(problems, "name is required")
+	}
+	if r.AdminApiEndpoint == "" {
+		problems = append(problems, "adminApiEndpoint is required")
+	}
+	if r.BootstrapEndpoint == "" {
+		problems = append(problems, "bootstrapEndpoint is required")
+	}
+	if isNew && r.AdminApiKey == nil {
+		problems = append(problems, "adminApiKey is required")
+	}
+	for name, apiKey := range map[string]*ApiKeyRequest{"adminApiKey": r.AdminApiKey, "schemaRegistryApiKey": r.SchemaRegistryApiKey} {
+		if apiKey != nil && (apiKey.Key == "" || apiKey.Secret == "") {
+			problems = append(problems, fmt.Sprintf("%s requires both a key and a secret", name))
+		}
+	}
+	if r.SubjectNameStrategy != "" && !r.SubjectNameStrategy.IsValid() {
+		problems = append(problems, fmt.Sprintf("unknown subjectNameStrategy %q", r.SubjectNameStrategy))
+	}
+	if r.AccessMode != "" && !r.AccessMode.IsValid() {
+		problems = append(problems, fmt.Sprintf("unknown accessMode %q", r.AccessMode))
+	}
+	if r.AccessMode == AccessModeRbac && (r.OrganizationId == "" || r.EnvironmentId == "") {
+		problems = append(problems, "accessMode rbac requires organizationId and environmentId")
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("%w: %s", ErrInvalidCluster, strings.Join(problems, ", "))
+	}
+
+	return nil
+}
+
+// Apply copies the settings of the request onto the cluster, leaving out the api keys.
+func (r *ClusterRequest) Apply(cluster *Cluster) {
+	cluster.Name = r.Name
+	cluster.AdminApiEndpoint = r.AdminApiEndpoint
+	cluster.BootstrapEndpoint = r.BootstrapEndpoint
+	cluster.SchemaRegistryApiEndpoint = r.SchemaRegistryApiEndpoint
+	cluster.OrganizationId = r.OrganizationId
+	cluster.EnvironmentId = r.EnvironmentId
+	cluster.SchemaRegistryId = r.SchemaRegistryId
+	cluster.SubjectNameStrategy = r.SubjectNameStrategy
+	cluster.AccessMode = r.AccessMode
+	if cluster.AccessMode == "" {
+		cluster.AccessMode = AccessModeAcl
+	}
+}
+
+// ClusterInfo is the read model of a cluster, which leaves out its api keys.
+type ClusterInfo struct {
+	ClusterId                 ClusterId           `json:"id"`
+	Name                      string              `json:"name"`
+	AdminApiEndpoint          string              `json:"adminApiEndpoint"`
+	BootstrapEndpoint         string              `json:"bootstrapEndpoint"`
+	SchemaRegistryApiEndpoint string              `json:"schemaRegistryApiEndpoint"`
+	OrganizationId            string              `json:"organizationId"`
+	EnvironmentId             string              `json:"environmentId"`
+	SchemaRegistryId          SchemaRegistryId    `json:"schemaRegistryId"`
+	SubjectNameStrategy       SubjectNameStrategy `json:"subjectNameStrategy"`
+	AccessMode                AccessMode          `json:"accessMode"`
+}
+
+func NewClusterInfo(cluster *Cluster) ClusterInfo {
+	return ClusterInfo{
+		ClusterId:                 cluster.ClusterId,
+		Name:                      cluster.Name,
+		AdminApiEndpoint:          cluster.AdminApiEndpoint,
+		BootstrapEndpoint:         cluster.BootstrapEndpoint,
+		SchemaRegistryApiEndpoint: cluster.SchemaRegistryApiEndpoint,
+		OrganizationId:            cluster.OrganizationId,
+		EnvironmentId:             cluster.EnvironmentId,
+		SchemaRegistryId:          cluster.SchemaRegistryId,
+		SubjectNameStrategy:       cluster.SubjectNameStrategy,
+		AccessMode:                cluster.AccessMode,
+	}
+}
@@ -15,10 +15,7 @@ type Metadata struct {
 }
 
 // Params represents a map of parameters.
-type Params struct {
-	Property1 string `json:"property1"`
-	Property2 string `json:"property2"`
-}
+type Params map[string]string
 
 // Rule represents a rule within a rule set.
 type Rule struct {
@@ -53,4 +50,27 @@ type Schema struct {
 	RuleSet    RuleSet     `json:"ruleSet"`
 }
 
+// SubjectCompatibility represents the compatibility level in effect for a subject.
+type SubjectCompatibility struct {
+	Subject            string `json:"subject"`
+	CompatibilityLevel string `json:"compatibilityLevel"`
+}
 
+// CompatibilityResult represents the outcome of testing a schema against the latest version of a subject.
+type CompatibilityResult struct {
+	Subject      string   `json:"subject"`
+	IsCompatible bool     `json:"isCompatible"`
+	Messages     []string `json:"messages"`
+}
+
+// SchemaCompatibilityRequest describes a message contract to test before it is requested.
+type SchemaCompatibilityRequest struct {
+	TopicId             string              `json:"kafkaTopicId"`
+	MessageType         string              `json:"messageType"`
+	Schema              string              `json:"schema"`
+	SchemaType          SchemaType          `json:"schemaType"`
+	References          []Reference         `json:"references"`
+	Metadata            *Metadata           `json:"metadata"`
+	RuleSet             *RuleSet            `json:"ruleSet"`
+	SubjectNameStrategy SubjectNameStrategy `json:"subjectNameStrategy"`
+}
@@ -16,6 +16,9 @@ type CreateProcess struct {
 	TopicRetention  int64
 	CreatedAt       time.Time
 	CompletedAt     *time.Time
+	ResumeAttempts  int
+	ResumedAt       *time.Time
+	FailedAt        *time.Time
 }
 
 func NewCreateProcess(capabilityId CapabilityId, clusterId ClusterId, topicId string, topic TopicDescription) *CreateProcess {
@@ -53,3 +56,23 @@ func (p *CreateProcess) TopicDescription() TopicDescription {
 	topic, _ := NewTopicDescription(p.TopicName, p.TopicPartitions, RetentionFromMs(p.TopicRetention))
 	return topic
 }
+
+// IsFailed tells whether resuming the process has been given up on.
+func (p *CreateProcess) IsFailed() bool {
+	return p.FailedAt != nil
+}
+
+func (p *CreateProcess) MarkAsFailed() {
+	if p.IsFailed() {
+		return
+	}
+
+	now := time.Now()
+	p.FailedAt = &now
+}
+
+func (p *CreateProcess) CountResumeAttempt() {
+	now := time.Now()
+	p.ResumeAttempts++
+	p.ResumedAt = &now
+}
@@ -11,6 +11,9 @@ type DeleteProcess struct {
 	CreatedAt        time.Time
 	SchemasDeletedAt *time.Time
 	CompletedAt      *time.Time
+	ResumeAttempts   int
+	ResumedAt        *time.Time
+	FailedAt         *time.Time
 }
 
 func NewDeleteProcess(topicId string) *DeleteProcess {
@@ -51,3 +54,23 @@ func (p *DeleteProcess) MarkAsCompleted() {
 	now := time.Now()
 	p.CompletedAt = &now
 }
+
+// IsFailed tells whether resuming the process has been given up on.
+func (p *DeleteProcess) IsFailed() bool {
+	return p.FailedAt != nil
+}
+
+func (p *DeleteProcess) MarkAsFailed() {
+	if p.IsFailed() {
+		return
+	}
+
+	now := time.Now()
+	p.FailedAt = &now
+}
+
+func (p *DeleteProcess) CountResumeAttempt() {
+	now := time.Now()
+	p.ResumeAttempts++
+	p.ResumedAt = &now
+}
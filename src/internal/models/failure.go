@@ -0,0 +1,15 @@
+package models
+
+// FailureCode is the machine-readable cause of a failed process, raised with its failure event.
+type FailureCode string
+
+const (
+	FailureCodeInvalidName   FailureCode = "invalid_name"
+	FailureCodeInvalidConfig FailureCode = "invalid_config"
+	FailureCodeQuotaExceeded FailureCode = "quota_exceeded"
+	FailureCodeNotAuthorized FailureCode = "not_authorized"
+	FailureCodeNotFound      FailureCode = "not_found"
+	FailureCodeAlreadyExists FailureCode = "already_exists"
+	FailureCodeRejected      FailureCode = "rejected"
+	FailureCodeUnavailable   FailureCode = "unavailable"
+)
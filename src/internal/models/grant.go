@@ -0,0 +1,164 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+var ErrInvalidAccessGrant = errors.New("invalid access grant")
+var ErrAccessGrantAlreadyExists = errors.New("access grant already exists")
+
+// AccessGrantRequest asks for a capability to consume a private topic of another capability.
+type AccessGrantRequest struct {
+	ClusterId           ClusterId    `json:"clusterId"`
+	TopicName           string       `json:"topicName"`
+	GranteeCapabilityId CapabilityId `json:"granteeCapabilityId"`
+	ConsumerGroup       string       `json:"consumerGroup"`
+}
+
+func (r AccessGrantRequest) Validate() error {
+	var problems []string
+
+	if r.ClusterId == "" {
+		problems = append(problems, "clusterId is required")
+	}
+	if r.TopicName == "" {
+		problems = append(problems, "topicName is required")
+	}
+	if strings.HasPrefix(r.TopicName, "pub.") {
+		problems = append(problems, "topicName must be a private topic, public topics can be consumed by every capability")
+	}
+	if r.GranteeCapabilityId == "" {
+		problems = append(problems, "granteeCapabilityId is required")
+	}
+	if r.ConsumerGroup == "" {
+		problems = append(problems, "consumerGroup is required")
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("%w: %s", ErrInvalidAccessGrant, strings.Join(problems, ", "))
+	}
+
+	return nil
+}
+
+// NewGrantAclEntries returns the ACL entries that let the grantee consume a topic of the owner: reading and
+// describing the topic, and reading with the consumer group. The entries wait for the owner to approve the grant.
+func NewGrantAclEntries(clusterAccess *ClusterAccess, owner CapabilityId, topicName string, consumerGroup string) []AclEntry {
+	grantId := uuid.NewV4()
+
+	definitions := []AclDefinition{
+		defineAcl(ResourceTypeTopic, topicName, PatternTypeLiteral, OperationTypeRead, PermissionTypeAllow),
+		defineAcl(ResourceTypeTopic, topicName, PatternTypeLiteral, OperationTypeDescribe, PermissionTypeAllow),
+		defineAcl(ResourceTypeGroup, consumerGroup, PatternTypeLiteral, OperationTypeRead, PermissionTypeAllow),
+	}
+
+	entries := make([]AclEntry, len(definitions))
+	for i, definition := range definitions {
+		entries[i] = newAclEntry(definition, clusterAccess.Id)
+		entries[i].GrantId = &grantId
+		entries[i].GrantedBy = owner
+	}
+
+	return entries
+}
+
+// AccessGrant is the read model of the ACL entries of a grant.
+type AccessGrant struct {
+	Id                  string       `json:"id"`
+	ClusterId           ClusterId    `json:"clusterId"`
+	TopicName           string       `json:"topicName"`
+	ConsumerGroup       string       `json:"consumerGroup"`
+	OwnerCapabilityId   CapabilityId `json:"ownerCapabilityId"`
+	GranteeCapabilityId CapabilityId `json:"granteeCapabilityId"`
+	OwnerApproved       bool         `json:"ownerApproved"`
+	Created             bool         `json:"created"`
+}
+
+// NewAccessGrant describes the grant made of the entries, which must all belong to the same grant.
+func NewAccessGrant(grantee CapabilityId, clusterAccess *ClusterAccess, entries []AclEntry) AccessGrant {
+	grant := AccessGrant{
+		ClusterId:           clusterAccess.ClusterId,
+		GranteeCapabilityId: grantee,
+		Created:             true,
+	}
+
+	for _, entry := range entries {
+		grant.Id = entry.GrantId.String()
+		grant.OwnerCapabilityId = entry.GrantedBy
+		grant.OwnerApproved = entry.OwnerApproved
+		grant.Created = grant.Created && entry.IsValid()
+
+		switch entry.ResourceType {
+		case ResourceTypeTopic:
+			grant.TopicName = entry.ResourceName
+		case ResourceTypeGroup:
+			grant.ConsumerGroup = entry.ResourceName
+		}
+	}
+
+	return grant
+}
+
+// GetGrants returns the entries of the grants in the ACL, by grant id.
+func (ca *ClusterAccess) GetGrants() map[uuid.UUID][]AclEntry {
+	grants := map[uuid.UUID][]AclEntry{}
+
+	for _, entry := range ca.Acl {
+		if entry.IsGranted() {
+			grants[*entry.GrantId] = append(grants[*entry.GrantId], entry)
+		}
+	}
+
+	return grants
+}
+
+// HasGrant tells whether the ACL already has a grant to consume the topic.
+func (ca *ClusterAccess) HasGrant(topicName string) bool {
+	for _, entry := range ca.Acl {
+		if entry.IsGranted() && entry.ResourceType == ResourceTypeTopic && entry.ResourceName == topicName {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetGrantedTopics returns the topics of the grants their owners have approved.
+func (ca *ClusterAccess) GetGrantedTopics() []string {
+	var topics []string
+
+	for _, entries := range ca.GetGrants() {
+		if accessGrant := NewAccessGrant("", ca, entries); accessGrant.OwnerApproved {
+			topics = append(topics, accessGrant.TopicName)
+		}
+	}
+
+	sort.Strings(topics)
+	return topics
+}
+
+// GetGrantedSubjectsRoleBinding returns the role binding on the subjects of a granted topic, unless it is deleted.
+func (ca *ClusterAccess) GetGrantedSubjectsRoleBinding(cluster *Cluster, topicName string) *RoleBinding {
+	definition := GrantedSubjectsRoleBinding(cluster, topicName)
+
+	for i := range ca.RoleBindings {
+		if ca.RoleBindings[i].RoleBindingDefinition == definition && !ca.RoleBindings[i].IsDeleted() {
+			return &ca.RoleBindings[i]
+		}
+	}
+
+	return nil
+}
+
+// AddGrantedSubjectsRoleBinding adds the role binding on the subjects of a granted topic, pending creation. Unlike
+// AddRoleBindings, it adds the role binding again when it was deleted as an earlier grant on the topic was revoked.
+func (ca *ClusterAccess) AddGrantedSubjectsRoleBinding(cluster *Cluster, topicName string) *RoleBinding {
+	ca.RoleBindings = append(ca.RoleBindings, newRoleBinding(GrantedSubjectsRoleBinding(cluster, topicName), ca.Id))
+	return &ca.RoleBindings[len(ca.RoleBindings)-1]
+}
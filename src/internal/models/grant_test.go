@@ -0,0 +1,85 @@
+package models
+
+import (
+	"testing"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessGrantRequest_Validate(t *testing.T) {
+	valid := AccessGrantRequest{ClusterId: "abc-1234", TopicName: "owner-cap.orders", GranteeCapabilityId: "grantee-cap", ConsumerGroup: "grantee-cap.orders-consumer"}
+	assert.NoError(t, valid.Validate())
+
+	public := valid
+	public.TopicName = "pub.owner-cap.orders"
+	assert.ErrorIs(t, public.Validate(), ErrInvalidAccessGrant)
+
+	assert.ErrorIs(t, AccessGrantRequest{}.Validate(), ErrInvalidAccessGrant)
+}
+
+func TestNewGrantAclEntries(t *testing.T) {
+	clusterAccess := &ClusterAccess{Id: uuid.NewV4(), ClusterId: "abc-1234"}
+
+	entries := NewGrantAclEntries(clusterAccess, "owner-cap", "owner-cap.orders", "grantee-cap.orders-consumer")
+
+	assert.Len(t, entries, 3)
+	for _, entry := range entries {
+		assert.Equal(t, entries[0].GrantId, entry.GrantId)
+		assert.Equal(t, clusterAccess.Id, entry.ClusterAccessId)
+		assert.True(t, entry.IsAwaitingApproval())
+	}
+
+	clusterAccess.Acl = entries
+	assert.True(t, clusterAccess.HasGrant("owner-cap.orders"))
+	assert.Empty(t, clusterAccess.GetAclPendingCreation())
+
+	grant := NewAccessGrant("grantee-cap", clusterAccess, clusterAccess.GetGrants()[*entries[0].GrantId])
+	assert.Equal(t, AccessGrant{
+		Id:                  entries[0].GrantId.String(),
+		ClusterId:           "abc-1234",
+		TopicName:           "owner-cap.orders",
+		ConsumerGroup:       "grantee-cap.orders-consumer",
+		OwnerCapabilityId:   "owner-cap",
+		GranteeCapabilityId: "grantee-cap",
+	}, grant)
+}
+
+func TestApplyAclTemplate_KeepsGrantedEntries(t *testing.T) {
+	clusterAccess := &ClusterAccess{Id: uuid.NewV4(), ClusterId: "abc-1234"}
+	clusterAccess.Acl = NewGrantAclEntries(clusterAccess, "owner-cap", "owner-cap.orders", "grantee-cap.orders-consumer")
+
+	removed := clusterAccess.ApplyAclTemplate(DefaultAclTemplate("abc-1234"), "grantee-cap")
+
+	assert.Empty(t, removed)
+	assert.True(t, clusterAccess.HasGrant("owner-cap.orders"))
+}
+
+func TestClusterAccess_GetGrantedTopics(t *testing.T) {
+	clusterAccess := &ClusterAccess{Id: uuid.NewV4(), ClusterId: "abc-1234"}
+	approved := NewGrantAclEntries(clusterAccess, "owner-cap", "owner-cap.orders", "grantee-cap.orders-consumer")
+	for i := range approved {
+		approved[i].OwnerApproved = true
+	}
+	awaiting := NewGrantAclEntries(clusterAccess, "owner-cap", "owner-cap.invoices", "grantee-cap.invoices-consumer")
+	clusterAccess.Acl = append(approved, awaiting...)
+
+	assert.Equal(t, []string{"owner-cap.orders"}, clusterAccess.GetGrantedTopics())
+}
+
+func TestClusterAccess_AddGrantedSubjectsRoleBinding(t *testing.T) {
+	cluster := &Cluster{ClusterId: "abc-1234", OrganizationId: "org-1", EnvironmentId: "env-1", SchemaRegistryId: "lsrc-1234"}
+	clusterAccess := &ClusterAccess{Id: uuid.NewV4(), ClusterId: cluster.ClusterId}
+
+	assert.Nil(t, clusterAccess.GetGrantedSubjectsRoleBinding(cluster, "owner-cap.orders"))
+
+	revoked := clusterAccess.AddGrantedSubjectsRoleBinding(cluster, "owner-cap.orders")
+	assert.Equal(t, revoked, clusterAccess.GetGrantedSubjectsRoleBinding(cluster, "owner-cap.orders"))
+
+	revoked.Deleted()
+	assert.Nil(t, clusterAccess.GetGrantedSubjectsRoleBinding(cluster, "owner-cap.orders"))
+
+	regranted := clusterAccess.AddGrantedSubjectsRoleBinding(cluster, "owner-cap.orders")
+	assert.Equal(t, regranted, clusterAccess.GetGrantedSubjectsRoleBinding(cluster, "owner-cap.orders"))
+	assert.Len(t, clusterAccess.GetRoleBindingsPendingCreation(), 1)
+}
@@ -0,0 +1,25 @@
+package models
+
+type PlanActionKind string
+
+const (
+	PlanActionConfluent PlanActionKind = "confluent"
+	PlanActionVault     PlanActionKind = "vault"
+	PlanActionDatabase  PlanActionKind = "database"
+	PlanActionEvent     PlanActionKind = "event"
+)
+
+// PlanAction is a change the gateway would make while handling an event.
+type PlanAction struct {
+	Kind      PlanActionKind `json:"kind"`
+	Operation string         `json:"operation"`
+	Arguments map[string]any `json:"arguments,omitempty"`
+}
+
+// Plan lists the changes the gateway would make while handling an event, in the order it would make them. Error is
+// set when handling the event would fail, in which case the actions are the ones made before the failure.
+type Plan struct {
+	EventType string       `json:"eventType"`
+	Actions   []PlanAction `json:"actions"`
+	Error     string       `json:"error,omitempty"`
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+
+	"github.com/satori/go.uuid"
+)
+
+// ProcessStep records the progress of a single step of a process, whichever kind of process it belongs to.
+type ProcessStep struct {
+	Id            uuid.UUID `gorm:"type:uuid;primarykey"`
+	Process       string
+	ProcessId     string
+	Name          string
+	Attempts      int
+	LastError     string
+	StartedAt     time.Time
+	UpdatedAt     time.Time
+	CompletedAt   *time.Time
+	CompensatedAt *time.Time
+}
+
+func NewProcessStep(process string, processId string, name string) *ProcessStep {
+	now := time.Now()
+
+	return &ProcessStep{
+		Id:        uuid.NewV4(),
+		Process:   process,
+		ProcessId: processId,
+		Name:      name,
+		StartedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func (*ProcessStep) TableName() string {
+	return "process_step"
+}
+
+// MarkAttempt counts an attempt of running the step and keeps the error it failed with, if any.
+func (s *ProcessStep) MarkAttempt(err error) {
+	s.Attempts++
+	s.UpdatedAt = time.Now()
+
+	if err != nil {
+		s.LastError = err.Error()
+	}
+}
+
+func (s *ProcessStep) IsCompleted() bool {
+	return s.CompletedAt != nil
+}
+
+func (s *ProcessStep) MarkAsCompleted() {
+	now := time.Now()
+	s.UpdatedAt = now
+	s.CompletedAt = &now
+	s.CompensatedAt = nil
+}
+
+func (s *ProcessStep) IsCompensated() bool {
+	return s.CompensatedAt != nil
+}
+
+// MarkAsCompensated records that the side effects of the step have been rolled back, or that rolling them back
+// failed with the given error.
+func (s *ProcessStep) MarkAsCompensated(err error) {
+	now := time.Now()
+	s.UpdatedAt = now
+
+	if err != nil {
+		s.LastError = err.Error()
+		return
+	}
+
+	s.CompensatedAt = &now
+}
@@ -0,0 +1,175 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// AccessMode decides how the service accounts on a cluster are given access to its topics and groups.
+type AccessMode string
+
+const (
+	// AccessModeAcl grants access through the ACL template of the cluster, which is what the gateway has always done.
+	AccessModeAcl AccessMode = "acl"
+	// AccessModeRbac grants access through Confluent role bindings on the topic and group prefixes of the capability.
+	AccessModeRbac AccessMode = "rbac"
+)
+
+var AccessModes = []AccessMode{AccessModeAcl, AccessModeRbac}
+
+func (m AccessMode) IsValid() bool {
+	for _, mode := range AccessModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+type RoleName string
+
+const (
+	RoleNameDeveloperRead  RoleName = "DeveloperRead"
+	RoleNameDeveloperWrite RoleName = "DeveloperWrite"
+)
+
+// RoleBindingDefinition binds a role to the resources matching a Confluent resource name pattern.
+type RoleBindingDefinition struct {
+	RoleName   RoleName
+	CrnPattern string
+}
+
+func (d RoleBindingDefinition) String() string {
+	return fmt.Sprintf("%s on %s", d.RoleName, d.CrnPattern)
+}
+
+// RbacRoleBindings renders the role bindings a capability has on a cluster in RBAC mode, mirroring the topic and
+// group access of the default ACL template.
+func RbacRoleBindings(cluster *Cluster, capabilityId CapabilityId) []RoleBindingDefinition {
+	kafka := cluster.KafkaCrn()
+	topics := func(prefix string) string { return fmt.Sprintf("%s/topic=%s*", kafka, prefix) }
+	groups := func(prefix string) string { return fmt.Sprintf("%s/group=%s*", kafka, prefix) }
+
+	return []RoleBindingDefinition{
+		// for all private topics
+		{RoleNameDeveloperRead, topics(string(capabilityId))},
+		{RoleNameDeveloperWrite, topics(string(capabilityId))},
+
+		// for all public topics
+		{RoleNameDeveloperRead, topics("pub.")},
+
+		// for own public topics
+		{RoleNameDeveloperWrite, topics("pub." + string(capabilityId))},
+
+		// for all connect and capability groups
+		{RoleNameDeveloperRead, groups("connect-" + string(capabilityId))},
+		{RoleNameDeveloperRead, groups(string(capabilityId))},
+	}
+}
+
+// SchemaRegistryRoleBindings renders the role bindings a capability has on the schema registry of a cluster: writing
+// the subjects of its own topics, reading the subjects of all public topics and reading the subjects of the private
+// topics it has been granted.
+func SchemaRegistryRoleBindings(cluster *Cluster, capabilityId CapabilityId, grantedTopics []string) []RoleBindingDefinition {
+	subjects := func(prefix string) string { return fmt.Sprintf("%s/subject=%s*", cluster.SchemaRegistryCrn(), prefix) }
+
+	definitions := []RoleBindingDefinition{
+		{RoleNameDeveloperWrite, subjects(string(capabilityId))},
+		{RoleNameDeveloperRead, subjects("pub.")},
+	}
+	for _, topicName := range grantedTopics {
+		definitions = append(definitions, GrantedSubjectsRoleBinding(cluster, topicName))
+	}
+
+	return definitions
+}
+
+// GrantedSubjectsRoleBinding is the role binding on the subjects of a granted topic, which are the subjects named
+// after the topic.
+func GrantedSubjectsRoleBinding(cluster *Cluster, topicName string) RoleBindingDefinition {
+	return RoleBindingDefinition{RoleNameDeveloperRead, fmt.Sprintf("%s/subject=%s-*", cluster.SchemaRegistryCrn(), topicName)}
+}
+
+// AllSubjectsRoleBinding is the role binding on every subject of the schema registry, which service accounts were
+// given before their schema registry access was scoped to the capability.
+func AllSubjectsRoleBinding(cluster *Cluster) RoleBindingDefinition {
+	return RoleBindingDefinition{RoleNameDeveloperRead, cluster.SchemaRegistryCrn() + "/subject=*"}
+}
+
+// RoleBinding tracks a role binding of a cluster access, from being pending creation until it is deleted.
+type RoleBinding struct {
+	Id                    uuid.UUID `gorm:"primarykey"`
+	ClusterAccessId       uuid.UUID
+	RoleBindingDefinition `gorm:"embedded"`
+	// ConfluentId is the id Confluent gave the role binding when it was created.
+	ConfluentId string
+	CreatedAt   *time.Time
+	DeletedAt   *time.Time
+}
+
+func (*RoleBinding) TableName() string {
+	return "role_binding"
+}
+
+func newRoleBinding(definition RoleBindingDefinition, clusterAccessId uuid.UUID) RoleBinding {
+	return RoleBinding{
+		Id:                    uuid.NewV4(),
+		ClusterAccessId:       clusterAccessId,
+		RoleBindingDefinition: definition,
+	}
+}
+
+func (rb *RoleBinding) Created(confluentId string) {
+	rb.ConfluentId = confluentId
+	now := time.Now()
+	rb.CreatedAt = &now
+}
+
+func (rb *RoleBinding) IsCreated() bool {
+	return rb.CreatedAt != nil && rb.DeletedAt == nil
+}
+
+func (rb *RoleBinding) Deleted() {
+	now := time.Now()
+	rb.DeletedAt = &now
+}
+
+func (rb *RoleBinding) IsDeleted() bool {
+	return rb.DeletedAt != nil
+}
+
+// ConfluentRoleBinding is a role binding as Confluent lists it.
+type ConfluentRoleBinding struct {
+	Id        string
+	Principal string
+	RoleBindingDefinition
+}
+
+// RoleBindingInfo is the read model of a role binding.
+type RoleBindingInfo struct {
+	Id         string         `json:"id"`
+	RoleName   RoleName       `json:"roleName"`
+	CrnPattern string         `json:"crnPattern"`
+	Status     AclEntryStatus `json:"status"`
+	CreatedAt  *time.Time     `json:"createdAt"`
+	// ClusterId and ApplicationName place the role binding when it is listed outside of its cluster access.
+	ClusterId       ClusterId `json:"clusterId,omitempty"`
+	ApplicationName string    `json:"applicationName,omitempty"`
+}
+
+func NewRoleBindingInfo(roleBinding *RoleBinding) RoleBindingInfo {
+	status := AclEntryStatusPending
+	if roleBinding.IsCreated() {
+		status = AclEntryStatusCreated
+	}
+
+	return RoleBindingInfo{
+		Id:         roleBinding.Id.String(),
+		RoleName:   roleBinding.RoleName,
+		CrnPattern: roleBinding.CrnPattern,
+		Status:     status,
+		CreatedAt:  roleBinding.CreatedAt,
+	}
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"testing"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRbacRoleBindings(t *testing.T) {
+	cluster := &Cluster{ClusterId: "lkc-1234", OrganizationId: "org-1", EnvironmentId: "env-1", AccessMode: AccessModeRbac}
+
+	definitions := RbacRoleBindings(cluster, "some-cap")
+
+	kafka := "crn://confluent.cloud/organization=org-1/environment=env-1/cloud-cluster=lkc-1234/kafka=lkc-1234"
+	assert.Equal(t, []RoleBindingDefinition{
+		{RoleNameDeveloperRead, kafka + "/topic=some-cap*"},
+		{RoleNameDeveloperWrite, kafka + "/topic=some-cap*"},
+		{RoleNameDeveloperRead, kafka + "/topic=pub.*"},
+		{RoleNameDeveloperWrite, kafka + "/topic=pub.some-cap*"},
+		{RoleNameDeveloperRead, kafka + "/group=connect-some-cap*"},
+		{RoleNameDeveloperRead, kafka + "/group=some-cap*"},
+	}, definitions)
+}
+
+func TestNewRoleBindingClusterAccess(t *testing.T) {
+	cluster := &Cluster{ClusterId: "lkc-1234", OrganizationId: "org-1", EnvironmentId: "env-1", AccessMode: AccessModeRbac}
+
+	sut := NewRoleBindingClusterAccess("sa-1234", "User:1234", cluster, "some-cap")
+
+	assert.Empty(t, sut.Acl)
+	assert.Len(t, sut.GetRoleBindingsPendingCreation(), 6)
+
+	sut.RoleBindings[0].Created("rb-1")
+	sut.RoleBindings[1].Deleted()
+
+	assert.Len(t, sut.GetRoleBindingsPendingCreation(), 4)
+	assert.True(t, sut.RoleBindings[0].IsCreated())
+	assert.Equal(t, "rb-1", sut.RoleBindings[0].ConfluentId)
+}
+
+func TestSchemaRegistryRoleBindings(t *testing.T) {
+	cluster := &Cluster{ClusterId: "lkc-1234", OrganizationId: "org-1", EnvironmentId: "env-1", SchemaRegistryId: "lsrc-1234"}
+
+	definitions := SchemaRegistryRoleBindings(cluster, "some-cap", []string{"other-cap.orders"})
+
+	registry := "crn://confluent.cloud/organization=org-1/environment=env-1/schema-registry=lsrc-1234"
+	assert.True(t, cluster.HasSchemaRegistryIds())
+	assert.Equal(t, []RoleBindingDefinition{
+		{RoleNameDeveloperWrite, registry + "/subject=some-cap*"},
+		{RoleNameDeveloperRead, registry + "/subject=pub.*"},
+		{RoleNameDeveloperRead, registry + "/subject=other-cap.orders-*"},
+	}, definitions)
+	assert.Equal(t, RoleBindingDefinition{RoleNameDeveloperRead, registry + "/subject=*"}, AllSubjectsRoleBinding(cluster))
+}
+
+func TestClusterAccess_AddRoleBindings(t *testing.T) {
+	cluster := &Cluster{ClusterId: "lkc-1234", OrganizationId: "org-1", EnvironmentId: "env-1", SchemaRegistryId: "lsrc-1234"}
+	sut := &ClusterAccess{Id: uuid.NewV4(), ClusterId: cluster.ClusterId}
+
+	added := sut.AddRoleBindings(SchemaRegistryRoleBindings(cluster, "some-cap", nil))
+
+	assert.Len(t, added, 2)
+	assert.Equal(t, sut.Id, added[0].ClusterAccessId)
+	assert.Len(t, sut.GetRoleBindingsPendingCreation(), 2)
+
+	sut.RoleBindings[0].Created("rb-1")
+	sut.RoleBindings[1].Deleted()
+
+	assert.Empty(t, sut.AddRoleBindings(SchemaRegistryRoleBindings(cluster, "some-cap", nil)))
+	assert.Empty(t, sut.GetRoleBindingsPendingCreation())
+}
+
+func TestClusterRequest_ValidateAccessMode(t *testing.T) {
+	request := ClusterRequest{Name: "some-cluster", AdminApiEndpoint: "https://admin", BootstrapEndpoint: "bootstrap:9092"}
+
+	request.AccessMode = "bogus"
+	assert.ErrorIs(t, request.Validate(false), ErrInvalidCluster)
+
+	request.AccessMode = AccessModeRbac
+	assert.ErrorContains(t, request.Validate(false), "accessMode rbac requires organizationId and environmentId")
+
+	request.OrganizationId, request.EnvironmentId = "org-1", "env-1"
+	assert.NoError(t, request.Validate(false))
+
+	cluster := &Cluster{}
+	request.AccessMode = ""
+	request.Apply(cluster)
+	assert.Equal(t, AccessModeAcl, cluster.AccessMode)
+}
@@ -6,32 +6,126 @@ import (
 )
 
 type SchemaProcess struct {
-	Id                uuid.UUID `gorm:"type:uuid;primarykey"`
-	ClusterId         ClusterId
-	MessageContractId string
-	TopicId           string
-	MessageType       string
-	Description       string
-	Subject           string
-	Schema            string
-	CreatedAt         time.Time
-	CompletedAt       *time.Time
-	SchemaVersion     int32
-}
-
-func NewSchemaProcess(clusterId ClusterId, messageContractId string, topicId string, messageType string, description string, subject string, schema string, schemaVersion int32) *SchemaProcess {
+	Id                  uuid.UUID `gorm:"type:uuid;primarykey"`
+	ClusterId           ClusterId
+	MessageContractId   string
+	TopicId             string
+	MessageType         string
+	Description         string
+	Subject             string
+	SubjectNameStrategy SubjectNameStrategy
+	Schema              string
+	SchemaType          SchemaType
+	References          []Reference `gorm:"column:schema_references;serializer:json"`
+	Metadata            *Metadata   `gorm:"column:schema_metadata;serializer:json"`
+	RuleSet             *RuleSet    `gorm:"column:schema_rule_set;serializer:json"`
+	CompatibilityLevel  CompatibilityLevel
+	CreatedAt           time.Time
+	CompletedAt         *time.Time
+	SchemaVersion       int32
+	RegistryVersion     int32
+	DeletedAt           *time.Time
+	PermanentlyDeleted  bool
+	ResumeAttempts      int
+	ResumedAt           *time.Time
+	FailedAt            *time.Time
+}
+
+type SchemaType string
+
+const (
+	SchemaTypeAvro     SchemaType = "AVRO"
+	SchemaTypeProtobuf SchemaType = "PROTOBUF"
+	SchemaTypeJson     SchemaType = "JSON"
+)
+
+// SchemaDefinition is what gets sent to the schema registry: the schema, its type and the schemas it references.
+type SchemaDefinition struct {
+	SchemaType SchemaType  `json:"schemaType"`
+	Schema     string      `json:"schema"`
+	References []Reference `json:"references,omitempty"`
+	Metadata   *Metadata   `json:"metadata,omitempty"`
+	RuleSet    *RuleSet    `json:"ruleSet,omitempty"`
+}
+
+// NewSchemaDefinition defaults to a JSON schema, which was the only type supported before the type was sent along.
+func NewSchemaDefinition(schemaType SchemaType, schema string, references []Reference) SchemaDefinition {
+	if schemaType == "" {
+		schemaType = SchemaTypeJson
+	}
+
+	return SchemaDefinition{
+		SchemaType: schemaType,
+		Schema:     schema,
+		References: references,
+	}
+}
+
+// WithRules attaches the metadata, e.g. tags and sensitive fields, and the migration and domain rules that are
+// registered along with the schema.
+func (d SchemaDefinition) WithRules(metadata *Metadata, ruleSet *RuleSet) SchemaDefinition {
+	d.Metadata = metadata
+	d.RuleSet = ruleSet
+	return d
+}
+
+type CompatibilityLevel string
+
+const (
+	CompatibilityBackward           CompatibilityLevel = "BACKWARD"
+	CompatibilityBackwardTransitive CompatibilityLevel = "BACKWARD_TRANSITIVE"
+	CompatibilityForward            CompatibilityLevel = "FORWARD"
+	CompatibilityForwardTransitive  CompatibilityLevel = "FORWARD_TRANSITIVE"
+	CompatibilityFull               CompatibilityLevel = "FULL"
+	CompatibilityFullTransitive     CompatibilityLevel = "FULL_TRANSITIVE"
+	CompatibilityNone               CompatibilityLevel = "NONE"
+)
+
+func (l CompatibilityLevel) IsValid() bool {
+	switch l {
+	case CompatibilityBackward, CompatibilityBackwardTransitive,
+		CompatibilityForward, CompatibilityForwardTransitive,
+		CompatibilityFull, CompatibilityFullTransitive,
+		CompatibilityNone:
+		return true
+	}
+	return false
+}
+
+// CapabilitySchemaSettings holds the schema defaults of a capability, used when a contract does not specify them.
+type CapabilitySchemaSettings struct {
+	CapabilityId       CapabilityId       `gorm:"primarykey" json:"capabilityId"`
+	CompatibilityLevel CompatibilityLevel `json:"compatibilityLevel"`
+	UpdatedAt          time.Time          `json:"updatedAt"`
+}
+
+func (*CapabilitySchemaSettings) TableName() string {
+	return "capability_schema_settings"
+}
+
+type CompatibilityLevelRequest struct {
+	CompatibilityLevel CompatibilityLevel `json:"compatibilityLevel"`
+}
+
+func NewSchemaProcess(clusterId ClusterId, messageContractId string, topicId string, messageType string, description string, subject string, subjectNameStrategy SubjectNameStrategy, schema SchemaDefinition, compatibilityLevel CompatibilityLevel, schemaVersion int32) *SchemaProcess {
 	return &SchemaProcess{
-		Id:                uuid.NewV4(),
-		ClusterId:         clusterId,
-		MessageContractId: messageContractId,
-		TopicId:           topicId,
-		MessageType:       messageType,
-		Description:       description,
-		Subject:           subject,
-		Schema:            schema,
-		CreatedAt:         time.Now(),
-		CompletedAt:       nil,
-		SchemaVersion:     schemaVersion,
+		Id:                  uuid.NewV4(),
+		ClusterId:           clusterId,
+		MessageContractId:   messageContractId,
+		TopicId:             topicId,
+		MessageType:         messageType,
+		Description:         description,
+		Subject:             subject,
+		SubjectNameStrategy: subjectNameStrategy,
+		Schema:              schema.Schema,
+		SchemaType:          schema.SchemaType,
+		References:          schema.References,
+		Metadata:            schema.Metadata,
+		RuleSet:             schema.RuleSet,
+		CompatibilityLevel:  compatibilityLevel,
+		CreatedAt:           time.Now(),
+		CompletedAt:         nil,
+		SchemaVersion:       schemaVersion,
 	}
 }
 
@@ -39,10 +133,29 @@ func (*SchemaProcess) TableName() string {
 	return "schema_process"
 }
 
+func (p *SchemaProcess) Definition() SchemaDefinition {
+	return NewSchemaDefinition(p.SchemaType, p.Schema, p.References).WithRules(p.Metadata, p.RuleSet)
+}
+
 func (p *SchemaProcess) IsCompleted() bool {
 	return p.CompletedAt != nil
 }
 
+func (p *SchemaProcess) IsDeleted() bool {
+	return p.DeletedAt != nil
+}
+
+// MarkAsDeleted records the schema as deleted. A permanent delete can follow a soft delete, but not the other way
+// around.
+func (p *SchemaProcess) MarkAsDeleted(permanent bool) {
+	if !p.IsDeleted() {
+		now := time.Now()
+		p.DeletedAt = &now
+	}
+
+	p.PermanentlyDeleted = p.PermanentlyDeleted || permanent
+}
+
 func (p *SchemaProcess) MarkAsCompleted() {
 	if p.IsCompleted() {
 		return
@@ -51,3 +164,23 @@ func (p *SchemaProcess) MarkAsCompleted() {
 	now := time.Now()
 	p.CompletedAt = &now
 }
+
+// IsFailed tells whether resuming the process has been given up on.
+func (p *SchemaProcess) IsFailed() bool {
+	return p.FailedAt != nil
+}
+
+func (p *SchemaProcess) MarkAsFailed() {
+	if p.IsFailed() {
+		return
+	}
+
+	now := time.Now()
+	p.FailedAt = &now
+}
+
+func (p *SchemaProcess) CountResumeAttempt() {
+	now := time.Now()
+	p.ResumeAttempts++
+	p.ResumedAt = &now
+}
@@ -1,8 +1,11 @@
 package models
 
 import (
+	"errors"
 	"fmt"
 	uuid "github.com/satori/go.uuid"
+	"regexp"
+	"slices"
 	"time"
 )
 
@@ -16,10 +19,36 @@ func MakeUserAccountId(id int) UserAccountId {
 
 type CapabilityId string
 
+var ErrInvalidApplicationName = errors.New("invalid application name")
+
+var applicationName = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,30}[a-z0-9])?$`)
+
+// ValidateApplicationName checks that the name of an application can be part of the name of its service account
+// and of the path to its api keys. The empty name is the default service account of the capability.
+func ValidateApplicationName(name string) error {
+	if name == "" || applicationName.MatchString(name) {
+		return nil
+	}
+	return fmt.Errorf("%w: %q must be lowercase letters, digits and dashes, at most 32 characters", ErrInvalidApplicationName, name)
+}
+
+// ServiceAccountDisplayName names the service account of an application of the capability in Confluent, the
+// default service account being named after the capability alone. The application is separated by a dot, which
+// capability ids cannot contain, so that the name of an application never matches the default service account of
+// another capability.
+func ServiceAccountDisplayName(capabilityId CapabilityId, applicationName string) string {
+	if applicationName == "" {
+		return string(capabilityId)
+	}
+	return fmt.Sprintf("%s.%s", capabilityId, applicationName)
+}
+
 type ServiceAccount struct {
-	Id              ServiceAccountId `gorm:"primarykey"`
-	UserAccountId   UserAccountId
-	CapabilityId    CapabilityId
+	Id            ServiceAccountId `gorm:"primarykey"`
+	UserAccountId UserAccountId
+	CapabilityId  CapabilityId
+	// ApplicationName tells the service accounts of a capability apart, and is empty for its default service account.
+	ApplicationName string
 	ClusterAccesses []ClusterAccess
 	CreatedAt       time.Time
 }
@@ -38,12 +67,15 @@ func (sa *ServiceAccount) TryGetClusterAccess(clusterId ClusterId) (*ClusterAcce
 }
 
 type ClusterAccess struct {
-	Id               uuid.UUID `gorm:"primarykey"`
-	ClusterId        ClusterId
-	ServiceAccountId ServiceAccountId
-	UserAccountId    UserAccountId
-	Acl              []AclEntry
-	CreatedAt        time.Time
+	Id                 uuid.UUID `gorm:"primarykey"`
+	ClusterId          ClusterId
+	ServiceAccountId   ServiceAccountId
+	UserAccountId      UserAccountId
+	Acl                []AclEntry
+	RoleBindings       []RoleBinding
+	AclTemplateVersion int
+	Profiles           []AccessProfile `gorm:"serializer:json"`
+	CreatedAt          time.Time
 }
 
 func (*ClusterAccess) TableName() string {
@@ -54,7 +86,7 @@ func (ca *ClusterAccess) GetAclPendingCreation() []AclEntry {
 	var pending []AclEntry
 
 	for _, entry := range ca.Acl {
-		if entry.CreatedAt == nil {
+		if entry.CreatedAt == nil && !entry.IsAwaitingApproval() {
 			pending = append(pending, entry)
 		}
 	}
@@ -62,40 +94,190 @@ func (ca *ClusterAccess) GetAclPendingCreation() []AclEntry {
 	return pending
 }
 
-func NewClusterAccess(serviceAccountId ServiceAccountId, userAccountId UserAccountId, clusterId ClusterId, capabilityId CapabilityId) *ClusterAccess {
+func NewClusterAccess(serviceAccountId ServiceAccountId, userAccountId UserAccountId, clusterId ClusterId, capabilityId CapabilityId, template *AclTemplate) *ClusterAccess {
 	clusterAccessId := uuid.NewV4()
 
+	return &ClusterAccess{
+		Id:                 clusterAccessId,
+		ServiceAccountId:   serviceAccountId,
+		UserAccountId:      userAccountId,
+		ClusterId:          clusterId,
+		Acl:                createAclEntries(template.Render(capabilityId), clusterAccessId),
+		AclTemplateVersion: template.Version,
+		CreatedAt:          time.Now(),
+	}
+}
+
+// NewRoleBindingClusterAccess gives a service account access to a cluster in RBAC mode, through role bindings pending
+// creation rather than the ACL template of the cluster.
+func NewRoleBindingClusterAccess(serviceAccountId ServiceAccountId, userAccountId UserAccountId, cluster *Cluster, capabilityId CapabilityId) *ClusterAccess {
+	clusterAccessId := uuid.NewV4()
+
+	definitions := RbacRoleBindings(cluster, capabilityId)
+	roleBindings := make([]RoleBinding, len(definitions))
+	for i, definition := range definitions {
+		roleBindings[i] = newRoleBinding(definition, clusterAccessId)
+	}
+
 	return &ClusterAccess{
 		Id:               clusterAccessId,
 		ServiceAccountId: serviceAccountId,
 		UserAccountId:    userAccountId,
-		ClusterId:        clusterId,
-		Acl:              createAclEntries(capabilityId, clusterAccessId),
+		ClusterId:        cluster.ClusterId,
+		RoleBindings:     roleBindings,
 		CreatedAt:        time.Now(),
 	}
 }
 
-func createAclEntries(capabilityId CapabilityId, clusterAccessId uuid.UUID) []AclEntry {
-	allDefinitions := CreateAclDefinitions(capabilityId)
+// GetRoleBindingsPendingCreation returns the role bindings not yet created in Confluent.
+func (ca *ClusterAccess) GetRoleBindingsPendingCreation() []RoleBinding {
+	var pending []RoleBinding
+
+	for _, roleBinding := range ca.RoleBindings {
+		if roleBinding.CreatedAt == nil && !roleBinding.IsDeleted() {
+			pending = append(pending, roleBinding)
+		}
+	}
+
+	return pending
+}
+
+// AddRoleBindings adds the role bindings the cluster access has never had, pending creation, and returns them. A role
+// binding that was deleted is not added again.
+func (ca *ClusterAccess) AddRoleBindings(definitions []RoleBindingDefinition) []RoleBinding {
+	var added []RoleBinding
+
+	for _, definition := range definitions {
+		if ca.hasRoleBinding(definition) {
+			continue
+		}
+		roleBinding := newRoleBinding(definition, ca.Id)
+		ca.RoleBindings = append(ca.RoleBindings, roleBinding)
+		added = append(added, roleBinding)
+	}
 
-	acl := make([]AclEntry, len(allDefinitions))
+	return added
+}
 
-	for i, definition := range allDefinitions {
-		acl[i] = AclEntry{
-			Id:              uuid.NewV4(),
-			ClusterAccessId: clusterAccessId,
-			CreatedAt:       nil,
-			AclDefinition:   definition,
+func (ca *ClusterAccess) hasRoleBinding(definition RoleBindingDefinition) bool {
+	for _, roleBinding := range ca.RoleBindings {
+		if roleBinding.RoleBindingDefinition == definition {
+			return true
 		}
 	}
+	return false
+}
+
+func createAclEntries(definitions []AclDefinition, clusterAccessId uuid.UUID) []AclEntry {
+	acl := make([]AclEntry, len(definitions))
+
+	for i, definition := range definitions {
+		acl[i] = newAclEntry(definition, clusterAccessId)
+	}
 	return acl
 }
 
+func newAclEntry(definition AclDefinition, clusterAccessId uuid.UUID) AclEntry {
+	return AclEntry{
+		Id:              uuid.NewV4(),
+		ClusterAccessId: clusterAccessId,
+		CreatedAt:       nil,
+		AclDefinition:   definition,
+	}
+}
+
+// ApplyAclTemplate brings the ACL up to the version of the template. The entries missing from the ACL are added
+// pending creation, and the entries no longer in the template are removed from the ACL and returned. The ACL of
+// the enabled profiles counts as part of the template, and entries granted by other capabilities are left as they
+// are.
+func (ca *ClusterAccess) ApplyAclTemplate(template *AclTemplate, capabilityId CapabilityId) []AclEntry {
+	definitions := template.Render(capabilityId)
+	for _, profile := range ca.Profiles {
+		definitions = append(definitions, profile.Render(capabilityId)...)
+	}
+
+	desired := map[AclDefinition]bool{}
+	for _, definition := range definitions {
+		desired[definition] = true
+	}
+
+	var kept, removed []AclEntry
+	for _, entry := range ca.Acl {
+		if entry.IsGranted() {
+			kept = append(kept, entry)
+		} else if desired[entry.AclDefinition] {
+			kept = append(kept, entry)
+			delete(desired, entry.AclDefinition)
+		} else {
+			removed = append(removed, entry)
+		}
+	}
+
+	for _, definition := range definitions {
+		if desired[definition] {
+			kept = append(kept, newAclEntry(definition, ca.Id))
+			delete(desired, definition)
+		}
+	}
+
+	ca.Acl = kept
+	ca.AclTemplateVersion = template.Version
+
+	return removed
+}
+
+// HasProfiles tells whether the profiles have been enabled on the cluster access.
+func (ca *ClusterAccess) HasProfiles(profiles []AccessProfile) bool {
+	for _, profile := range profiles {
+		if !slices.Contains(ca.Profiles, profile) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// EnableProfiles adds the ACL of the profiles not yet enabled, pending creation, and returns the added entries.
+func (ca *ClusterAccess) EnableProfiles(profiles []AccessProfile, capabilityId CapabilityId) []AclEntry {
+	existing := map[AclDefinition]bool{}
+	for _, entry := range ca.Acl {
+		if !entry.IsGranted() {
+			existing[entry.AclDefinition] = true
+		}
+	}
+
+	var added []AclEntry
+	for _, profile := range profiles {
+		if slices.Contains(ca.Profiles, profile) {
+			continue
+		}
+
+		for _, definition := range profile.Render(capabilityId) {
+			if !existing[definition] {
+				added = append(added, newAclEntry(definition, ca.Id))
+				existing[definition] = true
+			}
+		}
+		ca.Profiles = append(ca.Profiles, profile)
+	}
+
+	ca.Acl = append(ca.Acl, added...)
+
+	return added
+}
+
 type AclEntry struct {
 	Id              uuid.UUID `gorm:"primarykey"`
 	ClusterAccessId uuid.UUID
 	CreatedAt       *time.Time `gorm:"autoCreateTime:false"`
+	DeletedAt       *time.Time
 	AclDefinition
+
+	// GrantId is set on the entries another capability granted, which the owner of the topic has to approve
+	// before they are created.
+	GrantId       *uuid.UUID
+	GrantedBy     CapabilityId
+	OwnerApproved bool
 }
 
 func (*AclEntry) TableName() string {
@@ -107,6 +289,24 @@ func (e *AclEntry) Created() {
 	e.CreatedAt = &now
 }
 
+// Deleted marks the entry as removed from the cluster. Deleted entries are kept as a record of past access.
+func (e *AclEntry) Deleted() {
+	now := time.Now()
+	e.DeletedAt = &now
+}
+
 func (e *AclEntry) IsValid() bool {
-	return e.CreatedAt != nil
+	return e.CreatedAt != nil && !e.IsDeleted()
+}
+
+func (e *AclEntry) IsDeleted() bool {
+	return e.DeletedAt != nil
+}
+
+func (e *AclEntry) IsGranted() bool {
+	return e.GrantId != nil
+}
+
+func (e *AclEntry) IsAwaitingApproval() bool {
+	return e.IsGranted() && !e.OwnerApproved
 }
@@ -51,7 +51,7 @@ func TestClusterAccess_GetAclPendingCreation(t *testing.T) {
 		},
 		{
 			name: "all",
-			acl:  createAclEntries("some-cap", uuid.NewV4()),
+			acl:  createAclEntries(CreateAclDefinitions("some-cap"), uuid.NewV4()),
 			want: 17,
 		},
 	}
@@ -65,3 +65,54 @@ func TestClusterAccess_GetAclPendingCreation(t *testing.T) {
 		})
 	}
 }
+
+func TestClusterAccess_ApplyAclTemplate(t *testing.T) {
+	now := time.Now()
+	template := &AclTemplate{Version: 2, Entries: []AclTemplateEntry{
+		{ResourceTypeTopic, CapabilityIdPlaceholder, PatternTypePrefix, OperationTypeRead, PermissionTypeAllow},
+		{ResourceTypeGroup, CapabilityIdPlaceholder, PatternTypePrefix, OperationTypeRead, PermissionTypeAllow},
+	}}
+	kept := AclEntry{Id: uuid.NewV4(), CreatedAt: &now, AclDefinition: defineAcl(ResourceTypeTopic, "some-cap", PatternTypePrefix, OperationTypeRead, PermissionTypeAllow)}
+	obsolete := AclEntry{Id: uuid.NewV4(), CreatedAt: &now, AclDefinition: defineAcl(ResourceTypeTopic, "pub.", PatternTypePrefix, OperationTypeRead, PermissionTypeAllow)}
+	sut := &ClusterAccess{Id: uuid.NewV4(), Acl: []AclEntry{kept, obsolete}}
+
+	removed := sut.ApplyAclTemplate(template, "some-cap")
+
+	assert.Equal(t, []AclEntry{obsolete}, removed)
+	assert.Equal(t, 2, sut.AclTemplateVersion)
+	assert.Equal(t, []string{
+		"TOPIC | some-cap | PREFIXED | READ | ALLOW",
+		"GROUP | some-cap | PREFIXED | READ | ALLOW",
+	}, mapToString([]AclDefinition{sut.Acl[0].AclDefinition, sut.Acl[1].AclDefinition}))
+	assert.Equal(t, kept, sut.Acl[0])
+	assert.Equal(t, sut.Id, sut.Acl[1].ClusterAccessId)
+	assert.Len(t, sut.GetAclPendingCreation(), 1)
+}
+
+func TestAclEntry_Lifecycle(t *testing.T) {
+	sut := newAclEntry(defineAcl(ResourceTypeTopic, "some-cap", PatternTypePrefix, OperationTypeRead, PermissionTypeAllow), uuid.NewV4())
+	assert.False(t, sut.IsValid())
+
+	sut.Created()
+	assert.True(t, sut.IsValid())
+	assert.False(t, sut.IsDeleted())
+
+	sut.Deleted()
+	assert.False(t, sut.IsValid())
+	assert.True(t, sut.IsDeleted())
+}
+
+func TestValidateApplicationName(t *testing.T) {
+	for _, name := range []string{"", "billing", "billing-api-2"} {
+		assert.NoError(t, ValidateApplicationName(name), name)
+	}
+	for _, name := range []string{"Billing", "billing_api", "-billing", "billing-", "a-very-long-application-name-over-32"} {
+		assert.ErrorIs(t, ValidateApplicationName(name), ErrInvalidApplicationName, name)
+	}
+}
+
+func TestServiceAccountDisplayName(t *testing.T) {
+	assert.Equal(t, "some-cap", ServiceAccountDisplayName("some-cap", ""))
+	assert.Equal(t, "some-cap.billing", ServiceAccountDisplayName("some-cap", "billing"))
+	assert.NotEqual(t, ServiceAccountDisplayName("some-cap-billing", ""), ServiceAccountDisplayName("some-cap", "billing"))
+}
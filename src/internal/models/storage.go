@@ -2,6 +2,7 @@ package models
 
 import (
 	"context"
+	"time"
 
 	"github.com/dfds/confluent-gateway/messaging"
 )
@@ -16,18 +17,31 @@ type Session interface {
 
 type Transaction interface {
 	GetServiceAccount(CapabilityId) (*ServiceAccount, error)
+	GetApplicationServiceAccount(CapabilityId, string) (*ServiceAccount, error)
+	GetServiceAccounts(CapabilityId) ([]*ServiceAccount, error)
 	CreateServiceAccount(*ServiceAccount) error
 	UpdateAclEntry(*AclEntry) error
+	CreateAclEntry(*AclEntry) error
+	DeleteAclEntry(*AclEntry) error
+	CreateRoleBinding(*RoleBinding) error
+	UpdateRoleBinding(*RoleBinding) error
+	DeleteRoleBinding(*RoleBinding) error
 	CreateClusterAccess(*ClusterAccess) error
 	UpdateClusterAccess(*ClusterAccess) error
 
+	GetAclTemplate(ClusterId) (*AclTemplate, error)
+
 	GetCreateProcessState(CapabilityId, ClusterId, string) (*CreateProcess, error)
 	SaveCreateProcessState(*CreateProcess) error
 	UpdateCreateProcessState(*CreateProcess) error
+	LockCreateProcessState(*CreateProcess) error
 
 	GetDeleteProcessState(string) (*DeleteProcess, error)
 	SaveDeleteProcessState(*DeleteProcess) error
 	UpdateDeleteProcessState(*DeleteProcess) error
+	LockDeleteProcessState(*DeleteProcess) error
+
+	GetCluster(ClusterId) (*Cluster, error)
 
 	GetTopic(string) (*Topic, error)
 	CreateTopic(*Topic) error
@@ -36,9 +50,21 @@ type Transaction interface {
 	GetSchemaProcessState(string) (*SchemaProcess, error)
 	SaveSchemaProcessState(*SchemaProcess) error
 	UpdateSchemaProcessState(*SchemaProcess) error
+	LockSchemaProcessState(*SchemaProcess) error
 
 	SelectSchemaProcessStatesByTopicId(string) ([]SchemaProcess, error)
+	SelectSchemaProcessStatesBySubject(ClusterId, string) ([]SchemaProcess, error)
+	HasRecordNameSubjects(ClusterId) (bool, error)
 	DeleteSchemaProcessStateById(string) error
 
+	GetCapabilitySchemaSettings(CapabilityId) (*CapabilitySchemaSettings, error)
+
+	SelectStuckCreateProcessStates(time.Time, time.Time) ([]*CreateProcess, error)
+	SelectStuckDeleteProcessStates(time.Time, time.Time) ([]*DeleteProcess, error)
+	SelectStuckSchemaProcessStates(time.Time, time.Time) ([]*SchemaProcess, error)
+
+	GetProcessSteps(string, string) ([]*ProcessStep, error)
+	SaveProcessStep(*ProcessStep) error
+
 	AddToOutbox(*messaging.OutboxEntry) error
 }
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// SubjectNameStrategy decides the schema registry subject a message contract is registered under.
+type SubjectNameStrategy string
+
+const (
+	// SubjectNameStrategyTopicMessageType registers under "<topic>-<message type>", which is what the gateway has
+	// always done and remains the default.
+	SubjectNameStrategyTopicMessageType SubjectNameStrategy = "TopicMessageTypeNameStrategy"
+	// SubjectNameStrategyTopic registers under "<topic>-value", like the default of the Confluent serializers.
+	SubjectNameStrategyTopic SubjectNameStrategy = "TopicNameStrategy"
+	// SubjectNameStrategyRecord registers under the fully qualified record name of the schema.
+	SubjectNameStrategyRecord SubjectNameStrategy = "RecordNameStrategy"
+	// SubjectNameStrategyTopicRecord registers under "<topic>-<fully qualified record name>".
+	SubjectNameStrategyTopicRecord SubjectNameStrategy = "TopicRecordNameStrategy"
+)
+
+var ErrNoRecordName = errors.New("schema has no record name")
+
+func (s SubjectNameStrategy) IsValid() bool {
+	switch s {
+	case SubjectNameStrategyTopicMessageType, SubjectNameStrategyTopic, SubjectNameStrategyRecord, SubjectNameStrategyTopicRecord:
+		return true
+	default:
+		return false
+	}
+}
+
+// UsesRecordName tells whether the subject is derived from the record name found in the schema.
+func (s SubjectNameStrategy) UsesRecordName() bool {
+	return s == SubjectNameStrategyRecord || s == SubjectNameStrategyTopicRecord
+}
+
+// SubjectName returns the subject of a message type on a topic according to the strategy. An empty strategy is
+// the default strategy.
+func (s SubjectNameStrategy) SubjectName(topicName string, messageType string, schema SchemaDefinition) (string, error) {
+	switch s {
+	case "", SubjectNameStrategyTopicMessageType:
+		return SubjectName(topicName, messageType), nil
+	case SubjectNameStrategyTopic:
+		return topicName + "-value", nil
+	case SubjectNameStrategyRecord, SubjectNameStrategyTopicRecord:
+		recordName, err := RecordName(schema)
+		if err != nil {
+			return "", err
+		}
+		if s == SubjectNameStrategyRecord {
+			return recordName, nil
+		}
+		return fmt.Sprintf("%s-%s", topicName, recordName), nil
+	default:
+		return "", fmt.Errorf("unknown subject name strategy %q", s)
+	}
+}
+
+// SubjectName is the schema registry subject of a message type on a topic.
+func SubjectName(topicName string, messageType string) string {
+	return fmt.Sprintf("%s-%s", topicName, messageType)
+}
+
+var protobufPackage = regexp.MustCompile(`(?m)^\s*package\s+([A-Za-z_][A-Za-z0-9_.]*)\s*;`)
+var protobufMessage = regexp.MustCompile(`(?m)^\s*message\s+([A-Za-z_][A-Za-z0-9_]*)`)
+
+// RecordName finds the fully qualified name of the record a schema describes, the same way the Confluent
+// serializers do: the name and namespace of an Avro record, the package and first message of a Protobuf schema
+// and the title of a JSON schema.
+func RecordName(schema SchemaDefinition) (string, error) {
+	switch schema.SchemaType {
+	case SchemaTypeAvro:
+		var record struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		}
+		if err := json.Unmarshal([]byte(schema.Schema), &record); err != nil || record.Name == "" {
+			return "", ErrNoRecordName
+		}
+		if record.Namespace == "" || strings.Contains(record.Name, ".") {
+			return record.Name, nil
+		}
+		return record.Namespace + "." + record.Name, nil
+	case SchemaTypeProtobuf:
+		message := protobufMessage.FindStringSubmatch(schema.Schema)
+		if message == nil {
+			return "", ErrNoRecordName
+		}
+		if pkg := protobufPackage.FindStringSubmatch(schema.Schema); pkg != nil {
+			return pkg[1] + "." + message[1], nil
+		}
+		return message[1], nil
+	default:
+		var jsonSchema struct {
+			Title string `json:"title"`
+		}
+		if err := json.Unmarshal([]byte(schema.Schema), &jsonSchema); err != nil || jsonSchema.Title == "" {
+			return "", ErrNoRecordName
+		}
+		return jsonSchema.Title, nil
+	}
+}
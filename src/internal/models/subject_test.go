@@ -0,0 +1,38 @@
+package models
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSubjectNameStrategy_SubjectName(t *testing.T) {
+	avro := NewSchemaDefinition(SchemaTypeAvro, `{"type":"record","name":"SomeRecord","namespace":"com.example","fields":[]}`, nil)
+	protobuf := NewSchemaDefinition(SchemaTypeProtobuf, "syntax = \"proto3\";\npackage com.example;\nmessage SomeRecord {}\n", nil)
+	jsonSchema := NewSchemaDefinition(SchemaTypeJson, `{"title":"SomeRecord","type":"object"}`, nil)
+	untitled := NewSchemaDefinition(SchemaTypeJson, `{"type":"object"}`, nil)
+
+	tests := []struct {
+		name     string
+		strategy SubjectNameStrategy
+		schema   SchemaDefinition
+		want     string
+		wantErr  assert.ErrorAssertionFunc
+	}{
+		{name: "empty is default", strategy: "", schema: avro, want: "some-topic-some-event", wantErr: assert.NoError},
+		{name: "topic message type", strategy: SubjectNameStrategyTopicMessageType, schema: avro, want: "some-topic-some-event", wantErr: assert.NoError},
+		{name: "topic", strategy: SubjectNameStrategyTopic, schema: avro, want: "some-topic-value", wantErr: assert.NoError},
+		{name: "avro record", strategy: SubjectNameStrategyRecord, schema: avro, want: "com.example.SomeRecord", wantErr: assert.NoError},
+		{name: "protobuf record", strategy: SubjectNameStrategyRecord, schema: protobuf, want: "com.example.SomeRecord", wantErr: assert.NoError},
+		{name: "json topic record", strategy: SubjectNameStrategyTopicRecord, schema: jsonSchema, want: "some-topic-SomeRecord", wantErr: assert.NoError},
+		{name: "no record name", strategy: SubjectNameStrategyRecord, schema: untitled, want: "", wantErr: assert.Error},
+		{name: "unknown strategy", strategy: "SomethingElse", schema: avro, want: "", wantErr: assert.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.strategy.SubjectName("some-topic", "some-event", tt.schema)
+
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -31,6 +31,38 @@ func (*Topic) TableName() string {
 	return "topic"
 }
 
+// KafkaTopic is the live description of a topic as reported by the Kafka REST API.
+type KafkaTopic struct {
+	Name              string            `json:"name"`
+	PartitionsCount   int               `json:"partitionsCount"`
+	ReplicationFactor int               `json:"replicationFactor"`
+	Configs           map[string]string `json:"configs"`
+}
+
+// TopicInfo is the read model of a topic returned by the HTTP API.
+type TopicInfo struct {
+	Id           string      `json:"id"`
+	CapabilityId string      `json:"capabilityId"`
+	ClusterId    string      `json:"clusterId"`
+	Name         string      `json:"name"`
+	Partitions   int         `json:"partitions"`
+	Retention    int64       `json:"retention"`
+	CreatedAt    time.Time   `json:"createdAt"`
+	Live         *KafkaTopic `json:"live,omitempty"`
+}
+
+func NewTopicInfo(topic *Topic) TopicInfo {
+	return TopicInfo{
+		Id:           topic.Id,
+		CapabilityId: string(topic.CapabilityId),
+		ClusterId:    string(topic.ClusterId),
+		Name:         topic.Name,
+		Partitions:   topic.Partitions,
+		Retention:    topic.Retention,
+		CreatedAt:    topic.CreatedAt,
+	}
+}
+
 type TopicDescription struct {
 	Name       string
 	Partitions int
@@ -0,0 +1,149 @@
+package plan
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/dfds/confluent-gateway/internal/confluent"
+	"github.com/dfds/confluent-gateway/internal/models"
+)
+
+// PlannedServiceAccountId, PlannedRoleBindingId, PlannedSchemaVersion and PlannedApiKey stand in for the resources
+// Confluent would have created.
+const PlannedServiceAccountId = models.ServiceAccountId("sa-planned")
+const PlannedRoleBindingId = "rb-planned"
+const PlannedSchemaVersion = int32(1)
+
+var PlannedApiKey = models.ApiKey{Username: "planned-api-key", Password: "planned-api-secret"}
+
+// RecordingClient passes reads on to Confluent and records writes instead of making them.
+type RecordingClient struct {
+	confluent.ConfluentClient
+	recorder *Recorder
+}
+
+func NewRecordingClient(client confluent.ConfluentClient, recorder *Recorder) *RecordingClient {
+	return &RecordingClient{ConfluentClient: client, recorder: recorder}
+}
+
+func (c *RecordingClient) record(operation string, arguments map[string]any) {
+	c.recorder.Record(models.PlanActionConfluent, operation, arguments)
+}
+
+func (c *RecordingClient) SetCompatibilityLevel(_ context.Context, clusterId models.ClusterId, subject string, level models.CompatibilityLevel) error {
+	c.record("SetCompatibilityLevel", map[string]any{"clusterId": clusterId, "subject": subject, "compatibilityLevel": level})
+	return nil
+}
+
+func (c *RecordingClient) CreateServiceAccount(_ context.Context, name string, description string) (models.ServiceAccountId, error) {
+	c.record("CreateServiceAccount", map[string]any{"name": name, "description": description})
+	return PlannedServiceAccountId, nil
+}
+
+func (c *RecordingClient) CreateACLEntry(_ context.Context, clusterId models.ClusterId, userAccountId models.UserAccountId, entry models.AclDefinition) error {
+	c.record("CreateACLEntry", map[string]any{"clusterId": clusterId, "userAccountId": userAccountId, "entry": entry})
+	return nil
+}
+
+func (c *RecordingClient) CreateACLEntries(_ context.Context, clusterId models.ClusterId, userAccountId models.UserAccountId, entries []models.AclDefinition) ([]models.AclCreationResult, error) {
+	results := make([]models.AclCreationResult, len(entries))
+	for i, entry := range entries {
+		c.record("CreateACLEntry", map[string]any{"clusterId": clusterId, "userAccountId": userAccountId, "entry": entry})
+		results[i] = models.AclCreationResult{AclDefinition: entry}
+	}
+	return results, nil
+}
+
+func (c *RecordingClient) DeleteACLEntry(_ context.Context, clusterId models.ClusterId, userAccountId models.UserAccountId, entry models.AclDefinition) error {
+	c.record("DeleteACLEntry", map[string]any{"clusterId": clusterId, "userAccountId": userAccountId, "entry": entry})
+	return nil
+}
+
+func (c *RecordingClient) DeleteACLs(_ context.Context, clusterId models.ClusterId, filter models.AclFilter) ([]models.AclBinding, error) {
+	c.record("DeleteACLs", map[string]any{"clusterId": clusterId, "filter": filter})
+	return []models.AclBinding{}, nil
+}
+
+func (c *RecordingClient) CreateClusterApiKey(_ context.Context, clusterId models.ClusterId, serviceAccountId models.ServiceAccountId) (models.ApiKey, error) {
+	c.record("CreateClusterApiKey", map[string]any{"clusterId": clusterId, "serviceAccountId": serviceAccountId})
+	return PlannedApiKey, nil
+}
+
+func (c *RecordingClient) CreateSchemaRegistryApiKey(_ context.Context, clusterId models.ClusterId, serviceAccountId models.ServiceAccountId) (models.ApiKey, error) {
+	c.record("CreateSchemaRegistryApiKey", map[string]any{"clusterId": clusterId, "serviceAccountId": serviceAccountId})
+	return PlannedApiKey, nil
+}
+
+func (c *RecordingClient) DeleteClusterApiKey(_ context.Context, clusterId models.ClusterId, serviceAccountId models.ServiceAccountId) error {
+	c.record("DeleteClusterApiKey", map[string]any{"clusterId": clusterId, "serviceAccountId": serviceAccountId})
+	return nil
+}
+
+func (c *RecordingClient) DeleteSchemaRegistryApiKey(_ context.Context, clusterId models.ClusterId, serviceAccountId models.ServiceAccountId) error {
+	c.record("DeleteSchemaRegistryApiKey", map[string]any{"clusterId": clusterId, "serviceAccountId": serviceAccountId})
+	return nil
+}
+
+func (c *RecordingClient) CreateRoleBinding(_ context.Context, serviceAccount models.ServiceAccountId, definition models.RoleBindingDefinition) (string, error) {
+	c.record("CreateRoleBinding", map[string]any{"serviceAccountId": serviceAccount, "roleName": definition.RoleName, "crnPattern": definition.CrnPattern})
+	return PlannedRoleBindingId, nil
+}
+
+func (c *RecordingClient) EnsureRoleBinding(_ context.Context, serviceAccount models.ServiceAccountId, definition models.RoleBindingDefinition) (string, error) {
+	c.record("EnsureRoleBinding", map[string]any{"serviceAccountId": serviceAccount, "roleName": definition.RoleName, "crnPattern": definition.CrnPattern})
+	return PlannedRoleBindingId, nil
+}
+
+func (c *RecordingClient) DeleteRoleBinding(_ context.Context, roleBindingId string) error {
+	c.record("DeleteRoleBinding", map[string]any{"roleBindingId": roleBindingId})
+	return nil
+}
+
+func (c *RecordingClient) RevokeRoleBinding(_ context.Context, serviceAccount models.ServiceAccountId, definition models.RoleBindingDefinition) error {
+	c.record("RevokeRoleBinding", map[string]any{"serviceAccountId": serviceAccount, "roleName": definition.RoleName, "crnPattern": definition.CrnPattern})
+	return nil
+}
+
+func (c *RecordingClient) CreateTopic(_ context.Context, clusterId models.ClusterId, name string, partitions int, retention int64) error {
+	c.record("CreateTopic", map[string]any{"clusterId": clusterId, "name": name, "partitions": partitions, "retention": retention})
+	return nil
+}
+
+func (c *RecordingClient) DeleteTopic(_ context.Context, clusterId models.ClusterId, topicName string) error {
+	c.record("DeleteTopic", map[string]any{"clusterId": clusterId, "name": topicName})
+	return nil
+}
+
+func (c *RecordingClient) RegisterSchema(_ context.Context, clusterId models.ClusterId, subject string, schema models.SchemaDefinition, version int32) error {
+	c.record("RegisterSchema", map[string]any{"clusterId": clusterId, "subject": subject, "schemaType": schema.SchemaType, "version": version})
+	return nil
+}
+
+// LookupSchemaVersion passes the lookup on to Confluent. A schema whose registration was only planned is not found
+// there, and gets PlannedSchemaVersion instead.
+func (c *RecordingClient) LookupSchemaVersion(ctx context.Context, clusterId models.ClusterId, subject string, schema models.SchemaDefinition) (int32, error) {
+	version, err := c.ConfluentClient.LookupSchemaVersion(ctx, clusterId, subject, schema)
+
+	var clientError *confluent.ClientError
+	if errors.As(err, &clientError) && clientError.Status == http.StatusNotFound {
+		return PlannedSchemaVersion, nil
+	}
+
+	return version, err
+}
+
+func (c *RecordingClient) DeleteSchema(_ context.Context, clusterId models.ClusterId, subject string, _ string, version string) error {
+	c.record("DeleteSchema", map[string]any{"clusterId": clusterId, "subject": subject, "version": version})
+	return nil
+}
+
+func (c *RecordingClient) DeleteSubject(_ context.Context, clusterId models.ClusterId, subject string, permanent bool) error {
+	c.record("DeleteSubject", map[string]any{"clusterId": clusterId, "subject": subject, "permanent": permanent})
+	return nil
+}
+
+func (c *RecordingClient) DeleteSchemaVersion(_ context.Context, clusterId models.ClusterId, subject string, version string, permanent bool) error {
+	c.record("DeleteSchemaVersion", map[string]any{"clusterId": clusterId, "subject": subject, "version": version, "permanent": permanent})
+	return nil
+}
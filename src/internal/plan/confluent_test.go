@@ -0,0 +1,43 @@
+package plan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dfds/confluent-gateway/internal/mocks"
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordingClient_RecordsWrites(t *testing.T) {
+	recorder := NewRecorder()
+	client := &mocks.MockClient{}
+	sut := NewRecordingClient(client, recorder)
+
+	err := sut.CreateTopic(context.TODO(), "some-cluster-id", "some-topic", 3, 604800000)
+	assert.NoError(t, err)
+
+	serviceAccountId, err := sut.CreateServiceAccount(context.TODO(), "some-name", "some-description")
+	assert.NoError(t, err)
+	assert.Equal(t, PlannedServiceAccountId, serviceAccountId)
+
+	assert.Equal(t, []models.PlanAction{
+		{Kind: models.PlanActionConfluent, Operation: "CreateTopic", Arguments: map[string]any{"clusterId": models.ClusterId("some-cluster-id"), "name": "some-topic", "partitions": 3, "retention": int64(604800000)}},
+		{Kind: models.PlanActionConfluent, Operation: "CreateServiceAccount", Arguments: map[string]any{"name": "some-name", "description": "some-description"}},
+	}, recorder.Actions())
+	client.AssertExpectations(t)
+}
+
+func TestRecordingClient_DelegatesReads(t *testing.T) {
+	recorder := NewRecorder()
+	client := &mocks.MockClient{}
+	client.On("ListSubjects", context.TODO(), models.ClusterId("some-cluster-id"), "some-prefix").Return([]string{"some-subject"}, nil)
+	sut := NewRecordingClient(client, recorder)
+
+	subjects, err := sut.ListSubjects(context.TODO(), "some-cluster-id", "some-prefix")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"some-subject"}, subjects)
+	assert.Empty(t, recorder.Actions())
+	client.AssertExpectations(t)
+}
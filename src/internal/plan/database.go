@@ -0,0 +1,162 @@
+package plan
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/dfds/confluent-gateway/messaging"
+)
+
+// Rehearser runs a function against a database whose changes are discarded afterwards.
+type Rehearser interface {
+	Rehearse(ctx context.Context, f func(models.Database) error) error
+}
+
+// RecordingDatabase records the changes made in its transactions. It does not prevent them, so it is meant to wrap
+// a database handed out by a Rehearser.
+type RecordingDatabase struct {
+	database models.Database
+	recorder *Recorder
+}
+
+func NewRecordingDatabase(database models.Database, recorder *Recorder) *RecordingDatabase {
+	return &RecordingDatabase{database: database, recorder: recorder}
+}
+
+func (d *RecordingDatabase) NewSession(ctx context.Context) models.Session {
+	return &recordingSession{session: d.database.NewSession(ctx), recorder: d.recorder}
+}
+
+type recordingSession struct {
+	session  models.Session
+	recorder *Recorder
+}
+
+func (s *recordingSession) Transaction(f func(models.Transaction) error) error {
+	return s.session.Transaction(func(tx models.Transaction) error {
+		return f(&recordingTransaction{Transaction: tx, recorder: s.recorder})
+	})
+}
+
+// recordingTransaction passes every call on to the transaction it wraps and records the ones that change data.
+type recordingTransaction struct {
+	models.Transaction
+	recorder *Recorder
+}
+
+func (t *recordingTransaction) record(operation string, record any) {
+	t.recorder.Record(models.PlanActionDatabase, operation, map[string]any{"record": snapshot(record)})
+}
+
+// snapshot captures a record as it is now, as the processes keep changing their state after saving it.
+func snapshot(record any) json.RawMessage {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+func (t *recordingTransaction) CreateServiceAccount(serviceAccount *models.ServiceAccount) error {
+	t.record("CreateServiceAccount", serviceAccount)
+	return t.Transaction.CreateServiceAccount(serviceAccount)
+}
+
+func (t *recordingTransaction) UpdateAclEntry(aclEntry *models.AclEntry) error {
+	t.record("UpdateAclEntry", aclEntry)
+	return t.Transaction.UpdateAclEntry(aclEntry)
+}
+
+func (t *recordingTransaction) CreateAclEntry(aclEntry *models.AclEntry) error {
+	t.record("CreateAclEntry", aclEntry)
+	return t.Transaction.CreateAclEntry(aclEntry)
+}
+
+func (t *recordingTransaction) DeleteAclEntry(aclEntry *models.AclEntry) error {
+	t.record("DeleteAclEntry", aclEntry)
+	return t.Transaction.DeleteAclEntry(aclEntry)
+}
+
+func (t *recordingTransaction) CreateRoleBinding(roleBinding *models.RoleBinding) error {
+	t.record("CreateRoleBinding", roleBinding)
+	return t.Transaction.CreateRoleBinding(roleBinding)
+}
+
+func (t *recordingTransaction) UpdateRoleBinding(roleBinding *models.RoleBinding) error {
+	t.record("UpdateRoleBinding", roleBinding)
+	return t.Transaction.UpdateRoleBinding(roleBinding)
+}
+
+func (t *recordingTransaction) DeleteRoleBinding(roleBinding *models.RoleBinding) error {
+	t.record("DeleteRoleBinding", roleBinding)
+	return t.Transaction.DeleteRoleBinding(roleBinding)
+}
+
+func (t *recordingTransaction) CreateClusterAccess(clusterAccess *models.ClusterAccess) error {
+	t.record("CreateClusterAccess", clusterAccess)
+	return t.Transaction.CreateClusterAccess(clusterAccess)
+}
+
+func (t *recordingTransaction) UpdateClusterAccess(clusterAccess *models.ClusterAccess) error {
+	t.record("UpdateClusterAccess", clusterAccess)
+	return t.Transaction.UpdateClusterAccess(clusterAccess)
+}
+
+func (t *recordingTransaction) SaveCreateProcessState(state *models.CreateProcess) error {
+	t.record("SaveCreateProcessState", state)
+	return t.Transaction.SaveCreateProcessState(state)
+}
+
+func (t *recordingTransaction) UpdateCreateProcessState(state *models.CreateProcess) error {
+	t.record("UpdateCreateProcessState", state)
+	return t.Transaction.UpdateCreateProcessState(state)
+}
+
+func (t *recordingTransaction) SaveDeleteProcessState(state *models.DeleteProcess) error {
+	t.record("SaveDeleteProcessState", state)
+	return t.Transaction.SaveDeleteProcessState(state)
+}
+
+func (t *recordingTransaction) UpdateDeleteProcessState(state *models.DeleteProcess) error {
+	t.record("UpdateDeleteProcessState", state)
+	return t.Transaction.UpdateDeleteProcessState(state)
+}
+
+func (t *recordingTransaction) CreateTopic(topic *models.Topic) error {
+	t.record("CreateTopic", topic)
+	return t.Transaction.CreateTopic(topic)
+}
+
+func (t *recordingTransaction) DeleteTopic(topicId string) error {
+	t.record("DeleteTopic", map[string]string{"id": topicId})
+	return t.Transaction.DeleteTopic(topicId)
+}
+
+func (t *recordingTransaction) SaveSchemaProcessState(state *models.SchemaProcess) error {
+	t.record("SaveSchemaProcessState", state)
+	return t.Transaction.SaveSchemaProcessState(state)
+}
+
+func (t *recordingTransaction) UpdateSchemaProcessState(state *models.SchemaProcess) error {
+	t.record("UpdateSchemaProcessState", state)
+	return t.Transaction.UpdateSchemaProcessState(state)
+}
+
+func (t *recordingTransaction) DeleteSchemaProcessStateById(id string) error {
+	t.record("DeleteSchemaProcessStateById", map[string]string{"id": id})
+	return t.Transaction.DeleteSchemaProcessStateById(id)
+}
+
+// AddToOutbox records the event rather than the outbox entry, which is what consumers of the gateway will see.
+func (t *recordingTransaction) AddToOutbox(entry *messaging.OutboxEntry) error {
+	var payload struct {
+		Type string          `json:"type"`
+		Data json.RawMessage `json:"data"`
+	}
+	_ = json.Unmarshal([]byte(entry.Payload), &payload)
+
+	t.recorder.Record(models.PlanActionEvent, payload.Type, map[string]any{"topic": entry.Topic, "key": entry.Key, "data": payload.Data})
+
+	return t.Transaction.AddToOutbox(entry)
+}
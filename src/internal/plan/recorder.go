@@ -0,0 +1,31 @@
+package plan
+
+import (
+	"sync"
+
+	"github.com/dfds/confluent-gateway/internal/models"
+)
+
+// Recorder collects the actions made while planning.
+type Recorder struct {
+	mu      sync.Mutex
+	actions []models.PlanAction
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{actions: []models.PlanAction{}}
+}
+
+func (r *Recorder) Record(kind models.PlanActionKind, operation string, arguments map[string]any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.actions = append(r.actions, models.PlanAction{Kind: kind, Operation: operation, Arguments: arguments})
+}
+
+func (r *Recorder) Actions() []models.PlanAction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]models.PlanAction{}, r.actions...)
+}
@@ -0,0 +1,42 @@
+package plan
+
+import (
+	"context"
+
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/dfds/confluent-gateway/internal/vault"
+)
+
+// RecordingVault passes queries on to the vault and records changes instead of making them. Secrets are left out
+// of the recorded actions.
+type RecordingVault struct {
+	vault.Vault
+	recorder *Recorder
+}
+
+func NewRecordingVault(vault vault.Vault, recorder *Recorder) *RecordingVault {
+	return &RecordingVault{Vault: vault, recorder: recorder}
+}
+
+func (v *RecordingVault) StoreApiKey(_ context.Context, input vault.Input) error {
+	arguments := vaultArguments(input)
+	if input.StoringInput != nil {
+		arguments["overwrite"] = input.StoringInput.Overwrite
+	}
+
+	v.recorder.Record(models.PlanActionVault, "StoreApiKey", arguments)
+	return nil
+}
+
+func (v *RecordingVault) DeleteApiKey(_ context.Context, input vault.Input) error {
+	v.recorder.Record(models.PlanActionVault, "DeleteApiKey", vaultArguments(input))
+	return nil
+}
+
+func vaultArguments(input vault.Input) map[string]any {
+	return map[string]any{
+		"destination":  input.OperationDestination,
+		"capabilityId": input.CapabilityId,
+		"clusterId":    input.ClusterId,
+	}
+}
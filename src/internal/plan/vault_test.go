@@ -0,0 +1,34 @@
+package plan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/dfds/confluent-gateway/internal/vault"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordingVault_StoreApiKeyLeavesOutSecret(t *testing.T) {
+	recorder := NewRecorder()
+	sut := NewRecordingVault(nil, recorder)
+
+	err := sut.StoreApiKey(context.TODO(), vault.Input{
+		OperationDestination: vault.OperationDestinationCluster,
+		CapabilityId:         "some-capability-id",
+		ClusterId:            "some-cluster-id",
+		StoringInput:         &vault.StoringInput{ApiKey: models.ApiKey{Username: "some-key", Password: "some-secret"}, Overwrite: true},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.PlanAction{{
+		Kind:      models.PlanActionVault,
+		Operation: "StoreApiKey",
+		Arguments: map[string]any{
+			"destination":  vault.OperationDestinationCluster,
+			"capabilityId": models.CapabilityId("some-capability-id"),
+			"clusterId":    models.ClusterId("some-cluster-id"),
+			"overwrite":    true,
+		},
+	}}, recorder.Actions())
+}
@@ -0,0 +1,53 @@
+package process
+
+import "time"
+
+// Backoff tells how long to wait before the next attempt after the given number of failed attempts.
+type Backoff func(failures int) time.Duration
+
+// NoBackoff retries right away.
+func NoBackoff(int) time.Duration {
+	return 0
+}
+
+// ConstantBackoff waits the same amount of time between every attempt.
+func ConstantBackoff(delay time.Duration) Backoff {
+	return func(int) time.Duration {
+		return delay
+	}
+}
+
+// ExponentialBackoff doubles the wait after every failed attempt, starting at initial and never waiting longer
+// than max.
+func ExponentialBackoff(initial time.Duration, max time.Duration) Backoff {
+	return func(failures int) time.Duration {
+		delay := initial
+		for i := 1; i < failures && delay < max; i++ {
+			delay *= 2
+		}
+		return min(delay, max)
+	}
+}
+
+type stepConfig struct {
+	name     string
+	attempts int
+	backoff  Backoff
+}
+
+type StepOption func(*stepConfig)
+
+// WithName names the step in its records instead of naming it after the function implementing it.
+func WithName(name string) StepOption {
+	return func(config *stepConfig) {
+		config.name = name
+	}
+}
+
+// WithRetry makes up to the given number of attempts at the step, waiting between them as told by backoff.
+func WithRetry(attempts int, backoff Backoff) StepOption {
+	return func(config *stepConfig) {
+		config.attempts = max(attempts, 1)
+		config.backoff = backoff
+	}
+}
@@ -0,0 +1,69 @@
+package process
+
+import "github.com/dfds/confluent-gateway/internal/models"
+
+// StepRecorder keeps track of the progress of the steps of a process.
+type StepRecorder interface {
+	Load() ([]*models.ProcessStep, error)
+	New(name string) *models.ProcessStep
+	Save(*models.ProcessStep) error
+}
+
+type stepRecords map[string]*models.ProcessStep
+
+func (r stepRecords) get(recorder StepRecorder, name string) *models.ProcessStep {
+	record, ok := r[name]
+	if !ok {
+		record = recorder.New(name)
+		r[name] = record
+	}
+	return record
+}
+
+type databaseRecorder struct {
+	session   models.Session
+	process   string
+	processId string
+}
+
+// NewStepRecorder stores the steps of a process in the database. Every change is saved in a transaction of its
+// own, so the failures of a step are kept even though the transaction of the step is rolled back.
+func NewStepRecorder(session models.Session, process string, processId string) StepRecorder {
+	return &databaseRecorder{session: session, process: process, processId: processId}
+}
+
+func (r *databaseRecorder) Load() ([]*models.ProcessStep, error) {
+	var steps []*models.ProcessStep
+
+	err := r.session.Transaction(func(tx models.Transaction) error {
+		var err error
+		steps, err = tx.GetProcessSteps(r.process, r.processId)
+		return err
+	})
+
+	return steps, err
+}
+
+func (r *databaseRecorder) New(name string) *models.ProcessStep {
+	return models.NewProcessStep(r.process, r.processId, name)
+}
+
+func (r *databaseRecorder) Save(step *models.ProcessStep) error {
+	return r.session.Transaction(func(tx models.Transaction) error {
+		return tx.SaveProcessStep(step)
+	})
+}
+
+type nopRecorder struct{}
+
+func (nopRecorder) Load() ([]*models.ProcessStep, error) {
+	return nil, nil
+}
+
+func (nopRecorder) New(name string) *models.ProcessStep {
+	return models.NewProcessStep("", "", name)
+}
+
+func (nopRecorder) Save(*models.ProcessStep) error {
+	return nil
+}
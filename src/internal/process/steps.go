@@ -1,65 +1,179 @@
 package process
 
+import (
+	"errors"
+	"reflect"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/dfds/confluent-gateway/internal/models"
+)
+
 type StepBuilder[Context any] interface {
-	Step(func(Context) error) NextStepBuilder[Context]
+	Step(func(Context) error, ...StepOption) NextStepBuilder[Context]
 }
 
 type NextStepBuilder[Context any] interface {
 	StepBuilder[Context]
 	Until(func(Context) bool) StepBuilder[Context]
+	Compensate(func(Context) error) NextStepBuilder[Context]
+	RecordTo(StepRecorder) NextStepBuilder[Context]
 	Run(func(func(Context) error) error) error
 }
 
+type step[Context any] struct {
+	name       string
+	run        func(Context) (bool, error)
+	attempts   int
+	backoff    Backoff
+	compensate func(Context) error
+}
+
 type Steps[Context any] struct {
-	steps []func(Context) (bool, error)
+	steps    []*step[Context]
+	recorder StepRecorder
+	sleep    func(time.Duration)
 }
 
 func PrepareSteps[Context any]() StepBuilder[Context] {
-	return &Steps[Context]{steps: []func(Context) (bool, error){}}
+	return &Steps[Context]{steps: []*step[Context]{}, recorder: nopRecorder{}, sleep: time.Sleep}
 }
 
-func (s *Steps[Context]) Step(step func(Context) error) NextStepBuilder[Context] {
-	s.steps = append(s.steps, func(context Context) (bool, error) {
-		err := step(context)
-		return true, err
+func (s *Steps[Context]) Step(run func(Context) error, options ...StepOption) NextStepBuilder[Context] {
+	config := stepConfig{name: stepName(run), attempts: 1, backoff: NoBackoff}
+	for _, option := range options {
+		option(&config)
+	}
+
+	s.steps = append(s.steps, &step[Context]{
+		name:     config.name,
+		attempts: config.attempts,
+		backoff:  config.backoff,
+		run: func(context Context) (bool, error) {
+			err := run(context)
+			return true, err
+		},
 	})
 	return s
 }
 
 func (s *Steps[Context]) Until(isDone func(Context) bool) StepBuilder[Context] {
 	lastStep := s.steps[len(s.steps)-1]
+	run := lastStep.run
 
-	s.steps[len(s.steps)-1] = func(context Context) (bool, error) {
+	lastStep.run = func(context Context) (bool, error) {
 		if isDone(context) {
 			return true, nil
 		}
 
-		_, err := lastStep(context)
+		_, err := run(context)
 		return false, err
 	}
 
 	return s
 }
 
+// Compensate sets how to roll back the side effects of the last step, should a later step fail.
+func (s *Steps[Context]) Compensate(compensate func(Context) error) NextStepBuilder[Context] {
+	s.steps[len(s.steps)-1].compensate = compensate
+	return s
+}
+
+// RecordTo keeps track of the progress of the steps in the recorder.
+func (s *Steps[Context]) RecordTo(recorder StepRecorder) NextStepBuilder[Context] {
+	s.recorder = recorder
+	return s
+}
+
+// Run performs the steps in order and stops at the first step that still fails after all its attempts. The
+// steps completed before it are then compensated in reverse order.
 func (s *Steps[Context]) Run(perform func(func(Context) error) error) error {
-	for _, step := range s.steps {
-		for {
-			done := true
+	loaded, err := s.recorder.Load()
+	if err != nil {
+		return err
+	}
 
-			err := perform(func(context Context) error {
-				var err error
-				done, err = step(context)
-				return err
-			})
+	records := stepRecords{}
+	for _, record := range loaded {
+		records[record.Name] = record
+	}
 
-			if err != nil {
-				return err
-			}
-			if done {
-				break
+	for i, step := range s.steps {
+		record := records.get(s.recorder, step.name)
+
+		if err := s.runStep(perform, step, record); err != nil {
+			if compensateErr := s.compensate(perform, s.steps[:i], records); compensateErr != nil {
+				return errors.Join(err, compensateErr)
 			}
+			return err
+		}
+
+		record.MarkAsCompleted()
+		if err := s.recorder.Save(record); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
+
+func (s *Steps[Context]) runStep(perform func(func(Context) error) error, step *step[Context], record *models.ProcessStep) error {
+	failures := 0
+
+	for {
+		done := true
+
+		err := perform(func(context Context) error {
+			var err error
+			done, err = step.run(context)
+			return err
+		})
+
+		record.MarkAttempt(err)
+		if saveErr := s.recorder.Save(record); saveErr != nil {
+			return errors.Join(err, saveErr)
+		}
+
+		if err != nil {
+			failures++
+			if failures >= step.attempts {
+				return err
+			}
+
+			s.sleep(step.backoff(failures))
+			continue
+		}
+
+		failures = 0
+		if done {
+			return nil
+		}
+	}
+}
+
+func (s *Steps[Context]) compensate(perform func(func(Context) error) error, completed []*step[Context], records stepRecords) error {
+	var errs []error
+
+	for i := len(completed) - 1; i >= 0; i-- {
+		step := completed[i]
+		if step.compensate == nil {
+			continue
+		}
+
+		err := perform(step.compensate)
+
+		record := records.get(s.recorder, step.name)
+		record.MarkAsCompensated(err)
+		errs = append(errs, err, s.recorder.Save(record))
+	}
+
+	return errors.Join(errs...)
+}
+
+// stepName names a step after the function that implements it.
+func stepName(run any) string {
+	name := runtime.FuncForPC(reflect.ValueOf(run).Pointer()).Name()
+	name = name[strings.LastIndex(name, "/")+1:]
+	return strings.TrimSuffix(name[strings.Index(name, ".")+1:], "-fm")
+}
@@ -1,8 +1,11 @@
 package process
 
 import (
+	"errors"
+	"github.com/dfds/confluent-gateway/internal/models"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 type Context struct {
@@ -41,6 +44,124 @@ func TestSteps_Until(t *testing.T) {
 	assert.Equal(t, 7, c.process.calls)
 }
 
+func TestSteps_Retry(t *testing.T) {
+	c := NewCollector()
+	someError := errors.New("some-error")
+	failures := 0
+	var waits []time.Duration
+
+	steps := PrepareSteps[*Context]().
+		Step(c.DummyStep()).
+		Step(func(*Context) error {
+			if failures < 2 {
+				failures++
+				return someError
+			}
+			return nil
+		}, WithName("flaky"), WithRetry(3, ExponentialBackoff(time.Second, time.Minute))).
+		Step(c.DummyStep())
+	steps.(*Steps[*Context]).sleep = func(d time.Duration) { waits = append(waits, d) }
+
+	err := steps.Run(c.Execute)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, c.steps)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, waits)
+}
+
+func TestSteps_RetryGivesUp(t *testing.T) {
+	c := NewCollector()
+	someError := errors.New("some-error")
+	attempts := 0
+
+	steps := PrepareSteps[*Context]().
+		Step(func(*Context) error {
+			attempts++
+			return someError
+		}, WithRetry(2, NoBackoff)).
+		Step(c.DummyStep())
+
+	err := steps.Run(c.Execute)
+
+	assert.ErrorIs(t, err, someError)
+	assert.Equal(t, 2, attempts)
+	assert.Empty(t, c.steps)
+}
+
+func TestSteps_Compensate(t *testing.T) {
+	c := NewCollector()
+	someError := errors.New("some-error")
+	var compensated []string
+
+	err := PrepareSteps[*Context]().
+		Step(c.DummyStep()).Compensate(func(*Context) error {
+		compensated = append(compensated, "first")
+		return nil
+	}).
+		Step(c.DummyStep()).
+		Step(c.DummyStep()).Compensate(func(*Context) error {
+		compensated = append(compensated, "third")
+		return nil
+	}).
+		Step(func(*Context) error { return someError }).Compensate(func(*Context) error {
+		compensated = append(compensated, "failed")
+		return nil
+	}).
+		Run(c.Execute)
+
+	assert.Equal(t, someError, err)
+	assert.Equal(t, []string{"third", "first"}, compensated)
+}
+
+func TestSteps_RecordTo(t *testing.T) {
+	c := NewCollector()
+	someError := errors.New("some-error")
+	recorder := &recorderStub{Loaded: []*models.ProcessStep{{Name: "first", Attempts: 2, LastError: "earlier-error"}}}
+
+	err := PrepareSteps[*Context]().
+		Step(c.DummyStep(), WithName("first")).
+		Step(func(*Context) error { return someError }, WithName("second"), WithRetry(2, NoBackoff)).
+		RecordTo(recorder).
+		Run(c.Execute)
+
+	assert.Equal(t, someError, err)
+	first, second := recorder.Saved["first"], recorder.Saved["second"]
+	assert.Equal(t, 3, first.Attempts)
+	assert.True(t, first.IsCompleted())
+	assert.Equal(t, 2, second.Attempts)
+	assert.Equal(t, "some-error", second.LastError)
+	assert.False(t, second.IsCompleted())
+}
+
+func TestStepName(t *testing.T) {
+	assert.Equal(t, "namedStep", stepName(namedStep))
+}
+
+func namedStep(*Context) error {
+	return nil
+}
+
+type recorderStub struct {
+	Loaded []*models.ProcessStep
+	Saved  map[string]*models.ProcessStep
+}
+
+func (r *recorderStub) Load() ([]*models.ProcessStep, error) {
+	return r.Loaded, nil
+}
+
+func (r *recorderStub) New(name string) *models.ProcessStep {
+	return models.NewProcessStep("some-process", "some-process-id", name)
+}
+
+func (r *recorderStub) Save(step *models.ProcessStep) error {
+	if r.Saved == nil {
+		r.Saved = map[string]*models.ProcessStep{}
+	}
+	r.Saved[step.Name] = step
+	return nil
+}
+
 type Collector struct {
 	steps   []int
 	cnt     int
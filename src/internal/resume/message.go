@@ -0,0 +1,13 @@
+package resume
+
+type ProcessFailed struct {
+	Process   string `json:"process"`
+	ProcessId string `json:"processId"`
+	TopicId   string `json:"topicId"`
+	Attempts  int    `json:"attempts"`
+	Reason    string `json:"reason"`
+}
+
+func (t *ProcessFailed) PartitionKey() string {
+	return t.TopicId
+}
@@ -0,0 +1,110 @@
+package resume
+
+import (
+	"context"
+	"time"
+
+	"github.com/dfds/confluent-gateway/internal/create"
+	del "github.com/dfds/confluent-gateway/internal/delete"
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/dfds/confluent-gateway/internal/schema"
+	"github.com/dfds/confluent-gateway/messaging"
+)
+
+// stuckProcess is an incomplete process of any kind, named the same way as in its step records.
+type stuckProcess interface {
+	kind() string
+	id() string
+	attempts() int
+	resumedAt() *time.Time
+	countAttempt()
+	markAsFailed()
+	update(models.Transaction) error
+	resume(context.Context) error
+	failed(reason string) messaging.OutgoingMessage
+}
+
+// region Create
+
+type stuckCreateProcess struct {
+	process create.ResumableProcess
+	state   *models.CreateProcess
+}
+
+func (p *stuckCreateProcess) kind() string          { return "create-topic" }
+func (p *stuckCreateProcess) id() string            { return p.state.Id.String() }
+func (p *stuckCreateProcess) attempts() int         { return p.state.ResumeAttempts }
+func (p *stuckCreateProcess) resumedAt() *time.Time { return p.state.ResumedAt }
+func (p *stuckCreateProcess) countAttempt()         { p.state.CountResumeAttempt() }
+func (p *stuckCreateProcess) markAsFailed()         { p.state.MarkAsFailed() }
+
+func (p *stuckCreateProcess) update(tx models.Transaction) error {
+	return tx.UpdateCreateProcessState(p.state)
+}
+
+func (p *stuckCreateProcess) resume(ctx context.Context) error {
+	return p.process.Resume(ctx, p.state.Id)
+}
+
+func (p *stuckCreateProcess) failed(reason string) messaging.OutgoingMessage {
+	return &ProcessFailed{Process: p.kind(), ProcessId: p.id(), TopicId: p.state.TopicId, Attempts: p.attempts(), Reason: reason}
+}
+
+// endregion
+
+// region Delete
+
+type stuckDeleteProcess struct {
+	process del.ResumableProcess
+	state   *models.DeleteProcess
+}
+
+func (p *stuckDeleteProcess) kind() string          { return "delete-topic" }
+func (p *stuckDeleteProcess) id() string            { return p.state.Id.String() }
+func (p *stuckDeleteProcess) attempts() int         { return p.state.ResumeAttempts }
+func (p *stuckDeleteProcess) resumedAt() *time.Time { return p.state.ResumedAt }
+func (p *stuckDeleteProcess) countAttempt()         { p.state.CountResumeAttempt() }
+func (p *stuckDeleteProcess) markAsFailed()         { p.state.MarkAsFailed() }
+
+func (p *stuckDeleteProcess) update(tx models.Transaction) error {
+	return tx.UpdateDeleteProcessState(p.state)
+}
+
+func (p *stuckDeleteProcess) resume(ctx context.Context) error {
+	return p.process.Resume(ctx, p.state.Id)
+}
+
+func (p *stuckDeleteProcess) failed(reason string) messaging.OutgoingMessage {
+	return &ProcessFailed{Process: p.kind(), ProcessId: p.id(), TopicId: p.state.TopicId, Attempts: p.attempts(), Reason: reason}
+}
+
+// endregion
+
+// region Schema
+
+type stuckSchemaProcess struct {
+	process schema.ResumableProcess
+	state   *models.SchemaProcess
+}
+
+func (p *stuckSchemaProcess) kind() string          { return "schema" }
+func (p *stuckSchemaProcess) id() string            { return p.state.Id.String() }
+func (p *stuckSchemaProcess) attempts() int         { return p.state.ResumeAttempts }
+func (p *stuckSchemaProcess) resumedAt() *time.Time { return p.state.ResumedAt }
+func (p *stuckSchemaProcess) countAttempt()         { p.state.CountResumeAttempt() }
+func (p *stuckSchemaProcess) markAsFailed()         { p.state.MarkAsFailed() }
+
+func (p *stuckSchemaProcess) update(tx models.Transaction) error {
+	return tx.UpdateSchemaProcessState(p.state)
+}
+
+func (p *stuckSchemaProcess) resume(ctx context.Context) error {
+	return p.process.Resume(ctx, p.state.Id)
+}
+
+// failed reports a schema process that was given up on the same way as a schema that could not be registered.
+func (p *stuckSchemaProcess) failed(reason string) messaging.OutgoingMessage {
+	return &schema.SchemaRegistrationFailed{MessageContractId: p.state.MessageContractId, Reason: reason}
+}
+
+// endregion
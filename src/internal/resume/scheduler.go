@@ -0,0 +1,220 @@
+package resume
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dfds/confluent-gateway/internal/create"
+	del "github.com/dfds/confluent-gateway/internal/delete"
+	"github.com/dfds/confluent-gateway/internal/models"
+	proc "github.com/dfds/confluent-gateway/internal/process"
+	"github.com/dfds/confluent-gateway/internal/schema"
+	"github.com/dfds/confluent-gateway/logging"
+	"github.com/dfds/confluent-gateway/messaging"
+	"golang.org/x/sync/errgroup"
+)
+
+type Outbox interface {
+	Produce(msg messaging.OutgoingMessage) error
+}
+
+type OutboxRepository interface {
+	AddToOutbox(entry *messaging.OutboxEntry) error
+}
+
+type OutboxFactory func(repository OutboxRepository) Outbox
+
+// maxBackoff is the longest wait between two attempts to resume a process.
+const maxBackoff = 24 * time.Hour
+
+type Config struct {
+	// Interval is the time between looking for stuck processes.
+	Interval time.Duration
+	// Threshold is how long a process may run before it is considered stuck.
+	Threshold time.Duration
+	// MaxAttempts is the number of times a process is resumed before it is marked as failed.
+	MaxAttempts int
+	// Backoff is how long to wait after the first attempt to resume a process before the next one. The wait doubles
+	// after every further attempt, up to a day.
+	Backoff time.Duration
+	// Concurrency is the number of processes resumed at the same time.
+	Concurrency int
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Interval:    time.Minute,
+		Threshold:   10 * time.Minute,
+		MaxAttempts: 5,
+		Backoff:     5 * time.Minute,
+		Concurrency: 4,
+	}
+}
+
+// Scheduler resumes the processes that were left incomplete, which would otherwise only move forward when a
+// message about them arrives again.
+type Scheduler struct {
+	logger   logging.Logger
+	database models.Database
+	factory  OutboxFactory
+	config   Config
+	create   create.ResumableProcess
+	delete   del.ResumableProcess
+	schema   schema.ResumableProcess
+}
+
+func NewScheduler(logger logging.Logger, database models.Database, factory OutboxFactory, config Config, createProcess create.ResumableProcess, deleteProcess del.ResumableProcess, schemaProcess schema.ResumableProcess) *Scheduler {
+	return &Scheduler{
+		logger:   logger,
+		database: database,
+		factory:  factory,
+		config:   config,
+		create:   createProcess,
+		delete:   deleteProcess,
+		schema:   schemaProcess,
+	}
+}
+
+// Run resumes stuck processes every interval until the context is cancelled.
+func (s *Scheduler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := s.ResumeStuckProcesses(ctx); err != nil {
+				s.logger.Error(err, "Unable to resume stuck processes")
+			}
+		}
+	}
+}
+
+// ResumeStuckProcesses re-runs the processes that have been incomplete for longer than the threshold, backing off
+// between attempts, and marks the ones that have been resumed too many times as failed.
+func (s *Scheduler) ResumeStuckProcesses(ctx context.Context) error {
+	var resumable []stuckProcess
+
+	err := s.database.NewSession(ctx).Transaction(func(tx models.Transaction) error {
+		now := time.Now()
+
+		stuck, err := s.findStuckProcesses(tx, now.Add(-s.config.Threshold), now.Add(-s.config.Backoff))
+		if err != nil {
+			return err
+		}
+
+		outbox := s.factory(tx)
+
+		for _, process := range stuck {
+			if !s.isDue(process, now) {
+				continue
+			}
+
+			if process.attempts() >= s.config.MaxAttempts {
+				s.logger.Warning("Giving up on {Process} {ProcessId} after {Attempts} attempts", process.kind(), process.id(), fmt.Sprint(process.attempts()))
+
+				process.markAsFailed()
+				if err := process.update(tx); err != nil {
+					return err
+				}
+				if err := outbox.Produce(process.failed(lastError(tx, process))); err != nil {
+					return err
+				}
+				continue
+			}
+
+			process.countAttempt()
+			if err := process.update(tx); err != nil {
+				return err
+			}
+			resumable = append(resumable, process)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	g := errgroup.Group{}
+	g.SetLimit(max(s.config.Concurrency, 1))
+
+	for _, process := range resumable {
+		g.Go(func() error {
+			s.logger.Information("Resuming {Process} {ProcessId}", process.kind(), process.id())
+
+			if err := process.resume(ctx); err != nil {
+				s.logger.Error(err, "Resuming {Process} {ProcessId} failed", process.kind(), process.id())
+			}
+			return nil
+		})
+	}
+
+	return g.Wait()
+}
+
+// isDue tells whether the wait after the last attempt to resume the process is over.
+func (s *Scheduler) isDue(process stuckProcess, now time.Time) bool {
+	resumedAt := process.resumedAt()
+	if resumedAt == nil {
+		return true
+	}
+
+	backoff := proc.ExponentialBackoff(s.config.Backoff, maxBackoff)
+	return !now.Before(resumedAt.Add(backoff(process.attempts())))
+}
+
+func (s *Scheduler) findStuckProcesses(tx models.Transaction, startedBefore time.Time, resumedBefore time.Time) ([]stuckProcess, error) {
+	var stuck []stuckProcess
+
+	createStates, err := tx.SelectStuckCreateProcessStates(startedBefore, resumedBefore)
+	if err != nil {
+		return nil, err
+	}
+	for _, state := range createStates {
+		stuck = append(stuck, &stuckCreateProcess{process: s.create, state: state})
+	}
+
+	deleteStates, err := tx.SelectStuckDeleteProcessStates(startedBefore, resumedBefore)
+	if err != nil {
+		return nil, err
+	}
+	for _, state := range deleteStates {
+		stuck = append(stuck, &stuckDeleteProcess{process: s.delete, state: state})
+	}
+
+	schemaStates, err := tx.SelectStuckSchemaProcessStates(startedBefore, resumedBefore)
+	if err != nil {
+		return nil, err
+	}
+	for _, state := range schemaStates {
+		stuck = append(stuck, &stuckSchemaProcess{process: s.schema, state: state})
+	}
+
+	return stuck, nil
+}
+
+// lastError finds the error the process was last seen failing with in its step records.
+func lastError(tx models.Transaction, process stuckProcess) string {
+	reason := fmt.Sprintf("gave up on the process after %d attempts to resume it", process.attempts())
+
+	steps, err := tx.GetProcessSteps(process.kind(), process.id())
+	if err != nil {
+		return reason
+	}
+
+	var last *models.ProcessStep
+	for _, step := range steps {
+		if step.LastError != "" && !step.IsCompleted() && (last == nil || step.UpdatedAt.After(last.UpdatedAt)) {
+			last = step
+		}
+	}
+	if last == nil {
+		return reason
+	}
+
+	return fmt.Sprintf("%s, step %s failed with: %s", reason, last.Name, last.LastError)
+}
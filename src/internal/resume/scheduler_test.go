@@ -0,0 +1,184 @@
+package resume
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dfds/confluent-gateway/internal/create"
+	del "github.com/dfds/confluent-gateway/internal/delete"
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/dfds/confluent-gateway/internal/schema"
+	"github.com/dfds/confluent-gateway/logging"
+	"github.com/dfds/confluent-gateway/messaging"
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduler_ResumeStuckProcesses(t *testing.T) {
+	createState := models.NewCreateProcess("some-capability-id", "some-cluster-id", "some-topic-id", models.TopicDescription{Name: "some-topic", Partitions: 1})
+	deleteState := models.NewDeleteProcess("another-topic-id")
+	schemaState := &models.SchemaProcess{Id: uuid.NewV4(), MessageContractId: "some-message-contract-id", TopicId: "some-topic-id", ResumeAttempts: 1}
+
+	tx := &transactionStub{
+		CreateStates: []*models.CreateProcess{createState},
+		DeleteStates: []*models.DeleteProcess{deleteState},
+		SchemaStates: []*models.SchemaProcess{schemaState},
+	}
+	createProcess, deleteProcess, schemaProcess := &processSpy[create.ProcessInput]{}, &processSpy[del.ProcessInput]{}, &processSpy[schema.ProcessInput]{}
+	outbox := &outboxSpy{}
+	sut := NewScheduler(logging.NilLogger(), &databaseStub{tx: tx}, outbox.factory, DefaultConfig(), createProcess, deleteProcess, schemaProcess)
+
+	err := sut.ResumeStuckProcesses(context.TODO())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{createState.Id}, createProcess.Resumed)
+	assert.Equal(t, []uuid.UUID{deleteState.Id}, deleteProcess.Resumed)
+	assert.Equal(t, []uuid.UUID{schemaState.Id}, schemaProcess.Resumed)
+	assert.Equal(t, 1, createState.ResumeAttempts)
+	assert.Equal(t, 1, deleteState.ResumeAttempts)
+	assert.Equal(t, 2, schemaState.ResumeAttempts)
+	assert.Empty(t, outbox.Produced)
+}
+
+func TestScheduler_GivesUpAfterMaxAttempts(t *testing.T) {
+	createState := models.NewCreateProcess("some-capability-id", "some-cluster-id", "some-topic-id", models.TopicDescription{Name: "some-topic", Partitions: 1})
+	createState.ResumeAttempts = 5
+	schemaState := &models.SchemaProcess{Id: uuid.NewV4(), MessageContractId: "some-message-contract-id", ResumeAttempts: 5}
+
+	tx := &transactionStub{
+		CreateStates: []*models.CreateProcess{createState},
+		SchemaStates: []*models.SchemaProcess{schemaState},
+		Steps: []*models.ProcessStep{
+			{Name: "ensureHasValidServiceAccount", LastError: "old-error", UpdatedAt: time.Now().Add(-time.Hour), CompletedAt: &time.Time{}},
+			{Name: "ensureTopicIsCreated", LastError: "some-error", UpdatedAt: time.Now()},
+		},
+	}
+	createProcess, schemaProcess := &processSpy[create.ProcessInput]{}, &processSpy[schema.ProcessInput]{}
+	outbox := &outboxSpy{}
+	sut := NewScheduler(logging.NilLogger(), &databaseStub{tx: tx}, outbox.factory, DefaultConfig(), createProcess, &processSpy[del.ProcessInput]{}, schemaProcess)
+
+	err := sut.ResumeStuckProcesses(context.TODO())
+
+	assert.NoError(t, err)
+	assert.Empty(t, createProcess.Resumed)
+	assert.Empty(t, schemaProcess.Resumed)
+	assert.True(t, createState.IsFailed())
+	assert.True(t, schemaState.IsFailed())
+	assert.Equal(t, []messaging.OutgoingMessage{
+		&ProcessFailed{
+			Process:   "create-topic",
+			ProcessId: createState.Id.String(),
+			TopicId:   "some-topic-id",
+			Attempts:  5,
+			Reason:    "gave up on the process after 5 attempts to resume it, step ensureTopicIsCreated failed with: some-error",
+		},
+		&schema.SchemaRegistrationFailed{
+			MessageContractId: "some-message-contract-id",
+			Reason:            "gave up on the process after 5 attempts to resume it, step ensureTopicIsCreated failed with: some-error",
+		},
+	}, outbox.Produced)
+}
+
+func TestScheduler_BacksOffBetweenAttempts(t *testing.T) {
+	resumedAt := time.Now().Add(-6 * time.Minute)
+	createState := models.NewCreateProcess("some-capability-id", "some-cluster-id", "some-topic-id", models.TopicDescription{Name: "some-topic", Partitions: 1})
+	createState.ResumeAttempts = 1
+	createState.ResumedAt = &resumedAt
+	schemaState := &models.SchemaProcess{Id: uuid.NewV4(), MessageContractId: "some-message-contract-id", ResumeAttempts: 2, ResumedAt: &resumedAt}
+
+	tx := &transactionStub{
+		CreateStates: []*models.CreateProcess{createState},
+		SchemaStates: []*models.SchemaProcess{schemaState},
+	}
+	createProcess, schemaProcess := &processSpy[create.ProcessInput]{}, &processSpy[schema.ProcessInput]{}
+	sut := NewScheduler(logging.NilLogger(), &databaseStub{tx: tx}, (&outboxSpy{}).factory, DefaultConfig(), createProcess, &processSpy[del.ProcessInput]{}, schemaProcess)
+
+	err := sut.ResumeStuckProcesses(context.TODO())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{createState.Id}, createProcess.Resumed)
+	assert.Equal(t, 2, createState.ResumeAttempts)
+	assert.True(t, createState.ResumedAt.After(resumedAt))
+	assert.Empty(t, schemaProcess.Resumed)
+	assert.Equal(t, 2, schemaState.ResumeAttempts)
+}
+
+type databaseStub struct {
+	tx models.Transaction
+}
+
+func (d *databaseStub) NewSession(context.Context) models.Session {
+	return d
+}
+
+func (d *databaseStub) Transaction(f func(models.Transaction) error) error {
+	return f(d.tx)
+}
+
+type transactionStub struct {
+	models.Transaction
+	CreateStates []*models.CreateProcess
+	DeleteStates []*models.DeleteProcess
+	SchemaStates []*models.SchemaProcess
+	Steps        []*models.ProcessStep
+}
+
+func (t *transactionStub) SelectStuckCreateProcessStates(time.Time, time.Time) ([]*models.CreateProcess, error) {
+	return t.CreateStates, nil
+}
+
+func (t *transactionStub) SelectStuckDeleteProcessStates(time.Time, time.Time) ([]*models.DeleteProcess, error) {
+	return t.DeleteStates, nil
+}
+
+func (t *transactionStub) SelectStuckSchemaProcessStates(time.Time, time.Time) ([]*models.SchemaProcess, error) {
+	return t.SchemaStates, nil
+}
+
+func (t *transactionStub) UpdateCreateProcessState(*models.CreateProcess) error {
+	return nil
+}
+
+func (t *transactionStub) UpdateDeleteProcessState(*models.DeleteProcess) error {
+	return nil
+}
+
+func (t *transactionStub) UpdateSchemaProcessState(*models.SchemaProcess) error {
+	return nil
+}
+
+func (t *transactionStub) GetProcessSteps(string, string) ([]*models.ProcessStep, error) {
+	return t.Steps, nil
+}
+
+type processSpy[Input any] struct {
+	mu      sync.Mutex
+	Resumed []uuid.UUID
+}
+
+func (p *processSpy[Input]) Process(context.Context, Input) error {
+	return nil
+}
+
+func (p *processSpy[Input]) Resume(_ context.Context, processId uuid.UUID) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.Resumed = append(p.Resumed, processId)
+	return nil
+}
+
+type outboxSpy struct {
+	Produced []messaging.OutgoingMessage
+}
+
+func (o *outboxSpy) factory(OutboxRepository) Outbox {
+	return o
+}
+
+func (o *outboxSpy) Produce(msg messaging.OutgoingMessage) error {
+	o.Produced = append(o.Produced, msg)
+	return nil
+}
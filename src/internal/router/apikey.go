@@ -0,0 +1,68 @@
+package router
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/dfds/confluent-gateway/internal/models"
+)
+
+const ApiKeyHeader = "X-Api-Key"
+
+type apiKey struct {
+	key       []byte
+	principal Principal
+}
+
+// ApiKeyAuthenticator authenticates automation using static api keys passed in the X-Api-Key header.
+type ApiKeyAuthenticator struct {
+	keys []apiKey
+}
+
+// ParseApiKeys reads api keys from a comma separated list of "name:key:roles[:capabilityId]" entries, where
+// roles are separated by "|", e.g. "ci:s3cr3t:admin,team:0th3r:reader:some-capability".
+func ParseApiKeys(spec string) (*ApiKeyAuthenticator, error) {
+	authenticator := &ApiKeyAuthenticator{}
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.Split(entry, ":")
+		if len(parts) < 3 || len(parts) > 4 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid api key entry %q", parts[0])
+		}
+
+		principal := Principal{Subject: parts[0]}
+		for _, role := range strings.Split(parts[2], "|") {
+			principal.Roles = append(principal.Roles, Role(role))
+		}
+		if len(parts) == 4 {
+			principal.CapabilityId = models.CapabilityId(parts[3])
+		}
+
+		authenticator.keys = append(authenticator.keys, apiKey{key: []byte(parts[1]), principal: principal})
+	}
+
+	return authenticator, nil
+}
+
+func (a *ApiKeyAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	value := r.Header.Get(ApiKeyHeader)
+	if value == "" {
+		return nil, nil
+	}
+
+	for _, k := range a.keys {
+		if subtle.ConstantTimeCompare(k.key, []byte(value)) == 1 {
+			principal := k.principal
+			return &principal, nil
+		}
+	}
+
+	return nil, ErrInvalidCredentials
+}
@@ -0,0 +1,168 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/dfds/confluent-gateway/internal/handlers"
+	"github.com/dfds/confluent-gateway/internal/models"
+)
+
+type Role string
+
+const (
+	RoleAdmin  Role = "admin"
+	RoleReader Role = "reader"
+	// RoleOwner lets a principal restricted to a capability manage what the capability owns, such as the grants
+	// on its topics.
+	RoleOwner Role = "owner"
+)
+
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// Principal is the authenticated caller of the API. A non-empty CapabilityId restricts the caller to the
+// resources of that capability.
+type Principal struct {
+	Subject      string
+	Roles        []Role
+	CapabilityId models.CapabilityId
+}
+
+func (p *Principal) HasAnyRole(roles ...Role) bool {
+	for _, role := range roles {
+		if slices.Contains(p.Roles, role) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Principal) CanAccessCapability(capabilityId models.CapabilityId) bool {
+	return p.CapabilityId == "" || p.CapabilityId == capabilityId
+}
+
+// Authenticator resolves the caller of a request. It returns nil and no error when the request does not carry
+// credentials it understands, so the next authenticator can have a go.
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+// Rules maps a route pattern to the roles allowed to use it. Routes without a rule are restricted to admins.
+type Rules map[string][]Role
+
+func DefaultRules() Rules {
+	return Rules{
+		"GET /clusters":                                                   {RoleAdmin, RoleReader},
+		"GET /clusters/{clusterId}":                                       {RoleAdmin, RoleReader},
+		"GET /clusters/{clusterId}/acl-template":                          {RoleAdmin, RoleReader},
+		"GET /clusters/{clusterId}/schemas":                               {RoleAdmin, RoleReader},
+		"GET /clusters/{clusterId}/subjects":                              {RoleAdmin, RoleReader},
+		"GET /clusters/{clusterId}/subjects/{subject}/versions":           {RoleAdmin, RoleReader},
+		"GET /clusters/{clusterId}/subjects/{subject}/versions/{version}": {RoleAdmin, RoleReader},
+		"GET /clusters/{clusterId}/subjects/{subject}/compatibility":      {RoleAdmin, RoleReader},
+		"POST /schemas/compatibility":                                     {RoleAdmin, RoleReader},
+		"GET /capabilities/{capabilityId}/topics":                         {RoleAdmin, RoleReader},
+		"GET /clusters/{clusterId}/topics/{name}":                         {RoleAdmin, RoleReader},
+		"GET /capabilities/{capabilityId}/access":                         {RoleAdmin, RoleReader},
+		"GET /capabilities/{capabilityId}/role-bindings":                  {RoleAdmin, RoleReader},
+		"GET /capabilities/{capabilityId}/grants":                         {RoleAdmin, RoleReader},
+		"GET /capabilities/{capabilityId}/schema-settings":                {RoleAdmin, RoleReader},
+		"POST /grants/{grantId}/approve":                                  {RoleAdmin, RoleOwner},
+		"DELETE /grants/{grantId}":                                        {RoleAdmin, RoleOwner},
+	}
+}
+
+func (r Rules) allowedRoles(pattern string) []Role {
+	if roles, ok := r[pattern]; ok {
+		return roles
+	}
+	return []Role{RoleAdmin}
+}
+
+// CapabilityScope resolves the capability owning the resource of a request. A principal restricted to a capability
+// is only let through when the resource belongs to that capability.
+type CapabilityScope func(r *http.Request) (models.CapabilityId, error)
+
+// PathCapability scopes a route to the capability in its {capabilityId} path value.
+func PathCapability(r *http.Request) (models.CapabilityId, error) {
+	return models.CapabilityId(r.PathValue("capabilityId")), nil
+}
+
+type Auth struct {
+	Authenticators []Authenticator
+	Rules          Rules
+}
+
+func NewAuth(rules Rules, authenticators ...Authenticator) *Auth {
+	return &Auth{
+		Authenticators: authenticators,
+		Rules:          rules,
+	}
+}
+
+// Protect wraps the handler of a route with authentication and authorization. A nil Auth disables both. Routes
+// without a scope do not belong to a capability and are open to every principal with an allowed role.
+func (a *Auth) Protect(pattern string, scope CapabilityScope, next http.HandlerFunc) http.HandlerFunc {
+	if a == nil {
+		return next
+	}
+
+	roles := a.Rules.allowedRoles(pattern)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal, err := a.authenticate(r)
+		if err != nil || principal == nil {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeAuthError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		if !principal.HasAnyRole(roles...) {
+			writeAuthError(w, http.StatusForbidden, "Forbidden")
+			return
+		}
+
+		if principal.CapabilityId != "" && scope != nil {
+			capabilityId, err := scope(r)
+			if err != nil || !principal.CanAccessCapability(capabilityId) {
+				writeAuthError(w, http.StatusForbidden, "Forbidden")
+				return
+			}
+		}
+
+		next(w, r.WithContext(WithPrincipal(r.Context(), principal)))
+	}
+}
+
+func (a *Auth) authenticate(r *http.Request) (*Principal, error) {
+	for _, authenticator := range a.Authenticators {
+		principal, err := authenticator.Authenticate(r)
+		if err != nil {
+			return nil, err
+		}
+		if principal != nil {
+			return principal, nil
+		}
+	}
+	return nil, nil
+}
+
+func writeAuthError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(handlers.ErrorResponse{Message: message})
+}
+
+type principalKey struct{}
+
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+func PrincipalFrom(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok
+}
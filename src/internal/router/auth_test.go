@@ -0,0 +1,204 @@
+package router
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dfds/confluent-gateway/internal/handlers"
+	"github.com/dfds/confluent-gateway/internal/mocks"
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/dfds/confluent-gateway/internal/services"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newKeySet(t *testing.T, kid string) (*rsa.PrivateKey, *StaticKeySet) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(jsonWebKeySet{Keys: []jsonWebKey{{
+		Kty: "RSA",
+		Kid: kid,
+		N:   base64.RawURLEncoding.EncodeToString(privateKey.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(privateKey.E)).Bytes()),
+	}}})
+	require.NoError(t, err)
+
+	keySet, err := NewStaticKeySet(data)
+	require.NoError(t, err)
+
+	return privateKey, keySet
+}
+
+func signToken(t *testing.T, key *rsa.PrivateKey, kid string, claims tokenClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+
+	return signed
+}
+
+func TestJwtAuthenticator(t *testing.T) {
+	privateKey, keySet := newKeySet(t, "key-1")
+	authenticator := NewJwtAuthenticator(keySet, "https://issuer", "confluent-gateway")
+
+	valid := tokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "someone",
+			Issuer:    "https://issuer",
+			Audience:  jwt.ClaimStrings{"confluent-gateway"},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		Roles:        []string{"reader"},
+		CapabilityId: "some-capability",
+	}
+
+	expired := valid
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+
+	wrongAudience := valid
+	wrongAudience.Audience = jwt.ClaimStrings{"something-else"}
+
+	tests := []struct {
+		name          string
+		authorization string
+		want          *Principal
+		wantErr       bool
+	}{
+		{name: "no token", authorization: ""},
+		{name: "valid token", authorization: "Bearer " + signToken(t, privateKey, "key-1", valid), want: &Principal{Subject: "someone", Roles: []Role{RoleReader}, CapabilityId: "some-capability"}},
+		{name: "expired token", authorization: "Bearer " + signToken(t, privateKey, "key-1", expired), wantErr: true},
+		{name: "wrong audience", authorization: "Bearer " + signToken(t, privateKey, "key-1", wrongAudience), wantErr: true},
+		{name: "unknown key", authorization: "Bearer " + signToken(t, privateKey, "key-2", valid), wantErr: true},
+		{name: "garbage", authorization: "Bearer abc.def.ghi", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+
+			got, err := authenticator.Authenticate(req)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidCredentials)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseApiKeys(t *testing.T) {
+	authenticator, err := ParseApiKeys("ci:s3cr3t:admin|reader, team:0th3r:reader:some-capability")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(ApiKeyHeader, "0th3r")
+
+	principal, err := authenticator.Authenticate(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &Principal{Subject: "team", Roles: []Role{RoleReader}, CapabilityId: "some-capability"}, principal)
+
+	req.Header.Set(ApiKeyHeader, "wrong")
+	_, err = authenticator.Authenticate(req)
+
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	_, err = ParseApiKeys("missing-roles:key")
+	assert.Error(t, err)
+}
+
+func TestProtectedRoutes(t *testing.T) {
+	apiKeys, err := ParseApiKeys("admin:admin-key:admin,reader:reader-key:reader:some-capability,owner:owner-key:owner:some-capability,other:other-key:other")
+	require.NoError(t, err)
+
+	mockAccessService := &mocks.MockAccessService{}
+	mockAccessService.On("GetAccess", mock.Anything, models.CapabilityId("some-capability"), "").Return(&models.AccessInfo{}, nil)
+
+	mockTopicService := &mocks.MockTopicService{}
+	mockTopicService.On("GetTopic", mock.Anything, models.ClusterId("abc-1234"), "some-capability.orders", false).Return(&models.TopicInfo{CapabilityId: "some-capability"}, nil)
+	mockTopicService.On("GetTopic", mock.Anything, models.ClusterId("abc-1234"), "another-capability.orders", false).Return(&models.TopicInfo{CapabilityId: "another-capability"}, nil)
+
+	mockSchemaService := &mocks.MockSchemaService{}
+	mockSchemaService.On("ListSubjectVersions", mock.Anything, models.ClusterId("abc-1234"), mock.Anything).Return([]int{1}, nil)
+	mockSchemaService.On("ListSubjects", mock.Anything, models.ClusterId("abc-1234"), mock.Anything).Return([]string{}, nil)
+	mockSchemaService.On("GetSubjectOwner", mock.Anything, models.ClusterId("abc-1234"), "some-capability.orders-value").Return(models.CapabilityId("some-capability"), nil)
+	mockSchemaService.On("GetSubjectOwner", mock.Anything, models.ClusterId("abc-1234"), "some-capability.orders-order-placed").Return(models.CapabilityId("some-capability"), nil)
+	mockSchemaService.On("GetSubjectOwner", mock.Anything, models.ClusterId("abc-1234"), "another-capability.orders-value").Return(models.CapabilityId("another-capability"), nil)
+	mockSchemaService.On("GetSubjectOwner", mock.Anything, models.ClusterId("abc-1234"), "com.example.Order").Return(models.CapabilityId(""), services.ErrSubjectNotRegistered)
+	mockSchemaService.On("GetTopicOwner", mock.Anything, "own-topic").Return(models.CapabilityId("some-capability"), nil)
+	mockSchemaService.On("GetTopicOwner", mock.Anything, "other-topic").Return(models.CapabilityId("another-capability"), nil)
+	mockSchemaService.On("CheckCompatibility", mock.Anything, mock.Anything).Return(&models.CompatibilityResult{IsCompatible: true}, nil)
+
+	mockGrantService := &mocks.MockGrantService{}
+	mockGrantService.On("GetGrant", mock.Anything, "own-grant").Return(&models.AccessGrant{OwnerCapabilityId: "some-capability"}, nil)
+	mockGrantService.On("GetGrant", mock.Anything, "other-grant").Return(&models.AccessGrant{OwnerCapabilityId: "another-capability"}, nil)
+	mockGrantService.On("ApproveGrant", mock.Anything, "own-grant").Return(&models.AccessGrant{}, nil)
+
+	handler := &handlers.Handler{Ctx: context.Background(), Logger: &mocks.MockLogger{}, SchemaService: mockSchemaService, TopicService: mockTopicService, AccessService: mockAccessService, GrantService: mockGrantService}
+	mux := SetupRoutes(handler, NewAuth(DefaultRules(), apiKeys))
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		apiKey string
+		want   int
+	}{
+		{name: "health is public", path: "/health", want: http.StatusOK},
+		{name: "missing credentials", path: "/capabilities/some-capability/access", want: http.StatusUnauthorized},
+		{name: "invalid credentials", path: "/capabilities/some-capability/access", apiKey: "wrong", want: http.StatusUnauthorized},
+		{name: "role not allowed", path: "/capabilities/some-capability/access", apiKey: "other-key", want: http.StatusForbidden},
+		{name: "own capability", path: "/capabilities/some-capability/access", apiKey: "reader-key", want: http.StatusOK},
+		{name: "other capability", path: "/capabilities/another-capability/access", apiKey: "reader-key", want: http.StatusForbidden},
+		{name: "admin", path: "/capabilities/some-capability/access", apiKey: "admin-key", want: http.StatusOK},
+		{name: "own topic", path: "/clusters/abc-1234/topics/some-capability.orders", apiKey: "reader-key", want: http.StatusOK},
+		{name: "topic of other capability", path: "/clusters/abc-1234/topics/another-capability.orders", apiKey: "reader-key", want: http.StatusForbidden},
+		{name: "own subject", path: "/clusters/abc-1234/subjects/some-capability.orders-value/versions", apiKey: "reader-key", want: http.StatusOK},
+		{name: "subject of other capability", path: "/clusters/abc-1234/subjects/another-capability.orders-value/versions", apiKey: "reader-key", want: http.StatusForbidden},
+		{name: "own subject named after the message type", path: "/clusters/abc-1234/subjects/some-capability.orders-order-placed/versions", apiKey: "reader-key", want: http.StatusOK},
+		{name: "subject not registered", path: "/clusters/abc-1234/subjects/com.example.Order/versions", apiKey: "reader-key", want: http.StatusForbidden},
+		{name: "compatibility with own topic", method: http.MethodPost, path: "/schemas/compatibility", body: `{"kafkaTopicId": "own-topic", "messageType": "order-placed", "schema": "{}"}`, apiKey: "reader-key", want: http.StatusOK},
+		{name: "compatibility with topic of other capability", method: http.MethodPost, path: "/schemas/compatibility", body: `{"kafkaTopicId": "other-topic", "messageType": "order-placed", "schema": "{}"}`, apiKey: "reader-key", want: http.StatusForbidden},
+		{name: "subjects with own prefix", path: "/clusters/abc-1234/subjects?subjectPrefix=pub.some-capability.", apiKey: "reader-key", want: http.StatusOK},
+		{name: "subjects without prefix", path: "/clusters/abc-1234/subjects", apiKey: "reader-key", want: http.StatusForbidden},
+		{name: "owner approves own grant", method: http.MethodPost, path: "/grants/own-grant/approve", apiKey: "owner-key", want: http.StatusOK},
+		{name: "owner approves grant of other capability", method: http.MethodPost, path: "/grants/other-grant/approve", apiKey: "owner-key", want: http.StatusForbidden},
+		{name: "reader approves grant", method: http.MethodPost, path: "/grants/own-grant/approve", apiKey: "reader-key", want: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req := httptest.NewRequest(method, tt.path, strings.NewReader(tt.body))
+			if tt.apiKey != "" {
+				req.Header.Set(ApiKeyHeader, tt.apiKey)
+			}
+			rr := httptest.NewRecorder()
+
+			mux.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.want, rr.Code)
+		})
+	}
+}
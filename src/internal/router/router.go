@@ -2,6 +2,7 @@ package router
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/dfds/confluent-gateway/internal/handlers"
 	"github.com/dfds/confluent-gateway/internal/models"
@@ -14,21 +15,192 @@ import (
 
 // @contact.name	Cloud Engineering
 // @contact.email	cloud.engineering@dfds.com
-func SetupRoutes(handler *handlers.Handler) *http.ServeMux {
+func SetupRoutes(handler *handlers.Handler, auth *Auth) *http.ServeMux {
 	mux := http.NewServeMux()
 
+	route := func(pattern string, scope CapabilityScope, handlerFunc http.HandlerFunc) {
+		mux.HandleFunc(pattern, auth.Protect(pattern, scope, handlerFunc))
+	}
+
 	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
 		handlers.Health(w, r)
 	})
 
 	mux.Handle("/swagger", httpSwagger.WrapHandler)
 
-	mux.HandleFunc("GET /clusters/{clusterId}/schemas", func(w http.ResponseWriter, r *http.Request) {
+	route("GET /clusters", nil, func(w http.ResponseWriter, r *http.Request) {
+		handlers.ListClusters(handler, w, r)
+	})
+
+	route("POST /clusters", nil, func(w http.ResponseWriter, r *http.Request) {
+		handlers.CreateCluster(handler, w, r)
+	})
+
+	route("GET /clusters/{clusterId}", nil, func(w http.ResponseWriter, r *http.Request) {
+		clusterId := models.ClusterId(r.PathValue("clusterId"))
+
+		handlers.GetCluster(handler, w, r, clusterId)
+	})
+
+	route("PUT /clusters/{clusterId}", nil, func(w http.ResponseWriter, r *http.Request) {
+		clusterId := models.ClusterId(r.PathValue("clusterId"))
+
+		handlers.UpdateCluster(handler, w, r, clusterId)
+	})
+
+	route("DELETE /clusters/{clusterId}", nil, func(w http.ResponseWriter, r *http.Request) {
+		clusterId := models.ClusterId(r.PathValue("clusterId"))
+
+		handlers.DeleteCluster(handler, w, r, clusterId)
+	})
+
+	route("GET /clusters/{clusterId}/acl-template", nil, func(w http.ResponseWriter, r *http.Request) {
+		clusterId := models.ClusterId(r.PathValue("clusterId"))
+
+		handlers.GetAclTemplate(handler, w, r, clusterId)
+	})
+
+	route("PUT /clusters/{clusterId}/acl-template", nil, func(w http.ResponseWriter, r *http.Request) {
+		clusterId := models.ClusterId(r.PathValue("clusterId"))
+
+		handlers.UpdateAclTemplate(handler, w, r, clusterId)
+	})
+
+	route("POST /clusters/{clusterId}/acl-template/reapply", nil, func(w http.ResponseWriter, r *http.Request) {
+		clusterId := models.ClusterId(r.PathValue("clusterId"))
+
+		handlers.ReapplyAclTemplate(handler, w, r, clusterId)
+	})
+
+	route("GET /clusters/{clusterId}/schemas", SubjectPrefixCapability, func(w http.ResponseWriter, r *http.Request) {
+		clusterId := models.ClusterId(r.PathValue("clusterId"))
+
+		subjectPrefix := r.URL.Query().Get("subjectPrefix")
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		handlers.ListSchemas(handler, w, r, subjectPrefix, clusterId, offset, limit)
+	})
+
+	route("GET /clusters/{clusterId}/subjects", SubjectPrefixCapability, func(w http.ResponseWriter, r *http.Request) {
 		clusterId := models.ClusterId(r.PathValue("clusterId"))
 
 		subjectPrefix := r.URL.Query().Get("subjectPrefix")
 
-		handlers.ListSchemas(handler, w, r, subjectPrefix, clusterId)
+		handlers.ListSubjects(handler, w, r, clusterId, subjectPrefix)
+	})
+
+	route("GET /clusters/{clusterId}/subjects/{subject}/versions", SubjectCapability(handler), func(w http.ResponseWriter, r *http.Request) {
+		clusterId := models.ClusterId(r.PathValue("clusterId"))
+		subject := r.PathValue("subject")
+
+		handlers.ListSubjectVersions(handler, w, r, clusterId, subject)
+	})
+
+	route("GET /clusters/{clusterId}/subjects/{subject}/versions/{version}", SubjectCapability(handler), func(w http.ResponseWriter, r *http.Request) {
+		clusterId := models.ClusterId(r.PathValue("clusterId"))
+		subject := r.PathValue("subject")
+		version := r.PathValue("version")
+
+		handlers.GetSchemaVersion(handler, w, r, clusterId, subject, version)
+	})
+
+	route("GET /clusters/{clusterId}/subjects/{subject}/compatibility", SubjectCapability(handler), func(w http.ResponseWriter, r *http.Request) {
+		clusterId := models.ClusterId(r.PathValue("clusterId"))
+		subject := r.PathValue("subject")
+
+		handlers.GetCompatibilityLevel(handler, w, r, clusterId, subject)
+	})
+
+	route("PUT /clusters/{clusterId}/subjects/{subject}/compatibility", SubjectCapability(handler), func(w http.ResponseWriter, r *http.Request) {
+		clusterId := models.ClusterId(r.PathValue("clusterId"))
+		subject := r.PathValue("subject")
+
+		handlers.SetCompatibilityLevel(handler, w, r, clusterId, subject)
+	})
+
+	route("POST /schemas/compatibility", SchemaTopicCapability(handler), func(w http.ResponseWriter, r *http.Request) {
+		handlers.CheckSchemaCompatibility(handler, w, r)
+	})
+
+	route("GET /capabilities/{capabilityId}/topics", PathCapability, func(w http.ResponseWriter, r *http.Request) {
+		capabilityId := models.CapabilityId(r.PathValue("capabilityId"))
+
+		live, _ := strconv.ParseBool(r.URL.Query().Get("live"))
+
+		handlers.ListCapabilityTopics(handler, w, r, capabilityId, live)
+	})
+
+	route("GET /clusters/{clusterId}/topics/{name}", TopicCapability(handler), func(w http.ResponseWriter, r *http.Request) {
+		clusterId := models.ClusterId(r.PathValue("clusterId"))
+		name := r.PathValue("name")
+
+		live, _ := strconv.ParseBool(r.URL.Query().Get("live"))
+
+		handlers.GetClusterTopic(handler, w, r, clusterId, name, live)
+	})
+
+	route("GET /capabilities/{capabilityId}/schema-settings", PathCapability, func(w http.ResponseWriter, r *http.Request) {
+		capabilityId := models.CapabilityId(r.PathValue("capabilityId"))
+
+		handlers.GetCapabilitySchemaSettings(handler, w, r, capabilityId)
+	})
+
+	route("PUT /capabilities/{capabilityId}/schema-settings", PathCapability, func(w http.ResponseWriter, r *http.Request) {
+		capabilityId := models.CapabilityId(r.PathValue("capabilityId"))
+
+		handlers.UpdateCapabilitySchemaSettings(handler, w, r, capabilityId)
+	})
+
+	route("GET /capabilities/{capabilityId}/access", PathCapability, func(w http.ResponseWriter, r *http.Request) {
+		capabilityId := models.CapabilityId(r.PathValue("capabilityId"))
+
+		handlers.GetCapabilityAccess(handler, w, r, capabilityId)
+	})
+
+	route("GET /capabilities/{capabilityId}/role-bindings", PathCapability, func(w http.ResponseWriter, r *http.Request) {
+		capabilityId := models.CapabilityId(r.PathValue("capabilityId"))
+
+		handlers.ListCapabilityRoleBindings(handler, w, r, capabilityId)
+	})
+
+	route("DELETE /capabilities/{capabilityId}/role-bindings/{roleBindingId}", PathCapability, func(w http.ResponseWriter, r *http.Request) {
+		capabilityId := models.CapabilityId(r.PathValue("capabilityId"))
+		roleBindingId := r.PathValue("roleBindingId")
+
+		handlers.DeleteCapabilityRoleBinding(handler, w, r, capabilityId, roleBindingId)
+	})
+
+	route("GET /capabilities/{capabilityId}/grants", PathCapability, func(w http.ResponseWriter, r *http.Request) {
+		capabilityId := models.CapabilityId(r.PathValue("capabilityId"))
+
+		handlers.ListAccessGrants(handler, w, r, capabilityId)
+	})
+
+	route("POST /grants", nil, func(w http.ResponseWriter, r *http.Request) {
+		handlers.RequestAccessGrant(handler, w, r)
+	})
+
+	route("POST /grants/{grantId}/approve", GrantCapability(handler), func(w http.ResponseWriter, r *http.Request) {
+		grantId := r.PathValue("grantId")
+
+		handlers.ApproveAccessGrant(handler, w, r, grantId)
+	})
+
+	route("DELETE /grants/{grantId}", GrantCapability(handler), func(w http.ResponseWriter, r *http.Request) {
+		grantId := r.PathValue("grantId")
+
+		handlers.RevokeAccessGrant(handler, w, r, grantId)
+	})
+
+	route("GET /plans", nil, func(w http.ResponseWriter, r *http.Request) {
+		handlers.ListPlanEventTypes(handler, w, r)
+	})
+
+	route("POST /plans/{eventType}", nil, func(w http.ResponseWriter, r *http.Request) {
+		eventType := r.PathValue("eventType")
+
+		handlers.CreatePlan(handler, w, r, eventType)
 	})
 
 	return mux
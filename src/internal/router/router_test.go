@@ -22,13 +22,13 @@ func TestSetupRoutes(t *testing.T) {
 	mockSchemaService := &mocks.MockSchemaService{}
 
 	// Mock the ListSchemas method in the SchemaService
-	mockSchemaService.On("ListSchemas", mock.Anything, mock.AnythingOfType("string"), mock.Anything).Return(nil, nil)
+	mockSchemaService.On("ListSchemas", mock.Anything, mock.AnythingOfType("string"), mock.Anything, 0, 0).Return(nil, nil)
 
 	// Create a new handler with the mock services
 	handler := handlers.NewHandler(context.Background(), mockLogger, mockSchemaService)
 
 	// Initialize the routes with the handler
-	router := SetupRoutes(handler)
+	router := SetupRoutes(handler, nil)
 
 	// Create a new HTTP request to the /schemas route
 	req, err := http.NewRequest("GET", "/clusters/abc-1234/schemas", nil)
@@ -0,0 +1,83 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/dfds/confluent-gateway/internal/handlers"
+	"github.com/dfds/confluent-gateway/internal/models"
+)
+
+var ErrNoOwningCapability = errors.New("resource does not belong to a capability")
+
+// TopicCapability scopes a route to the capability owning the topic in its {clusterId} and {name} path values.
+func TopicCapability(handler *handlers.Handler) CapabilityScope {
+	return func(r *http.Request) (models.CapabilityId, error) {
+		return topicOwner(handler, r, models.ClusterId(r.PathValue("clusterId")), r.PathValue("name"))
+	}
+}
+
+// SubjectCapability scopes a route to the capability owning the topic the subject in its {subject} path value was
+// registered for.
+func SubjectCapability(handler *handlers.Handler) CapabilityScope {
+	return func(r *http.Request) (models.CapabilityId, error) {
+		return handler.SchemaService.GetSubjectOwner(r.Context(), models.ClusterId(r.PathValue("clusterId")), r.PathValue("subject"))
+	}
+}
+
+// SchemaTopicCapability scopes a route to the capability owning the topic in the kafkaTopicId of its request body. The
+// body is left for the handler to read.
+func SchemaTopicCapability(handler *handlers.Handler) CapabilityScope {
+	return func(r *http.Request) (models.CapabilityId, error) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return "", err
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		var request models.SchemaCompatibilityRequest
+		if err := json.Unmarshal(body, &request); err != nil || request.TopicId == "" {
+			return "", ErrNoOwningCapability
+		}
+
+		return handler.SchemaService.GetTopicOwner(r.Context(), request.TopicId)
+	}
+}
+
+// SubjectPrefixCapability scopes a route to the capability named by its subjectPrefix query parameter, which must
+// start with "<capability>." or "pub.<capability>.".
+func SubjectPrefixCapability(r *http.Request) (models.CapabilityId, error) {
+	prefix := strings.TrimPrefix(r.URL.Query().Get("subjectPrefix"), "pub.")
+
+	capabilityId, _, found := strings.Cut(prefix, ".")
+	if !found || capabilityId == "" {
+		return "", ErrNoOwningCapability
+	}
+
+	return models.CapabilityId(capabilityId), nil
+}
+
+// GrantCapability scopes a route to the capability owning the topic of the grant in its {grantId} path value.
+func GrantCapability(handler *handlers.Handler) CapabilityScope {
+	return func(r *http.Request) (models.CapabilityId, error) {
+		grant, err := handler.GrantService.GetGrant(r.Context(), r.PathValue("grantId"))
+		if err != nil {
+			return "", err
+		}
+
+		return grant.OwnerCapabilityId, nil
+	}
+}
+
+func topicOwner(handler *handlers.Handler, r *http.Request, clusterId models.ClusterId, topicName string) (models.CapabilityId, error) {
+	topic, err := handler.TopicService.GetTopic(r.Context(), clusterId, topicName, false)
+	if err != nil {
+		return "", err
+	}
+
+	return models.CapabilityId(topic.CapabilityId), nil
+}
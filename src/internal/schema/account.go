@@ -3,6 +3,7 @@ package schema
 import (
 	"context"
 	"fmt"
+	"github.com/dfds/confluent-gateway/internal/confluent"
 	"github.com/dfds/confluent-gateway/internal/models"
 )
 
@@ -14,6 +15,10 @@ type accountService struct {
 
 type serviceAccountRepository interface {
 	GetServiceAccount(capabilityId models.CapabilityId) (*models.ServiceAccount, error)
+	GetCluster(clusterId models.ClusterId) (*models.Cluster, error)
+	CreateRoleBinding(roleBinding *models.RoleBinding) error
+	UpdateRoleBinding(roleBinding *models.RoleBinding) error
+	HasRecordNameSubjects(clusterId models.ClusterId) (bool, error)
 }
 
 func NewSchemaAccountService(ctx context.Context, confluent Confluent, repo serviceAccountRepository) *accountService {
@@ -62,10 +67,45 @@ func (h *accountService) CreateSchemaRegistryApiKey(clusterAccess *models.Cluste
 
 }
 
-func (h *accountService) CreateServiceAccountRoleBinding(clusterAccess *models.ClusterAccess) error {
-	err := h.confluent.CreateServiceAccountRoleBinding(h.context, clusterAccess.ServiceAccountId, clusterAccess.ClusterId)
+// CreateSchemaRegistryRoleBindings binds the roles on the schema registry subjects of the capability and of the topics
+// it has been granted to the service account, and revokes the role on all subjects it was given before its bindings
+// were scoped to the capability. Subjects named after their record rather than their topic cannot be scoped, so the
+// role on all subjects is kept while the cluster has any.
+func (h *accountService) CreateSchemaRegistryRoleBindings(clusterAccess *models.ClusterAccess, capabilityId models.CapabilityId) error {
+	cluster, err := h.repo.GetCluster(clusterAccess.ClusterId)
 	if err != nil {
 		return err
 	}
-	return nil
+	if !cluster.HasSchemaRegistryIds() {
+		return confluent.ErrMissingSchemaRegistryIds
+	}
+
+	added := clusterAccess.AddRoleBindings(models.SchemaRegistryRoleBindings(cluster, capabilityId, clusterAccess.GetGrantedTopics()))
+	for i := range added {
+		if err := h.repo.CreateRoleBinding(&added[i]); err != nil {
+			return err
+		}
+	}
+
+	for _, roleBinding := range clusterAccess.GetRoleBindingsPendingCreation() {
+		confluentId, err := h.confluent.EnsureRoleBinding(h.context, clusterAccess.ServiceAccountId, roleBinding.RoleBindingDefinition)
+		if err != nil {
+			return fmt.Errorf("unable to create role binding %s, error: %w", roleBinding.RoleBindingDefinition, err)
+		}
+
+		roleBinding.Created(confluentId)
+		if err := h.repo.UpdateRoleBinding(&roleBinding); err != nil {
+			return err
+		}
+	}
+
+	if len(added) == 0 {
+		return nil
+	}
+
+	hasRecordNameSubjects, err := h.repo.HasRecordNameSubjects(cluster.ClusterId)
+	if err != nil || hasRecordNameSubjects {
+		return err
+	}
+	return h.confluent.RevokeRoleBinding(h.context, clusterAccess.ServiceAccountId, models.AllSubjectsRoleBinding(cluster))
 }
@@ -9,8 +9,15 @@ import (
 type Confluent interface {
 	CreateServiceAccount(ctx context.Context, name string, description string) (models.ServiceAccountId, error)
 	CreateSchemaRegistryApiKey(ctx context.Context, clusterId models.ClusterId, serviceAccountId models.ServiceAccountId) (models.ApiKey, error)
-	CreateServiceAccountRoleBinding(ctx context.Context, serviceAccount models.ServiceAccountId, clusterId models.ClusterId) error
+	EnsureRoleBinding(ctx context.Context, serviceAccount models.ServiceAccountId, definition models.RoleBindingDefinition) (string, error)
+	RevokeRoleBinding(ctx context.Context, serviceAccount models.ServiceAccountId, definition models.RoleBindingDefinition) error
 	CountSchemaRegistryApiKeys(ctx context.Context, clusterAccess models.ServiceAccountId, clusterId models.ClusterId) (int, error)
 	DeleteSchemaRegistryApiKey(ctx context.Context, clusterId models.ClusterId, serviceAccountId models.ServiceAccountId) error
-	RegisterSchema(ctx context.Context, clusterId models.ClusterId, subject string, schema string, version int32) error
+	RegisterSchema(ctx context.Context, clusterId models.ClusterId, subject string, schema models.SchemaDefinition, version int32) error
+	LookupSchemaVersion(ctx context.Context, clusterId models.ClusterId, subject string, schema models.SchemaDefinition) (int32, error)
+	CheckSchemaCompatibility(ctx context.Context, clusterId models.ClusterId, subject string, schema models.SchemaDefinition) (*models.CompatibilityResult, error)
+	ListSubjectVersions(ctx context.Context, clusterId models.ClusterId, subject string) ([]int, error)
+	SetCompatibilityLevel(ctx context.Context, clusterId models.ClusterId, subject string, level models.CompatibilityLevel) error
+	DeleteSubject(ctx context.Context, clusterId models.ClusterId, subject string, permanent bool) error
+	DeleteSchemaVersion(ctx context.Context, clusterId models.ClusterId, subject string, version string, permanent bool) error
 }
@@ -2,10 +2,13 @@ package schema
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"github.com/dfds/confluent-gateway/internal/confluent"
 	"github.com/dfds/confluent-gateway/internal/models"
 	"github.com/dfds/confluent-gateway/logging"
 	"github.com/dfds/confluent-gateway/messaging"
+	"net/http"
 )
 
 type StepContext struct {
@@ -23,7 +26,7 @@ type AccountService interface {
 	GetServiceAccount(models.CapabilityId) (*models.ServiceAccount, error)
 	GetClusterAccess(models.CapabilityId, models.ClusterId) (*models.ClusterAccess, error)
 	CreateSchemaRegistryApiKey(clusterAccess *models.ClusterAccess) (models.ApiKey, error)
-	CreateServiceAccountRoleBinding(clusterAccess *models.ClusterAccess) error
+	CreateSchemaRegistryRoleBindings(clusterAccess *models.ClusterAccess, capabilityId models.CapabilityId) error
 	CountSchemaRegistryApiKeys(clusterAccess *models.ClusterAccess) (int, error)
 	DeleteSchemaRegistryApiKey(clusterAccess *models.ClusterAccess) error
 }
@@ -50,8 +53,59 @@ func (c *StepContext) IsCompleted() bool {
 	return c.state.IsCompleted()
 }
 
+func (c *StepContext) ValidateSchema() error {
+	if c.state.CompatibilityLevel != "" && !c.state.CompatibilityLevel.IsValid() {
+		return fmt.Errorf("%w: unknown compatibility level %q", ErrInvalidSchema, c.state.CompatibilityLevel)
+	}
+	if !c.state.SubjectNameStrategy.IsValid() {
+		return fmt.Errorf("%w: unknown subject name strategy %q", ErrInvalidSchema, c.state.SubjectNameStrategy)
+	}
+	if err := Validate(c.state.Definition()); err != nil {
+		return err
+	}
+	if c.state.SubjectNameStrategy.UsesRecordName() && c.state.Subject == "" {
+		return fmt.Errorf("%w: %s requires a record name in the schema", ErrInvalidSchema, c.state.SubjectNameStrategy)
+	}
+	return nil
+}
+
+// EnsureCompatibilityLevel sets the compatibility level of a subject that has no versions yet. Existing subjects
+// keep the level they have.
+func (c *StepContext) EnsureCompatibilityLevel() error {
+	if c.state.CompatibilityLevel == "" {
+		return nil
+	}
+
+	_, err := c.registry.ListSubjectVersions(c.ctx, c.state.ClusterId, c.state.Subject)
+	if err == nil {
+		return nil
+	}
+
+	var clientError *confluent.ClientError
+	if !errors.As(err, &clientError) || clientError.Status != http.StatusNotFound {
+		return err
+	}
+
+	return c.registry.SetCompatibilityLevel(c.ctx, c.state.ClusterId, c.state.Subject, c.state.CompatibilityLevel)
+}
+
+func (c *StepContext) CheckSchemaCompatibility() (*models.CompatibilityResult, error) {
+	return c.registry.CheckSchemaCompatibility(c.ctx, c.state.ClusterId, c.state.Subject, c.state.Definition())
+}
+
 func (c *StepContext) RegisterSchema() error {
-	return c.registry.RegisterSchema(c.ctx, c.state.ClusterId, c.state.Subject, c.state.Schema, c.state.SchemaVersion)
+	if err := c.registry.RegisterSchema(c.ctx, c.state.ClusterId, c.state.Subject, c.state.Definition(), c.state.SchemaVersion); err != nil {
+		return err
+	}
+
+	version, err := c.registry.LookupSchemaVersion(c.ctx, c.state.ClusterId, c.state.Subject, c.state.Definition())
+	if err != nil {
+		return err
+	}
+
+	c.state.RegistryVersion = version
+
+	return nil
 }
 
 func (c *StepContext) MarkAsCompleted() {
@@ -73,6 +127,15 @@ func (c *StepContext) RaiseSchemaRegistrationFailed(reason string) error {
 	return c.outbox.Produce(event)
 }
 
+func (c *StepContext) RaiseSchemaIncompatible(compatibility *models.CompatibilityResult) error {
+	event := &SchemaRegistrationFailed{
+		MessageContractId: c.state.MessageContractId,
+		Reason:            fmt.Sprintf("schema is incompatible with the latest version of subject %s", compatibility.Subject),
+		Incompatibilities: compatibility.Messages,
+	}
+	return c.outbox.Produce(event)
+}
+
 func (c *StepContext) LogDebug(format string, args ...string) {
 	c.logger.Debug(format, args...)
 }
@@ -131,6 +194,10 @@ func (c *StepContext) DeleteSchemaRegistryApiKey(clusterAccess *models.ClusterAc
 	return c.account.DeleteSchemaRegistryApiKey(clusterAccess)
 }
 
-func (c *StepContext) CreateServiceAccountRoleBinding(clusterAccess *models.ClusterAccess) error {
-	return c.account.CreateServiceAccountRoleBinding(clusterAccess)
+func (c *StepContext) CreateSchemaRegistryRoleBindings(clusterAccess *models.ClusterAccess) error {
+	topic, err := c.topic.GetTopic(c.state.TopicId)
+	if err != nil {
+		return err
+	}
+	return c.account.CreateSchemaRegistryRoleBindings(clusterAccess, topic.CapabilityId)
 }
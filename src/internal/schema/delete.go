@@ -0,0 +1,145 @@
+package schema
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/dfds/confluent-gateway/internal/confluent"
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/dfds/confluent-gateway/logging"
+)
+
+type deleteProcess struct {
+	logger    logging.Logger
+	database  models.Database
+	confluent Confluent
+	factory   OutboxFactory
+}
+
+func NewDeleteProcess(logger logging.Logger, database models.Database, confluent Confluent, factory OutboxFactory) DeleteProcess {
+	return &deleteProcess{
+		logger:    logger,
+		database:  database,
+		confluent: confluent,
+		factory:   factory,
+	}
+}
+
+type DeleteProcessInput struct {
+	MessageContractId string
+	Permanent         bool
+}
+
+func (p *deleteProcess) Process(ctx context.Context, input DeleteProcessInput) error {
+	return p.database.NewSession(ctx).Transaction(func(tx models.Transaction) error {
+		state, err := tx.GetSchemaProcessState(input.MessageContractId)
+		if err != nil {
+			return err
+		}
+
+		if state == nil {
+			// contract was never registered => skip
+			p.logger.Warning("Schema of message contract {MessageContractId} not found", input.MessageContractId)
+			return nil
+		}
+
+		if state.IsDeleted() && (state.PermanentlyDeleted || !input.Permanent) {
+			// already deleted => skip
+			return nil
+		}
+
+		deleter := NewSchemaDeleter(ctx, p.confluent, tx)
+
+		version, err := deleter.Delete(state, input.Permanent)
+		if err != nil {
+			return err
+		}
+
+		state.MarkAsDeleted(input.Permanent)
+
+		if err := tx.UpdateSchemaProcessState(state); err != nil {
+			return err
+		}
+
+		return p.factory(tx).Produce(&SchemaDeleted{
+			MessageContractId: state.MessageContractId,
+			Subject:           state.Subject,
+			Version:           version,
+			Permanent:         input.Permanent,
+		})
+	})
+}
+
+type schemaDeleterRepository interface {
+	SelectSchemaProcessStatesBySubject(clusterId models.ClusterId, subject string) ([]models.SchemaProcess, error)
+}
+
+type SchemaDeleter struct {
+	ctx       context.Context
+	confluent Confluent
+	repo      schemaDeleterRepository
+}
+
+func NewSchemaDeleter(ctx context.Context, confluent Confluent, repo schemaDeleterRepository) *SchemaDeleter {
+	return &SchemaDeleter{ctx: ctx, confluent: confluent, repo: repo}
+}
+
+// Delete removes the schema of a contract from the schema registry. The whole subject is deleted when no other
+// contract still uses it, otherwise only the version of the contract. It returns the deleted version, which is
+// empty when the whole subject was deleted.
+func (d *SchemaDeleter) Delete(state *models.SchemaProcess, permanent bool) (string, error) {
+	if state.Subject == "" {
+		// schema never got a subject => nothing to delete
+		return "", nil
+	}
+
+	shared, err := d.isSubjectShared(state)
+	if err != nil {
+		return "", err
+	}
+
+	var version string
+	if shared {
+		var registryVersion int32
+		registryVersion, err = d.registryVersion(state)
+		if err == nil {
+			version = strconv.Itoa(int(registryVersion))
+			err = d.confluent.DeleteSchemaVersion(d.ctx, state.ClusterId, state.Subject, version, permanent)
+		}
+	} else {
+		err = d.confluent.DeleteSubject(d.ctx, state.ClusterId, state.Subject, permanent)
+	}
+
+	if errors.Is(err, confluent.ErrNoSchemaRegistry) {
+		// nothing was registered without a schema registry
+		return version, nil
+	}
+
+	return version, err
+}
+
+// registryVersion returns the version of the subject the schema of the contract was registered as. Schemas
+// registered before the version was recorded are looked up in the schema registry.
+func (d *SchemaDeleter) registryVersion(state *models.SchemaProcess) (int32, error) {
+	if state.RegistryVersion > 0 {
+		return state.RegistryVersion, nil
+	}
+
+	return d.confluent.LookupSchemaVersion(d.ctx, state.ClusterId, state.Subject, state.Definition())
+}
+
+func (d *SchemaDeleter) isSubjectShared(state *models.SchemaProcess) (bool, error) {
+	others, err := d.repo.SelectSchemaProcessStatesBySubject(state.ClusterId, state.Subject)
+	if err != nil {
+		return false, err
+	}
+
+	for _, other := range others {
+		if other.MessageContractId != state.MessageContractId && !other.IsDeleted() {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,103 @@
+package schema
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dfds/confluent-gateway/internal/confluent"
+	"github.com/dfds/confluent-gateway/internal/mocks"
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+)
+
+type deleterRepositoryStub struct {
+	BySubject []models.SchemaProcess
+}
+
+func (s *deleterRepositoryStub) SelectSchemaProcessStatesBySubject(models.ClusterId, string) ([]models.SchemaProcess, error) {
+	return s.BySubject, nil
+}
+
+func TestSchemaDeleter_Delete(t *testing.T) {
+	state := &models.SchemaProcess{ClusterId: someClusterId, MessageContractId: someMessageContractId, Subject: "some-subject", SchemaVersion: 2, RegistryVersion: 5}
+	legacyState := &models.SchemaProcess{ClusterId: someClusterId, MessageContractId: someMessageContractId, Subject: "some-subject", SchemaVersion: 2}
+	now := state.CreatedAt
+
+	tests := []struct {
+		name        string
+		state       *models.SchemaProcess
+		bySubject   []models.SchemaProcess
+		permanent   bool
+		setup       func(*mocks.MockClient)
+		wantVersion string
+	}{
+		{
+			name:      "last contract deletes subject",
+			state:     state,
+			bySubject: []models.SchemaProcess{*state, {MessageContractId: "deleted-contract", DeletedAt: &now}},
+			permanent: true,
+			setup: func(m *mocks.MockClient) {
+				m.On("DeleteSubject", testifymock.Anything, someClusterId, "some-subject", true).Return(nil)
+			},
+			wantVersion: "",
+		},
+		{
+			name:      "shared subject deletes registry version",
+			state:     state,
+			bySubject: []models.SchemaProcess{*state, {MessageContractId: "other-contract"}},
+			setup: func(m *mocks.MockClient) {
+				m.On("DeleteSchemaVersion", testifymock.Anything, someClusterId, "some-subject", "5", false).Return(nil)
+			},
+			wantVersion: "5",
+		},
+		{
+			name:      "shared subject looks up unrecorded registry version",
+			state:     legacyState,
+			bySubject: []models.SchemaProcess{*legacyState, {MessageContractId: "other-contract"}},
+			setup: func(m *mocks.MockClient) {
+				m.On("LookupSchemaVersion", testifymock.Anything, someClusterId, "some-subject", legacyState.Definition()).Return(int32(3), nil)
+				m.On("DeleteSchemaVersion", testifymock.Anything, someClusterId, "some-subject", "3", false).Return(nil)
+			},
+			wantVersion: "3",
+		},
+		{
+			name:  "no schema registry",
+			state: state,
+			setup: func(m *mocks.MockClient) {
+				m.On("DeleteSubject", testifymock.Anything, someClusterId, "some-subject", false).Return(confluent.ErrNoSchemaRegistry)
+			},
+			wantVersion: "",
+		},
+		{
+			name:        "no subject",
+			state:       &models.SchemaProcess{MessageContractId: someMessageContractId},
+			setup:       func(*mocks.MockClient) {},
+			wantVersion: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mocks.MockClient{}
+			tt.setup(client)
+
+			sut := NewSchemaDeleter(context.TODO(), client, &deleterRepositoryStub{BySubject: tt.bySubject})
+
+			version, err := sut.Delete(tt.state, tt.permanent)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantVersion, version)
+			client.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSchemaProcess_MarkAsDeleted(t *testing.T) {
+	state := &models.SchemaProcess{}
+
+	state.MarkAsDeleted(true)
+	state.MarkAsDeleted(false)
+
+	assert.True(t, state.IsDeleted())
+	assert.True(t, state.PermanentlyDeleted)
+}
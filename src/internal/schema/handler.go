@@ -3,6 +3,7 @@ package schema
 import (
 	"context"
 	"fmt"
+	"github.com/dfds/confluent-gateway/internal/models"
 	"github.com/dfds/confluent-gateway/messaging"
 )
 
@@ -23,12 +24,41 @@ func (h *handler) Handle(ctx context.Context, msgContext messaging.MessageContex
 
 	case *MessageContractRequested:
 		input := ProcessInput{
+			MessageContractId:   message.MessageContractId,
+			TopicId:             message.TopicId,
+			MessageType:         message.MessageType,
+			Description:         message.Description,
+			Schema:              models.NewSchemaDefinition(message.SchemaType, message.Schema, message.References).WithRules(message.Metadata, message.RuleSet),
+			CompatibilityLevel:  message.CompatibilityLevel,
+			SubjectNameStrategy: message.SubjectNameStrategy,
+			SchemaVersion:       message.SchemaVersion,
+		}
+		return h.process.Process(ctx, input)
+
+	default:
+		return fmt.Errorf("unknown message %#v", message)
+	}
+}
+
+type deletedHandler struct {
+	process DeleteProcess
+}
+
+func NewMessageContractDeletedHandler(process DeleteProcess) messaging.MessageHandler {
+	return &deletedHandler{process: process}
+}
+
+type DeleteProcess interface {
+	Process(context.Context, DeleteProcessInput) error
+}
+
+func (h *deletedHandler) Handle(ctx context.Context, msgContext messaging.MessageContext) error {
+	switch message := msgContext.Message().(type) {
+
+	case *MessageContractDeleted:
+		input := DeleteProcessInput{
 			MessageContractId: message.MessageContractId,
-			TopicId:           message.TopicId,
-			MessageType:       message.MessageType,
-			Description:       message.Description,
-			Schema:            message.Schema,
-			SchemaVersion:     message.SchemaVersion,
+			Permanent:         message.Permanent,
 		}
 		return h.process.Process(ctx, input)
 
@@ -1,12 +1,25 @@
 package schema
 
+import "github.com/dfds/confluent-gateway/internal/models"
+
 type MessageContractRequested struct {
+	MessageContractId   string                     `json:"messageContractId"`
+	TopicId             string                     `json:"kafkaTopicId"`
+	MessageType         string                     `json:"messageType"`
+	Description         string                     `json:"description"`
+	Schema              string                     `json:"schema"`
+	SchemaType          models.SchemaType          `json:"schemaType"`
+	References          []models.Reference         `json:"references"`
+	Metadata            *models.Metadata           `json:"metadata"`
+	RuleSet             *models.RuleSet            `json:"ruleSet"`
+	CompatibilityLevel  models.CompatibilityLevel  `json:"compatibilityLevel"`
+	SubjectNameStrategy models.SubjectNameStrategy `json:"subjectNameStrategy"`
+	SchemaVersion       int32                      `json:"schemaVersion"`
+}
+
+type MessageContractDeleted struct {
 	MessageContractId string `json:"messageContractId"`
-	TopicId           string `json:"kafkaTopicId"`
-	MessageType       string `json:"messageType"`
-	Description       string `json:"description"`
-	Schema            string `json:"schema"`
-	SchemaVersion     int32  `json:"schemaVersion"`
+	Permanent         bool   `json:"permanent"`
 }
 
 type SchemaRegistered struct {
@@ -18,10 +31,22 @@ func (t *SchemaRegistered) PartitionKey() string {
 }
 
 type SchemaRegistrationFailed struct {
-	MessageContractId string `json:"messageContractId"`
-	Reason            string `json:"reason"`
+	MessageContractId string   `json:"messageContractId"`
+	Reason            string   `json:"reason"`
+	Incompatibilities []string `json:"incompatibilities,omitempty"`
 }
 
 func (t *SchemaRegistrationFailed) PartitionKey() string {
 	return t.MessageContractId
 }
+
+type SchemaDeleted struct {
+	MessageContractId string `json:"messageContractId"`
+	Subject           string `json:"subject"`
+	Version           string `json:"version,omitempty"`
+	Permanent         bool   `json:"permanent"`
+}
+
+func (t *SchemaDeleted) PartitionKey() string {
+	return t.MessageContractId
+}
@@ -3,13 +3,14 @@ package schema
 import (
 	"context"
 	"errors"
-	"fmt"
 	"github.com/dfds/confluent-gateway/internal/confluent"
 	"github.com/dfds/confluent-gateway/internal/models"
 	. "github.com/dfds/confluent-gateway/internal/process"
 	"github.com/dfds/confluent-gateway/internal/storage"
 	"github.com/dfds/confluent-gateway/internal/vault"
 	"github.com/dfds/confluent-gateway/logging"
+	uuid "github.com/satori/go.uuid"
+	"time"
 )
 
 type logger interface {
@@ -18,6 +19,12 @@ type logger interface {
 	LogError(error, string, ...string)
 }
 
+// ResumableProcess is a process that can also be continued from a state it left incomplete.
+type ResumableProcess interface {
+	Process
+	Resume(ctx context.Context, processId uuid.UUID) error
+}
+
 type process struct {
 	logger    logging.Logger
 	database  models.Database
@@ -26,7 +33,7 @@ type process struct {
 	vault     vault.Vault
 }
 
-func NewProcess(logger logging.Logger, database models.Database, confluent Confluent, vault vault.Vault, factory OutboxFactory) Process {
+func NewProcess(logger logging.Logger, database models.Database, confluent Confluent, vault vault.Vault, factory OutboxFactory) ResumableProcess {
 	return &process{
 		logger:    logger,
 		database:  database,
@@ -37,12 +44,14 @@ func NewProcess(logger logging.Logger, database models.Database, confluent Confl
 }
 
 type ProcessInput struct {
-	MessageContractId string
-	TopicId           string
-	MessageType       string
-	Description       string
-	Schema            string
-	SchemaVersion     int32
+	MessageContractId   string
+	TopicId             string
+	MessageType         string
+	Description         string
+	Schema              models.SchemaDefinition
+	CompatibilityLevel  models.CompatibilityLevel
+	SubjectNameStrategy models.SubjectNameStrategy
+	SchemaVersion       int32
 }
 
 func (p *process) Process(ctx context.Context, input ProcessInput) error {
@@ -64,11 +73,45 @@ func (p *process) Process(ctx context.Context, input ProcessInput) error {
 		return nil
 	}
 
+	return p.run(ctx, session, state)
+}
+
+// Resume continues the process from the state it was left in.
+func (p *process) Resume(ctx context.Context, processId uuid.UUID) error {
+	session := p.database.NewSession(ctx)
+
+	state := &models.SchemaProcess{Id: processId}
+	if err := session.Transaction(func(tx models.Transaction) error { return tx.LockSchemaProcessState(state) }); err != nil {
+		return err
+	}
+
+	if state.IsCompleted() || state.IsFailed() {
+		// finished in the meantime => skip
+		return nil
+	}
+
+	return p.run(ctx, session, state)
+}
+
+func (p *process) run(ctx context.Context, session models.Session, state *models.SchemaProcess) error {
+	// the steps start over from the locked process state on every attempt, so failing calls to Confluent can safely be
+	// retried
+	retry := WithRetry(3, ExponentialBackoff(time.Second, 5*time.Second))
+
 	return PrepareSteps[*StepContext]().
-		Step(ensureServiceAccountSchemaRegistryAccessStep).
-		Step(ensureSchemaIsRegistered).
+		Step(ensureServiceAccountSchemaRegistryAccessStep, retry).
+		Step(ensureSchemaIsRegistered, retry).
+		RecordTo(NewStepRecorder(session, "schema", state.Id.String())).
 		Run(func(step func(*StepContext) error) error {
 			return session.Transaction(func(tx models.Transaction) error {
+				// another run of the process waits for this step and continues from its outcome
+				if err := tx.LockSchemaProcessState(state); err != nil {
+					return err
+				}
+				if state.IsCompleted() || state.IsFailed() {
+					return nil
+				}
+
 				stepContext := p.getStepContext(ctx, tx, state)
 
 				err := step(stepContext)
@@ -110,6 +153,8 @@ func (p *process) prepareProcessState(session models.Session, input ProcessInput
 type schemaRepository interface {
 	GetSchemaProcessState(messageContractId string) (*models.SchemaProcess, error)
 	SaveSchemaProcessState(state *models.SchemaProcess) error
+	GetCapabilitySchemaSettings(capabilityId models.CapabilityId) (*models.CapabilitySchemaSettings, error)
+	GetCluster(clusterId models.ClusterId) (*models.Cluster, error)
 }
 
 func getOrCreateProcessState(repo schemaRepository, input ProcessInput, topic *models.Topic) (*models.SchemaProcess, error) {
@@ -118,13 +163,28 @@ func getOrCreateProcessState(repo schemaRepository, input ProcessInput, topic *m
 		return nil, err
 	}
 
-	if schema != nil && !schema.IsCompleted() {
+	if schema != nil && !schema.IsCompleted() && !schema.IsFailed() {
 		// is process is unfinished => continue
 		return schema, nil
 	}
 
-	subject := fmt.Sprintf("%s-%s", topic.Name, input.MessageType)
-	schema = models.NewSchemaProcess(topic.ClusterId, input.MessageContractId, input.TopicId, input.MessageType, input.Description, subject, input.Schema, input.SchemaVersion)
+	compatibilityLevel, err := resolveCompatibilityLevel(repo, input, topic)
+	if err != nil {
+		return nil, err
+	}
+
+	strategy, err := resolveSubjectNameStrategy(repo, input, topic)
+	if err != nil {
+		return nil, err
+	}
+
+	// without a valid strategy or a record name the subject is left empty, and the schema is rejected when validated
+	var subject string
+	if strategy.IsValid() {
+		subject, _ = strategy.SubjectName(topic.Name, input.MessageType, input.Schema)
+	}
+
+	schema = models.NewSchemaProcess(topic.ClusterId, input.MessageContractId, input.TopicId, input.MessageType, input.Description, subject, strategy, input.Schema, compatibilityLevel, input.SchemaVersion)
 
 	if err := repo.SaveSchemaProcessState(schema); err != nil {
 		return nil, err
@@ -133,6 +193,44 @@ func getOrCreateProcessState(repo schemaRepository, input ProcessInput, topic *m
 	return schema, nil
 }
 
+// resolveCompatibilityLevel prefers the level of the contract over the default of the capability. An empty level
+// leaves the subject at the registry default.
+func resolveCompatibilityLevel(repo schemaRepository, input ProcessInput, topic *models.Topic) (models.CompatibilityLevel, error) {
+	if input.CompatibilityLevel != "" {
+		return input.CompatibilityLevel, nil
+	}
+
+	settings, err := repo.GetCapabilitySchemaSettings(topic.CapabilityId)
+	if err != nil {
+		return "", err
+	}
+
+	if settings == nil {
+		return "", nil
+	}
+
+	return settings.CompatibilityLevel, nil
+}
+
+// resolveSubjectNameStrategy prefers the strategy of the contract over the strategy of the cluster, falling back to
+// the default strategy.
+func resolveSubjectNameStrategy(repo schemaRepository, input ProcessInput, topic *models.Topic) (models.SubjectNameStrategy, error) {
+	if input.SubjectNameStrategy != "" {
+		return input.SubjectNameStrategy, nil
+	}
+
+	cluster, err := repo.GetCluster(topic.ClusterId)
+	if err != nil {
+		return "", err
+	}
+
+	if cluster == nil || cluster.SubjectNameStrategy == "" {
+		return models.SubjectNameStrategyTopicMessageType, nil
+	}
+
+	return cluster.SubjectNameStrategy, nil
+}
+
 func (p *process) getStepContext(ctx context.Context, tx models.Transaction, schema *models.SchemaProcess) *StepContext {
 	newAccountService := NewSchemaAccountService(ctx, p.confluent, tx)
 	vaultService := NewVaultService(ctx, p.vault)
@@ -149,10 +247,14 @@ func ensureSchemaIsRegistered(stepContext *StepContext) error {
 
 type EnsureSchemaIsRegisteredStep interface {
 	IsCompleted() bool
+	ValidateSchema() error
+	EnsureCompatibilityLevel() error
+	CheckSchemaCompatibility() (*models.CompatibilityResult, error)
 	RegisterSchema() error
 	MarkAsCompleted()
 	RaiseSchemaRegisteredEvent() error
 	RaiseSchemaRegistrationFailed(string) error
+	RaiseSchemaIncompatible(*models.CompatibilityResult) error
 }
 
 func ensureSchemaIsRegisteredStep(step EnsureSchemaIsRegisteredStep) error {
@@ -160,7 +262,25 @@ func ensureSchemaIsRegisteredStep(step EnsureSchemaIsRegisteredStep) error {
 		return nil
 	}
 
-	err := step.RegisterSchema()
+	if err := step.ValidateSchema(); err != nil {
+		step.MarkAsCompleted()
+		return step.RaiseSchemaRegistrationFailed(err.Error())
+	}
+
+	err := step.EnsureCompatibilityLevel()
+
+	if err == nil {
+		var compatibility *models.CompatibilityResult
+		compatibility, err = step.CheckSchemaCompatibility()
+		if err == nil && !compatibility.IsCompatible {
+			step.MarkAsCompleted()
+			return step.RaiseSchemaIncompatible(compatibility)
+		}
+	}
+
+	if err == nil {
+		err = step.RegisterSchema()
+	}
 
 	if errors.Is(err, confluent.ErrNoSchemaRegistry) {
 		step.MarkAsCompleted()
@@ -187,7 +307,7 @@ type EnsureServiceAccountSchemaRegistryAccessStep interface {
 	GetClusterAccess() (*models.ClusterAccess, error)
 	HasSchemaRegistryApiKey(clusterAccess *models.ClusterAccess) (bool, error)
 	HasSchemaRegistryApiKeyInVault(clusterAccess *models.ClusterAccess) (bool, error)
-	CreateServiceAccountRoleBinding(*models.ClusterAccess) error
+	CreateSchemaRegistryRoleBindings(*models.ClusterAccess) error
 	CreateSchemaRegistryApiKeyAndStoreInVault(clusterAccess *models.ClusterAccess, shouldOverwriteKey bool) error
 	DeleteSchemaRegistryApiKey(clusterAccess *models.ClusterAccess) error
 }
@@ -204,7 +324,7 @@ func ensureServiceAccountSchemaRegistryAccessStep(step *StepContext) error {
 			return nil
 		}
 
-		err = step.CreateServiceAccountRoleBinding(clusterAccess)
+		err = step.CreateSchemaRegistryRoleBindings(clusterAccess)
 		if err != nil {
 			if errors.Is(err, confluent.ErrMissingSchemaRegistryIds) {
 				step.LogError(err, "unable to setup schema registry access")
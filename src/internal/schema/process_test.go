@@ -34,7 +34,7 @@ func Test_createProcessState(t *testing.T) {
 				MessageContractId: someMessageContractId,
 				TopicId:           someTopicId,
 				MessageType:       "message-type",
-				Schema:            "{}",
+				Schema:            models.NewSchemaDefinition("", "{}", nil),
 				Description:       "description",
 			},
 			topic:           models.Topic{ClusterId: someClusterId, Name: someTopicName},
@@ -84,6 +84,7 @@ func Test_createProcessState(t *testing.T) {
 			assert.Equal(t, someTopicId, got.TopicId)
 			assert.Equal(t, "message-type", got.MessageType)
 			assert.Equal(t, "{}", got.Schema)
+			assert.Equal(t, models.SchemaTypeJson, got.Definition().SchemaType)
 			assert.Equal(t, "description", got.Description)
 			assert.Equal(t, someTopicName+"-message-type", got.Subject)
 			assert.Equal(t, someClusterId, got.ClusterId)
@@ -95,6 +96,8 @@ func Test_createProcessState(t *testing.T) {
 type mock struct {
 	ReturnProcessState   *models.SchemaProcess
 	ReturnServiceAccount *models.ServiceAccount
+	ReturnSettings       *models.CapabilitySchemaSettings
+	ReturnCluster        *models.Cluster
 }
 
 func (m *mock) GetSchemaProcessState(messageContractId string) (*models.SchemaProcess, error) {
@@ -105,6 +108,61 @@ func (m *mock) SaveSchemaProcessState(state *models.SchemaProcess) error {
 	return nil
 }
 
+func (m *mock) GetCapabilitySchemaSettings(models.CapabilityId) (*models.CapabilitySchemaSettings, error) {
+	return m.ReturnSettings, nil
+}
+
+func (m *mock) GetCluster(models.ClusterId) (*models.Cluster, error) {
+	return m.ReturnCluster, nil
+}
+
+func Test_resolveCompatibilityLevel(t *testing.T) {
+	tests := []struct {
+		name  string
+		mock  *mock
+		input ProcessInput
+		want  models.CompatibilityLevel
+	}{
+		{name: "registry default", mock: &mock{}, input: ProcessInput{}, want: ""},
+		{name: "capability default", mock: &mock{ReturnSettings: &models.CapabilitySchemaSettings{CompatibilityLevel: models.CompatibilityFull}}, input: ProcessInput{}, want: models.CompatibilityFull},
+		{name: "contract overrides capability", mock: &mock{ReturnSettings: &models.CapabilitySchemaSettings{CompatibilityLevel: models.CompatibilityFull}}, input: ProcessInput{CompatibilityLevel: models.CompatibilityNone}, want: models.CompatibilityNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveCompatibilityLevel(tt.mock, tt.input, &models.Topic{CapabilityId: someCapabilityId})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_createProcessStateSubjectNameStrategy(t *testing.T) {
+	avro := models.NewSchemaDefinition(models.SchemaTypeAvro, `{"type":"record","name":"SomeRecord","namespace":"com.example","fields":[]}`, nil)
+
+	tests := []struct {
+		name         string
+		mock         *mock
+		input        ProcessInput
+		wantStrategy models.SubjectNameStrategy
+		wantSubject  string
+	}{
+		{name: "default", mock: &mock{}, input: ProcessInput{MessageType: "message-type", Schema: avro}, wantStrategy: models.SubjectNameStrategyTopicMessageType, wantSubject: someTopicName + "-message-type"},
+		{name: "cluster strategy", mock: &mock{ReturnCluster: &models.Cluster{SubjectNameStrategy: models.SubjectNameStrategyTopic}}, input: ProcessInput{MessageType: "message-type", Schema: avro}, wantStrategy: models.SubjectNameStrategyTopic, wantSubject: someTopicName + "-value"},
+		{name: "contract overrides cluster", mock: &mock{ReturnCluster: &models.Cluster{SubjectNameStrategy: models.SubjectNameStrategyTopic}}, input: ProcessInput{MessageType: "message-type", Schema: avro, SubjectNameStrategy: models.SubjectNameStrategyRecord}, wantStrategy: models.SubjectNameStrategyRecord, wantSubject: "com.example.SomeRecord"},
+		{name: "no record name", mock: &mock{}, input: ProcessInput{MessageType: "message-type", Schema: models.NewSchemaDefinition("", "{}", nil), SubjectNameStrategy: models.SubjectNameStrategyTopicRecord}, wantStrategy: models.SubjectNameStrategyTopicRecord, wantSubject: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getOrCreateProcessState(tt.mock, tt.input, &models.Topic{ClusterId: someClusterId, Name: someTopicName})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStrategy, got.SubjectNameStrategy)
+			assert.Equal(t, tt.wantSubject, got.Subject)
+		})
+	}
+}
+
 func Test_ensureSchemaIsRegistered(t *testing.T) {
 	tests := []struct {
 		name               string
@@ -113,6 +171,7 @@ func Test_ensureSchemaIsRegistered(t *testing.T) {
 		marked             bool
 		okEventRaised      bool
 		failureEventRaised bool
+		incompatibleRaised bool
 	}{
 		{
 			name:               "ok",
@@ -138,6 +197,47 @@ func Test_ensureSchemaIsRegistered(t *testing.T) {
 			okEventRaised:      false,
 			failureEventRaised: true,
 		},
+		{
+			name:               "compatibility level error",
+			context:            &mocks.StepContextMock{OnEnsureCompatibilityLevelError: confluentError},
+			wantErr:            assert.NoError,
+			marked:             true,
+			okEventRaised:      false,
+			failureEventRaised: true,
+		},
+		{
+			name:               "invalid schema",
+			context:            &mocks.StepContextMock{OnValidateSchemaError: ErrInvalidSchema},
+			wantErr:            assert.NoError,
+			marked:             true,
+			okEventRaised:      false,
+			failureEventRaised: true,
+		},
+		{
+			name:               "incompatible schema",
+			context:            &mocks.StepContextMock{ReturnCompatibilityResult: &models.CompatibilityResult{IsCompatible: false, Messages: []string{"property removed"}}},
+			wantErr:            assert.NoError,
+			marked:             true,
+			okEventRaised:      false,
+			failureEventRaised: false,
+			incompatibleRaised: true,
+		},
+		{
+			name:               "compatibility check error",
+			context:            &mocks.StepContextMock{OnCheckSchemaCompatibilityError: serviceError},
+			wantErr:            assert.Error,
+			marked:             false,
+			okEventRaised:      false,
+			failureEventRaised: false,
+		},
+		{
+			name:               "confluent client compatibility check error",
+			context:            &mocks.StepContextMock{OnCheckSchemaCompatibilityError: confluentError},
+			wantErr:            assert.NoError,
+			marked:             true,
+			okEventRaised:      false,
+			failureEventRaised: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -146,6 +246,7 @@ func Test_ensureSchemaIsRegistered(t *testing.T) {
 			assert.Equal(t, tt.marked, tt.context.MarkAsCompletedWasCalled)
 			assert.Equal(t, tt.okEventRaised, tt.context.SchemaRegisteredEventWasRaised)
 			assert.Equal(t, tt.failureEventRaised, tt.context.SchemaRegistrationFailedWasRaised)
+			assert.Equal(t, tt.incompatibleRaised, tt.context.SchemaIncompatibleWasRaised)
 		})
 	}
 }
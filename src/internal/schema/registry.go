@@ -6,5 +6,9 @@ import (
 )
 
 type SchemaRegistry interface {
-	RegisterSchema(ctx context.Context, clusterId models.ClusterId, subject string, schema string, version int32) error
+	RegisterSchema(ctx context.Context, clusterId models.ClusterId, subject string, schema models.SchemaDefinition, version int32) error
+	LookupSchemaVersion(ctx context.Context, clusterId models.ClusterId, subject string, schema models.SchemaDefinition) (int32, error)
+	CheckSchemaCompatibility(ctx context.Context, clusterId models.ClusterId, subject string, schema models.SchemaDefinition) (*models.CompatibilityResult, error)
+	ListSubjectVersions(ctx context.Context, clusterId models.ClusterId, subject string) ([]int, error)
+	SetCompatibilityLevel(ctx context.Context, clusterId models.ClusterId, subject string, level models.CompatibilityLevel) error
 }
@@ -0,0 +1,240 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/dfds/confluent-gateway/internal/models"
+)
+
+var ErrInvalidSchema = errors.New("invalid schema")
+
+// Validate does a local syntax check of a schema, so obviously broken schemas are rejected without a round trip
+// to the schema registry. It does not replace the validation done by the registry.
+func Validate(definition models.SchemaDefinition) error {
+	if strings.TrimSpace(definition.Schema) == "" {
+		return fmt.Errorf("%w: schema is empty", ErrInvalidSchema)
+	}
+
+	for _, reference := range definition.References {
+		if reference.Name == "" || reference.Subject == "" {
+			return fmt.Errorf("%w: references must have a name and a subject", ErrInvalidSchema)
+		}
+	}
+
+	if definition.RuleSet != nil {
+		if err := validateRuleSet(*definition.RuleSet); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidSchema, err)
+		}
+	}
+
+	var err error
+
+	switch definition.SchemaType {
+	case models.SchemaTypeJson:
+		err = validateJsonSchema(definition.Schema)
+	case models.SchemaTypeAvro:
+		err = validateAvroSchema(definition.Schema)
+	case models.SchemaTypeProtobuf:
+		err = validateProtobufSchema(definition.Schema)
+	default:
+		err = fmt.Errorf("unsupported schema type %q", definition.SchemaType)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidSchema, err)
+	}
+
+	return nil
+}
+
+var migrationRuleModes = []string{"UPGRADE", "DOWNGRADE", "UPDOWN"}
+var domainRuleModes = []string{"WRITE", "READ", "WRITEREAD"}
+
+func validateRuleSet(ruleSet models.RuleSet) error {
+	names := make(map[string]bool)
+
+	validate := func(rule models.Rule, modes []string) error {
+		if rule.Name == "" {
+			return errors.New("rules must have a name")
+		}
+		if names[rule.Name] {
+			return fmt.Errorf("rule %q is defined more than once", rule.Name)
+		}
+		names[rule.Name] = true
+
+		if rule.Kind != "TRANSFORM" && rule.Kind != "CONDITION" {
+			return fmt.Errorf("rule %q has unknown kind %q", rule.Name, rule.Kind)
+		}
+		if !slices.Contains(modes, rule.Mode) {
+			return fmt.Errorf("rule %q has mode %q, expected one of %s", rule.Name, rule.Mode, strings.Join(modes, ", "))
+		}
+		if rule.Type == "" {
+			return fmt.Errorf("rule %q must have a type", rule.Name)
+		}
+		return nil
+	}
+
+	for _, rule := range ruleSet.MigrationRules {
+		if err := validate(rule, migrationRuleModes); err != nil {
+			return err
+		}
+	}
+
+	for _, rule := range ruleSet.DomainRules {
+		if err := validate(rule, domainRuleModes); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateJsonSchema(schema string) error {
+	var value any
+	if err := json.Unmarshal([]byte(schema), &value); err != nil {
+		return err
+	}
+
+	switch value.(type) {
+	case map[string]any, bool:
+		return nil
+	default:
+		return errors.New("json schema must be an object or a boolean")
+	}
+}
+
+func validateAvroSchema(schema string) error {
+	var value any
+	if err := json.Unmarshal([]byte(schema), &value); err != nil {
+		return err
+	}
+
+	return validateAvroType(value)
+}
+
+func validateAvroType(value any) error {
+	switch t := value.(type) {
+	case string:
+		if t == "" {
+			return errors.New("avro type name is empty")
+		}
+		return nil
+	case []any:
+		for _, member := range t {
+			if err := validateAvroType(member); err != nil {
+				return err
+			}
+		}
+		return nil
+	case map[string]any:
+		return validateAvroComplexType(t)
+	default:
+		return fmt.Errorf("unexpected avro type %v", t)
+	}
+}
+
+func validateAvroComplexType(t map[string]any) error {
+	typeName, ok := t["type"]
+	if !ok {
+		return errors.New("avro schema is missing \"type\"")
+	}
+
+	switch typeName {
+	case "record", "error":
+		if _, ok := t["name"].(string); !ok {
+			return errors.New("avro record is missing \"name\"")
+		}
+		fields, ok := t["fields"].([]any)
+		if !ok {
+			return errors.New("avro record is missing \"fields\"")
+		}
+		for _, f := range fields {
+			field, ok := f.(map[string]any)
+			if !ok {
+				return errors.New("avro record field must be an object")
+			}
+			if _, ok := field["name"].(string); !ok {
+				return errors.New("avro record field is missing \"name\"")
+			}
+			fieldType, ok := field["type"]
+			if !ok {
+				return fmt.Errorf("avro record field %q is missing \"type\"", field["name"])
+			}
+			if err := validateAvroType(fieldType); err != nil {
+				return err
+			}
+		}
+	case "enum":
+		if _, ok := t["name"].(string); !ok {
+			return errors.New("avro enum is missing \"name\"")
+		}
+		if _, ok := t["symbols"].([]any); !ok {
+			return errors.New("avro enum is missing \"symbols\"")
+		}
+	case "fixed":
+		if _, ok := t["name"].(string); !ok {
+			return errors.New("avro fixed is missing \"name\"")
+		}
+		if _, ok := t["size"].(float64); !ok {
+			return errors.New("avro fixed is missing \"size\"")
+		}
+	case "array":
+		items, ok := t["items"]
+		if !ok {
+			return errors.New("avro array is missing \"items\"")
+		}
+		return validateAvroType(items)
+	case "map":
+		values, ok := t["values"]
+		if !ok {
+			return errors.New("avro map is missing \"values\"")
+		}
+		return validateAvroType(values)
+	default:
+		return validateAvroType(typeName)
+	}
+
+	return nil
+}
+
+var protobufSyntax = regexp.MustCompile(`^\s*syntax\s*=\s*"([^"]*)"\s*;`)
+var protobufDeclaration = regexp.MustCompile(`\b(message|enum|service)\s+[A-Za-z_][A-Za-z0-9_]*\s*\{`)
+var protobufComments = regexp.MustCompile(`(?s)//[^\n]*|/\*.*?\*/`)
+var protobufStrings = regexp.MustCompile(`"(\\.|[^"\\])*"|'(\\.|[^'\\])*'`)
+
+func validateProtobufSchema(schema string) error {
+	source := protobufComments.ReplaceAllString(schema, "")
+
+	if match := protobufSyntax.FindStringSubmatch(source); match != nil && match[1] != "proto2" && match[1] != "proto3" {
+		return fmt.Errorf("unsupported protobuf syntax %q", match[1])
+	}
+
+	source = protobufStrings.ReplaceAllString(source, `""`)
+
+	depth := 0
+	for _, r := range source {
+		switch r {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth < 0 {
+				return errors.New("unbalanced braces in protobuf schema")
+			}
+		}
+	}
+	if depth != 0 {
+		return errors.New("unbalanced braces in protobuf schema")
+	}
+
+	if !protobufDeclaration.MatchString(source) {
+		return errors.New("protobuf schema declares no message, enum or service")
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	jsonSchema := models.NewSchemaDefinition(models.SchemaTypeJson, `{"type":"object"}`, nil)
+	piiRule := models.Rule{Name: "checkEmail", Kind: "CONDITION", Mode: "WRITE", Type: "CEL_FIELD", Tags: []string{"PII"}, Expr: "value.matches(r'.+@.+')"}
+	upgradeRule := models.Rule{Name: "renameField", Kind: "TRANSFORM", Mode: "UPGRADE", Type: "JSONATA", Expr: "$merge([$sift($, function($v, $k) {$k != 'name'}), {'fullName': $.'name'}])"}
+
+	tests := []struct {
+		name       string
+		definition models.SchemaDefinition
+		wantErr    bool
+	}{
+		{name: "json schema", definition: models.NewSchemaDefinition(models.SchemaTypeJson, `{"type":"object"}`, nil)},
+		{name: "json schema is not json", definition: models.NewSchemaDefinition(models.SchemaTypeJson, `{"type":`, nil), wantErr: true},
+		{name: "json schema is not an object", definition: models.NewSchemaDefinition(models.SchemaTypeJson, `"string"`, nil), wantErr: true},
+		{name: "avro record", definition: models.NewSchemaDefinition(models.SchemaTypeAvro, `{"type":"record","name":"User","fields":[{"name":"id","type":"string"},{"name":"tags","type":{"type":"array","items":"string"}}]}`, nil)},
+		{name: "avro primitive", definition: models.NewSchemaDefinition(models.SchemaTypeAvro, `"string"`, nil)},
+		{name: "avro union", definition: models.NewSchemaDefinition(models.SchemaTypeAvro, `["null","string"]`, nil)},
+		{name: "avro record without fields", definition: models.NewSchemaDefinition(models.SchemaTypeAvro, `{"type":"record","name":"User"}`, nil), wantErr: true},
+		{name: "avro enum without symbols", definition: models.NewSchemaDefinition(models.SchemaTypeAvro, `{"type":"enum","name":"Color"}`, nil), wantErr: true},
+		{name: "protobuf message", definition: models.NewSchemaDefinition(models.SchemaTypeProtobuf, "syntax = \"proto3\";\n// a { comment\nmessage User {\n  string id = 1; // }\n  string name = 2 [json_name = \"{\"];\n}\n", nil)},
+		{name: "protobuf unbalanced braces", definition: models.NewSchemaDefinition(models.SchemaTypeProtobuf, "syntax = \"proto3\";\nmessage User {\n  string id = 1;\n", nil), wantErr: true},
+		{name: "protobuf unknown syntax", definition: models.NewSchemaDefinition(models.SchemaTypeProtobuf, "syntax = \"proto4\";\nmessage User {}\n", nil), wantErr: true},
+		{name: "protobuf without declarations", definition: models.NewSchemaDefinition(models.SchemaTypeProtobuf, "syntax = \"proto3\";\n", nil), wantErr: true},
+		{name: "unsupported type", definition: models.NewSchemaDefinition("XML", `<schema/>`, nil), wantErr: true},
+		{name: "empty schema", definition: models.NewSchemaDefinition(models.SchemaTypeJson, " ", nil), wantErr: true},
+		{name: "rule set", definition: jsonSchema.WithRules(&models.Metadata{Sensitive: []string{"email"}}, &models.RuleSet{DomainRules: []models.Rule{piiRule}, MigrationRules: []models.Rule{upgradeRule}})},
+		{name: "rule with wrong mode", definition: jsonSchema.WithRules(nil, &models.RuleSet{MigrationRules: []models.Rule{piiRule}}), wantErr: true},
+		{name: "rule defined twice", definition: jsonSchema.WithRules(nil, &models.RuleSet{DomainRules: []models.Rule{piiRule, piiRule}}), wantErr: true},
+		{name: "reference without subject", definition: models.NewSchemaDefinition(models.SchemaTypeAvro, `"string"`, []models.Reference{{Name: "other.avsc"}}), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.definition)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidSchema)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -2,9 +2,11 @@ package serviceaccount
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/dfds/confluent-gateway/internal/confluent"
 	"github.com/dfds/confluent-gateway/internal/models"
 )
 
@@ -17,11 +19,17 @@ type accountService struct {
 const defaultServiceAccountDescription = "Created by Confluent Gateway"
 
 type serviceAccountRepository interface {
-	GetServiceAccount(capabilityId models.CapabilityId) (*models.ServiceAccount, error)
+	GetApplicationServiceAccount(capabilityId models.CapabilityId, applicationName string) (*models.ServiceAccount, error)
 	CreateServiceAccount(serviceAccount *models.ServiceAccount) error
 	UpdateAclEntry(aclEntry *models.AclEntry) error
+	CreateAclEntry(aclEntry *models.AclEntry) error
 	CreateClusterAccess(clusterAccess *models.ClusterAccess) error
 	UpdateClusterAccess(clusterAccess *models.ClusterAccess) error
+	CreateRoleBinding(roleBinding *models.RoleBinding) error
+	UpdateRoleBinding(roleBinding *models.RoleBinding) error
+	GetAclTemplate(clusterId models.ClusterId) (*models.AclTemplate, error)
+	GetCluster(clusterId models.ClusterId) (*models.Cluster, error)
+	HasRecordNameSubjects(clusterId models.ClusterId) (bool, error)
 }
 
 func NewAccountService(ctx context.Context, confluent Confluent, repo serviceAccountRepository) *accountService {
@@ -32,27 +40,29 @@ func NewAccountService(ctx context.Context, confluent Confluent, repo serviceAcc
 	}
 }
 
-func (h *accountService) GetServiceAccount(capabilityId models.CapabilityId) (*models.ServiceAccount, error) {
-	return h.repo.GetServiceAccount(capabilityId)
+func (h *accountService) GetServiceAccount(capabilityId models.CapabilityId, applicationName string) (*models.ServiceAccount, error) {
+	return h.repo.GetApplicationServiceAccount(capabilityId, applicationName)
 }
 
-func (h *accountService) CreateServiceAccount(capabilityId models.CapabilityId, clusterId models.ClusterId) error {
-	serviceAccountId, err := h.confluent.CreateServiceAccount(h.context, string(capabilityId), defaultServiceAccountDescription)
+func (h *accountService) CreateServiceAccount(capabilityId models.CapabilityId, applicationName string, clusterId models.ClusterId) error {
+	displayName := models.ServiceAccountDisplayName(capabilityId, applicationName)
+	serviceAccountId, err := h.confluent.CreateServiceAccount(h.context, displayName, defaultServiceAccountDescription)
 	if err != nil {
 		return err
 	}
-	return h.CreateServiceAccountDBLink(capabilityId, clusterId, serviceAccountId)
+	return h.CreateServiceAccountDBLink(capabilityId, applicationName, clusterId, serviceAccountId)
 }
 
-func (h *accountService) FindServiceAccountAndCreateDBLink(capabilityId models.CapabilityId, clusterId models.ClusterId) error {
-	serviceAccountId, err := h.confluent.GetServiceAccount(h.context, string(capabilityId))
+func (h *accountService) FindServiceAccountAndCreateDBLink(capabilityId models.CapabilityId, applicationName string, clusterId models.ClusterId) error {
+	displayName := models.ServiceAccountDisplayName(capabilityId, applicationName)
+	serviceAccountId, err := h.confluent.GetServiceAccount(h.context, displayName)
 	if err != nil {
 		return err
 	}
-	return h.CreateServiceAccountDBLink(capabilityId, clusterId, serviceAccountId)
+	return h.CreateServiceAccountDBLink(capabilityId, applicationName, clusterId, serviceAccountId)
 }
 
-func (h *accountService) CreateServiceAccountDBLink(capabilityId models.CapabilityId, clusterId models.ClusterId, serviceAccountId models.ServiceAccountId) error {
+func (h *accountService) CreateServiceAccountDBLink(capabilityId models.CapabilityId, applicationName string, clusterId models.ClusterId, serviceAccountId models.ServiceAccountId) error {
 
 	users, err := h.confluent.GetConfluentInternalUsers(h.context)
 	if err != nil {
@@ -75,17 +85,46 @@ func (h *accountService) CreateServiceAccountDBLink(capabilityId models.Capabili
 
 	userAccountId := models.MakeUserAccountId(user.Id)
 
+	clusterAccess, err := h.newClusterAccess(serviceAccountId, userAccountId, clusterId, capabilityId)
+	if err != nil {
+		return err
+	}
+
 	newServiceAccount := &models.ServiceAccount{
 		Id:              serviceAccountId,
 		UserAccountId:   userAccountId,
 		CapabilityId:    capabilityId,
-		ClusterAccesses: []models.ClusterAccess{*models.NewClusterAccess(serviceAccountId, userAccountId, clusterId, capabilityId)},
+		ApplicationName: applicationName,
+		ClusterAccesses: []models.ClusterAccess{*clusterAccess},
 		CreatedAt:       time.Now(),
 	}
 
 	return h.repo.CreateServiceAccount(newServiceAccount)
 }
 
+// newClusterAccess gives the service account access to the cluster through role bindings when the cluster is in
+// RBAC mode, and through the ACL template of the cluster otherwise.
+func (h *accountService) newClusterAccess(serviceAccountId models.ServiceAccountId, userAccountId models.UserAccountId, clusterId models.ClusterId, capabilityId models.CapabilityId) (*models.ClusterAccess, error) {
+	cluster, err := h.repo.GetCluster(clusterId)
+	if err != nil {
+		return nil, err
+	}
+	if cluster == nil {
+		return nil, fmt.Errorf("cluster '%s' not found", clusterId)
+	}
+
+	if cluster.UsesRbac() {
+		return models.NewRoleBindingClusterAccess(serviceAccountId, userAccountId, cluster, capabilityId), nil
+	}
+
+	template, err := h.repo.GetAclTemplate(clusterId)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewClusterAccess(serviceAccountId, userAccountId, clusterId, capabilityId, template), nil
+}
+
 func (h *accountService) DeleteClusterApiKey(clusterAccess *models.ClusterAccess) error {
 	return h.confluent.DeleteClusterApiKey(h.context, clusterAccess.ClusterId, clusterAccess.ServiceAccountId)
 }
@@ -94,8 +133,11 @@ func (h *accountService) DeleteSchemaRegistryApiKey(clusterAccess *models.Cluste
 	return h.confluent.DeleteSchemaRegistryApiKey(h.context, clusterAccess.ClusterId, clusterAccess.ServiceAccountId)
 }
 
-func (h *accountService) GetOrCreateClusterAccess(capabilityId models.CapabilityId, clusterId models.ClusterId) (*models.ClusterAccess, error) {
-	serviceAccount, err := h.repo.GetServiceAccount(capabilityId)
+// GetOrCreateClusterAccess returns the cluster access of the service account of the application, creating it from
+// the ACL template of the cluster when there is none, with the ACL of the profiles not yet enabled added pending
+// creation.
+func (h *accountService) GetOrCreateClusterAccess(capabilityId models.CapabilityId, applicationName string, clusterId models.ClusterId, profiles []models.AccessProfile) (*models.ClusterAccess, error) {
+	serviceAccount, err := h.repo.GetApplicationServiceAccount(capabilityId, applicationName)
 	if err != nil {
 		return nil, err
 	}
@@ -106,18 +148,38 @@ func (h *accountService) GetOrCreateClusterAccess(capabilityId models.Capability
 	clusterAccess, hasClusterAccess := serviceAccount.TryGetClusterAccess(clusterId)
 
 	if !hasClusterAccess {
-		clusterAccess = models.NewClusterAccess(serviceAccount.Id, serviceAccount.UserAccountId, clusterId, capabilityId)
+		clusterAccess, err = h.newClusterAccess(serviceAccount.Id, serviceAccount.UserAccountId, clusterId, capabilityId)
+		if err != nil {
+			return nil, err
+		}
+
+		clusterAccess.EnableProfiles(profiles, capabilityId)
 		serviceAccount.ClusterAccesses = append(serviceAccount.ClusterAccesses, *clusterAccess)
 
 		if err = h.repo.CreateClusterAccess(clusterAccess); err != nil {
 			return nil, err
 		}
+		return clusterAccess, nil
+	}
+
+	if clusterAccess.HasProfiles(profiles) {
+		return clusterAccess, nil
+	}
+
+	for _, entry := range clusterAccess.EnableProfiles(profiles, capabilityId) {
+		if err := h.repo.CreateAclEntry(&entry); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := h.repo.UpdateClusterAccess(clusterAccess); err != nil {
+		return nil, err
 	}
 	return clusterAccess, nil
 }
 
-func (h *accountService) GetClusterAccess(capabilityId models.CapabilityId, clusterId models.ClusterId) (*models.ClusterAccess, error) {
-	serviceAccount, err := h.repo.GetServiceAccount(capabilityId)
+func (h *accountService) GetClusterAccess(capabilityId models.CapabilityId, applicationName string, clusterId models.ClusterId) (*models.ClusterAccess, error) {
+	serviceAccount, err := h.repo.GetApplicationServiceAccount(capabilityId, applicationName)
 	if err != nil {
 		return nil, err
 	}
@@ -133,14 +195,53 @@ func (h *accountService) GetClusterAccess(capabilityId models.CapabilityId, clus
 	return clusterAccess, nil
 }
 
-func (h *accountService) CreateAclEntry(clusterId models.ClusterId, userAccountId models.UserAccountId, entry *models.AclEntry) error {
-	if err := h.confluent.CreateACLEntry(h.context, clusterId, userAccountId, entry.AclDefinition); err != nil {
-		return err
+// CreateAclEntries creates the entries in Confluent and marks the ones that were created, returning how many they
+// are. The error names the entries that failed.
+func (h *accountService) CreateAclEntries(clusterId models.ClusterId, userAccountId models.UserAccountId, entries []*models.AclEntry) (int, error) {
+	definitions := make([]models.AclDefinition, len(entries))
+	for i, entry := range entries {
+		definitions[i] = entry.AclDefinition
 	}
 
-	entry.Created()
+	results, err := h.confluent.CreateACLEntries(h.context, clusterId, userAccountId, definitions)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	var errs []error
+	for i, result := range results {
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("unable to create ACL entry with definition %s, error: %w", result.AclDefinition, result.Err))
+			continue
+		}
+
+		entries[i].Created()
+		if err := h.repo.UpdateAclEntry(entries[i]); err != nil {
+			return created, err
+		}
+		created++
+	}
+
+	return created, errors.Join(errs...)
+}
+
+// CreateRoleBindings creates the role bindings in Confluent, unless the service account already has them, and marks
+// them as created.
+func (h *accountService) CreateRoleBindings(serviceAccountId models.ServiceAccountId, roleBindings []*models.RoleBinding) error {
+	for _, roleBinding := range roleBindings {
+		confluentId, err := h.confluent.EnsureRoleBinding(h.context, serviceAccountId, roleBinding.RoleBindingDefinition)
+		if err != nil {
+			return fmt.Errorf("unable to create role binding %s, error: %w", roleBinding.RoleBindingDefinition, err)
+		}
+
+		roleBinding.Created(confluentId)
+		if err := h.repo.UpdateRoleBinding(roleBinding); err != nil {
+			return err
+		}
+	}
 
-	return h.repo.UpdateAclEntry(entry)
+	return nil
 }
 
 func (h *accountService) CreateClusterApiKey(clusterAccess *models.ClusterAccess) (models.ApiKey, error) {
@@ -168,10 +269,42 @@ func (h *accountService) CreateSchemaRegistryApiKey(clusterAccess *models.Cluste
 
 }
 
-func (h *accountService) CreateServiceAccountRoleBinding(clusterAccess *models.ClusterAccess) error {
-	err := h.confluent.CreateServiceAccountRoleBinding(h.context, clusterAccess.ServiceAccountId, clusterAccess.ClusterId)
+// CreateSchemaRegistryRoleBindings binds the roles on the schema registry subjects of the capability and of the topics
+// it has been granted to the service account, and revokes the role on all subjects it was given before its bindings
+// were scoped to the capability. Subjects named after their record rather than their topic cannot be scoped, so the
+// role on all subjects is kept while the cluster has any.
+func (h *accountService) CreateSchemaRegistryRoleBindings(clusterAccess *models.ClusterAccess, capabilityId models.CapabilityId) error {
+	cluster, err := h.repo.GetCluster(clusterAccess.ClusterId)
 	if err != nil {
 		return err
 	}
-	return nil
+	if !cluster.HasSchemaRegistryIds() {
+		return confluent.ErrMissingSchemaRegistryIds
+	}
+
+	added := clusterAccess.AddRoleBindings(models.SchemaRegistryRoleBindings(cluster, capabilityId, clusterAccess.GetGrantedTopics()))
+	for i := range added {
+		if err := h.repo.CreateRoleBinding(&added[i]); err != nil {
+			return err
+		}
+	}
+
+	pending := clusterAccess.GetRoleBindingsPendingCreation()
+	roleBindings := make([]*models.RoleBinding, len(pending))
+	for i := range pending {
+		roleBindings[i] = &pending[i]
+	}
+	if err := h.CreateRoleBindings(clusterAccess.ServiceAccountId, roleBindings); err != nil {
+		return err
+	}
+
+	if len(added) == 0 {
+		return nil
+	}
+
+	hasRecordNameSubjects, err := h.repo.HasRecordNameSubjects(cluster.ClusterId)
+	if err != nil || hasRecordNameSubjects {
+		return err
+	}
+	return h.confluent.RevokeRoleBinding(h.context, clusterAccess.ServiceAccountId, models.AllSubjectsRoleBinding(cluster))
 }
@@ -22,15 +22,16 @@ func NewStepContext(logger logging.Logger, account AccountService, vault VaultSe
 }
 
 type AccountService interface {
-	GetServiceAccount(models.CapabilityId) (*models.ServiceAccount, error)
-	CreateServiceAccount(models.CapabilityId, models.ClusterId) error
-	FindServiceAccountAndCreateDBLink(models.CapabilityId, models.ClusterId) error
-	GetOrCreateClusterAccess(models.CapabilityId, models.ClusterId) (*models.ClusterAccess, error)
-	GetClusterAccess(models.CapabilityId, models.ClusterId) (*models.ClusterAccess, error)
-	CreateAclEntry(models.ClusterId, models.UserAccountId, *models.AclEntry) error
+	GetServiceAccount(models.CapabilityId, string) (*models.ServiceAccount, error)
+	CreateServiceAccount(models.CapabilityId, string, models.ClusterId) error
+	FindServiceAccountAndCreateDBLink(models.CapabilityId, string, models.ClusterId) error
+	GetOrCreateClusterAccess(models.CapabilityId, string, models.ClusterId, []models.AccessProfile) (*models.ClusterAccess, error)
+	GetClusterAccess(models.CapabilityId, string, models.ClusterId) (*models.ClusterAccess, error)
+	CreateAclEntries(models.ClusterId, models.UserAccountId, []*models.AclEntry) (int, error)
+	CreateRoleBindings(models.ServiceAccountId, []*models.RoleBinding) error
 	CreateClusterApiKey(*models.ClusterAccess) (models.ApiKey, error)
 	CreateSchemaRegistryApiKey(clusterAccess *models.ClusterAccess) (models.ApiKey, error)
-	CreateServiceAccountRoleBinding(clusterAccess *models.ClusterAccess) error
+	CreateSchemaRegistryRoleBindings(clusterAccess *models.ClusterAccess, capabilityId models.CapabilityId) error
 	CountClusterApiKeys(clusterAccess *models.ClusterAccess) (int, error)
 	CountSchemaRegistryApiKeys(clusterAccess *models.ClusterAccess) (int, error)
 	DeleteClusterApiKey(clusterAccess *models.ClusterAccess) error
@@ -60,7 +61,7 @@ func (c *StepContext) LogWarning(format string, args ...string) {
 }
 
 func (c *StepContext) HasServiceAccount() bool {
-	account, err := c.account.GetServiceAccount(c.input.CapabilityId)
+	account, err := c.account.GetServiceAccount(c.input.CapabilityId, c.input.ApplicationName)
 	if err != nil {
 		c.LogError(err, fmt.Sprintf("encountered error when checking if ServiceAccount exists for CapabilityId %s", c.input.CapabilityId))
 		return false
@@ -69,11 +70,11 @@ func (c *StepContext) HasServiceAccount() bool {
 }
 
 func (c *StepContext) CreateServiceAccount() error {
-	return c.account.CreateServiceAccount(c.input.CapabilityId, c.input.ClusterId)
+	return c.account.CreateServiceAccount(c.input.CapabilityId, c.input.ApplicationName, c.input.ClusterId)
 }
 
 func (c *StepContext) CreateServiceAccountDbLink() error {
-	return c.account.FindServiceAccountAndCreateDBLink(c.input.CapabilityId, c.input.ClusterId)
+	return c.account.FindServiceAccountAndCreateDBLink(c.input.CapabilityId, c.input.ApplicationName, c.input.ClusterId)
 }
 
 func (c *StepContext) GetInputCapabilityId() models.CapabilityId {
@@ -81,14 +82,14 @@ func (c *StepContext) GetInputCapabilityId() models.CapabilityId {
 }
 
 func (c *StepContext) HasClusterAccessWithValidAcls() bool {
-	serviceAccount, _ := c.account.GetServiceAccount(c.input.CapabilityId)
+	serviceAccount, _ := c.account.GetServiceAccount(c.input.CapabilityId, c.input.ApplicationName)
 	if serviceAccount != nil {
 		clusterAccess, hasClusterAccess := serviceAccount.TryGetClusterAccess(c.input.ClusterId)
-		if !hasClusterAccess {
+		if !hasClusterAccess || !clusterAccess.HasProfiles(c.input.Profiles) || len(clusterAccess.GetRoleBindingsPendingCreation()) > 0 {
 			return false
 		}
 		for _, entry := range clusterAccess.Acl {
-			if !entry.IsValid() {
+			if !entry.IsValid() && !entry.IsAwaitingApproval() {
 				return false
 			}
 		}
@@ -98,15 +99,27 @@ func (c *StepContext) HasClusterAccessWithValidAcls() bool {
 }
 
 func (c *StepContext) GetOrCreateClusterAccess() (*models.ClusterAccess, error) {
-	return c.account.GetOrCreateClusterAccess(c.input.CapabilityId, c.input.ClusterId)
+	return c.account.GetOrCreateClusterAccess(c.input.CapabilityId, c.input.ApplicationName, c.input.ClusterId, c.input.Profiles)
 }
 
-func (c *StepContext) CreateAclEntry(clusterAccess *models.ClusterAccess, nextEntry models.AclEntry) error {
-	return c.account.CreateAclEntry(c.input.ClusterId, clusterAccess.UserAccountId, &nextEntry)
+func (c *StepContext) CreateAclEntries(clusterAccess *models.ClusterAccess, entries []models.AclEntry) (int, error) {
+	pending := make([]*models.AclEntry, len(entries))
+	for i := range entries {
+		pending[i] = &entries[i]
+	}
+	return c.account.CreateAclEntries(c.input.ClusterId, clusterAccess.UserAccountId, pending)
+}
+
+func (c *StepContext) CreateRoleBindings(clusterAccess *models.ClusterAccess, roleBindings []models.RoleBinding) error {
+	pending := make([]*models.RoleBinding, len(roleBindings))
+	for i := range roleBindings {
+		pending[i] = &roleBindings[i]
+	}
+	return c.account.CreateRoleBindings(clusterAccess.ServiceAccountId, pending)
 }
 
 func (c *StepContext) GetClusterAccess() (*models.ClusterAccess, error) {
-	return c.account.GetClusterAccess(c.input.CapabilityId, c.input.ClusterId)
+	return c.account.GetClusterAccess(c.input.CapabilityId, c.input.ApplicationName, c.input.ClusterId)
 }
 
 func (c *StepContext) HasClusterApiKey(clusterAccess *models.ClusterAccess) (bool, error) {
@@ -120,11 +133,11 @@ func (c *StepContext) HasSchemaRegistryApiKey(clusterAccess *models.ClusterAcces
 }
 
 func (c *StepContext) HasClusterApiKeyInVault(clusterAccess *models.ClusterAccess) (bool, error) {
-	return c.vault.QueryClusterApiKey(c.input.CapabilityId, clusterAccess.ClusterId)
+	return c.vault.QueryClusterApiKey(c.input.CapabilityId, c.input.ApplicationName, clusterAccess.ClusterId)
 }
 
 func (c *StepContext) HasSchemaRegistryApiKeyInVault(clusterAccess *models.ClusterAccess) (bool, error) {
-	return c.vault.QuerySchemaRegistryApiKey(c.input.CapabilityId, clusterAccess.ClusterId)
+	return c.vault.QuerySchemaRegistryApiKey(c.input.CapabilityId, c.input.ApplicationName, clusterAccess.ClusterId)
 }
 
 func (c *StepContext) CreateClusterApiKeyAndStoreInVault(clusterAccess *models.ClusterAccess, shouldOverwriteKey bool) error {
@@ -133,7 +146,7 @@ func (c *StepContext) CreateClusterApiKeyAndStoreInVault(clusterAccess *models.C
 		return err
 	}
 
-	return c.vault.StoreClusterApiKey(c.input.CapabilityId, clusterAccess.ClusterId, newKey, shouldOverwriteKey)
+	return c.vault.StoreClusterApiKey(c.input.CapabilityId, c.input.ApplicationName, clusterAccess.ClusterId, newKey, shouldOverwriteKey)
 
 }
 func (c *StepContext) CreateSchemaRegistryApiKeyAndStoreInVault(clusterAccess *models.ClusterAccess, shouldOverwriteKey bool) error {
@@ -142,7 +155,7 @@ func (c *StepContext) CreateSchemaRegistryApiKeyAndStoreInVault(clusterAccess *m
 		return err
 	}
 
-	return c.vault.StoreSchemaRegistryApiKey(c.input.CapabilityId, clusterAccess.ClusterId, newKey, shouldOverwriteKey)
+	return c.vault.StoreSchemaRegistryApiKey(c.input.CapabilityId, c.input.ApplicationName, clusterAccess.ClusterId, newKey, shouldOverwriteKey)
 }
 
 func (c *StepContext) DeleteClusterApiKey(clusterAccess *models.ClusterAccess) error {
@@ -153,14 +166,15 @@ func (c *StepContext) DeleteSchemaRegistryApiKey(clusterAccess *models.ClusterAc
 	return c.account.DeleteSchemaRegistryApiKey(clusterAccess)
 }
 
-func (c *StepContext) CreateServiceAccountRoleBinding(clusterAccess *models.ClusterAccess) error {
-	return c.account.CreateServiceAccountRoleBinding(clusterAccess)
+func (c *StepContext) CreateSchemaRegistryRoleBindings(clusterAccess *models.ClusterAccess) error {
+	return c.account.CreateSchemaRegistryRoleBindings(clusterAccess, c.input.CapabilityId)
 }
 
 func (c *StepContext) RaiseServiceAccountAccessGranted() error {
 	event := &ServiceAccountAccessGranted{
-		CapabilityId:   string(c.input.CapabilityId),
-		KafkaClusterId: string(c.input.ClusterId),
+		CapabilityId:    string(c.input.CapabilityId),
+		KafkaClusterId:  string(c.input.ClusterId),
+		ApplicationName: c.input.ApplicationName,
 	}
 	return c.outbox.Produce(event)
 }
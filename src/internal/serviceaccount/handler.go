@@ -24,9 +24,20 @@ func (h *handler) Handle(ctx context.Context, msgContext messaging.MessageContex
 	switch message := msgContext.Message().(type) {
 
 	case *ServiceAccountAccessRequested:
+		if err := models.ValidateApplicationName(message.ApplicationName); err != nil {
+			return err
+		}
+
+		profiles, err := models.ParseAccessProfiles(message.Profiles)
+		if err != nil {
+			return err
+		}
+
 		input := ProcessInput{
-			CapabilityId: models.CapabilityId(message.GetCapabilityId()),
-			ClusterId:    models.ClusterId(message.GetClusterId()),
+			CapabilityId:    models.CapabilityId(message.GetCapabilityId()),
+			ApplicationName: message.ApplicationName,
+			ClusterId:       models.ClusterId(message.GetClusterId()),
+			Profiles:        profiles,
 		}
 		return h.process.Process(ctx, input)
 
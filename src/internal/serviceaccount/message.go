@@ -3,6 +3,9 @@ package serviceaccount
 type ServiceAccountAccessRequested struct {
 	CapabilityId   string `json:"capabilityId"`
 	KafkaClusterId string `json:"kafkaClusterId"`
+	// ApplicationName asks for the access of a service account of its own for an application of the capability.
+	ApplicationName string   `json:"applicationName,omitempty"`
+	Profiles        []string `json:"profiles,omitempty"`
 }
 
 func (r *ServiceAccountAccessRequested) GetCapabilityId() string {
@@ -18,8 +21,9 @@ func (r *ServiceAccountAccessRequested) PartitionKey() string {
 }
 
 type ServiceAccountAccessGranted struct {
-	CapabilityId   string `json:"capabilityId"`
-	KafkaClusterId string `json:"kafkaClusterId"`
+	CapabilityId    string `json:"capabilityId"`
+	KafkaClusterId  string `json:"kafkaClusterId"`
+	ApplicationName string `json:"applicationName,omitempty"`
 }
 
 func (r *ServiceAccountAccessGranted) GetCapabilityId() string {
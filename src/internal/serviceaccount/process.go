@@ -9,6 +9,8 @@ import (
 	proc "github.com/dfds/confluent-gateway/internal/process"
 	"github.com/dfds/confluent-gateway/internal/vault"
 	"github.com/dfds/confluent-gateway/logging"
+	"strconv"
+	"time"
 )
 
 type logger interface {
@@ -37,18 +39,31 @@ func NewProcess(logger logging.Logger, database models.Database, confluent Confl
 
 type ProcessInput struct {
 	CapabilityId models.CapabilityId
-	ClusterId    models.ClusterId
+	// ApplicationName selects the service account of an application of the capability, or the default service
+	// account of the capability when empty.
+	ApplicationName string
+	ClusterId       models.ClusterId
+	// Profiles are enabled on the cluster access in addition to the ACL template of the cluster.
+	Profiles []models.AccessProfile
 }
 
 func (p *process) Process(ctx context.Context, input ProcessInput) error {
 	session := p.database.NewSession(ctx)
+	processId := fmt.Sprintf("%s/%s", input.CapabilityId, input.ClusterId)
+	if input.ApplicationName != "" {
+		processId = fmt.Sprintf("%s/%s/%s", input.CapabilityId, input.ApplicationName, input.ClusterId)
+	}
+
+	// the steps start over from the database on every attempt, so failing calls to Confluent can safely be retried
+	retry := proc.WithRetry(3, proc.ExponentialBackoff(time.Second, 5*time.Second))
 
 	return proc.PrepareSteps[*StepContext]().
-		Step(ensureServiceAccountStep).
-		Step(ensureServiceAccountAclStep).
-		Step(ensureServiceAccountClusterAccessStep).
-		Step(ensureServiceAccountSchemaRegistryAccessStep).
+		Step(ensureServiceAccountStep, retry).
+		Step(ensureServiceAccountAclStep, retry).Until((*StepContext).HasClusterAccessWithValidAcls).
+		Step(ensureServiceAccountClusterAccessStep, retry).
+		Step(ensureServiceAccountSchemaRegistryAccessStep, retry).
 		Step(raiseServiceAccountAccessGrantedStep).
+		RecordTo(proc.NewStepRecorder(session, "service-account", processId)).
 		Run(func(step func(*StepContext) error) error {
 			return session.Transaction(func(tx models.Transaction) error {
 				stepContext := p.getStepContext(ctx, tx, input)
@@ -106,7 +121,8 @@ type EnsureServiceAccountAclStep interface {
 	HasClusterAccessWithValidAcls() bool
 	GetInputCapabilityId() models.CapabilityId
 	GetOrCreateClusterAccess() (*models.ClusterAccess, error)
-	CreateAclEntry(clusterAccess *models.ClusterAccess, nextEntry models.AclEntry) error
+	CreateAclEntries(clusterAccess *models.ClusterAccess, entries []models.AclEntry) (int, error)
+	CreateRoleBindings(clusterAccess *models.ClusterAccess, roleBindings []models.RoleBinding) error
 }
 
 func ensureServiceAccountAclStep(step *StepContext) error {
@@ -123,6 +139,13 @@ func ensureServiceAccountAclStep(step *StepContext) error {
 			return err
 		}
 
+		// a cluster in RBAC mode grants the access through role bindings, with ACL entries left only for profiles
+		if roleBindings := clusterAccess.GetRoleBindingsPendingCreation(); len(roleBindings) > 0 {
+			if err := step.CreateRoleBindings(clusterAccess, roleBindings); err != nil {
+				return err
+			}
+		}
+
 		entries := clusterAccess.GetAclPendingCreation()
 		if len(entries) == 0 {
 			step.LogDebug("found no ACL pending creation")
@@ -130,13 +153,13 @@ func ensureServiceAccountAclStep(step *StepContext) error {
 			return nil
 		}
 
-		for _, entry := range entries {
-			err = step.CreateAclEntry(clusterAccess, entry)
-			if err != nil {
-				return fmt.Errorf("unable to create ACL entry with definition %s, error: %w", entry.AclDefinition, err)
-			}
+		created, err := step.CreateAclEntries(clusterAccess, entries)
+		if err != nil && created > 0 {
+			// commit the entries that were created, the step runs again for the ones that failed
+			step.LogWarning("created {Created} of {Count} ACL entries, retrying the rest: {Reason}", strconv.Itoa(created), strconv.Itoa(len(entries)), err.Error())
+			return nil
 		}
-		return nil
+		return err
 	}
 	return inner(step)
 }
@@ -192,7 +215,7 @@ type EnsureServiceAccountSchemaRegistryAccessStep interface {
 	GetClusterAccess() (*models.ClusterAccess, error)
 	HasSchemaRegistryApiKey(clusterAccess *models.ClusterAccess) (bool, error)
 	HasSchemaRegistryApiKeyInVault(clusterAccess *models.ClusterAccess) (bool, error)
-	CreateServiceAccountRoleBinding(*models.ClusterAccess) error
+	CreateSchemaRegistryRoleBindings(*models.ClusterAccess) error
 	CreateSchemaRegistryApiKeyAndStoreInVault(clusterAccess *models.ClusterAccess, shouldOverwriteKey bool) error
 	DeleteSchemaRegistryApiKey(clusterAccess *models.ClusterAccess) error
 }
@@ -206,7 +229,7 @@ func ensureServiceAccountSchemaRegistryAccessStep(step *StepContext) error {
 			return err
 		}
 
-		err = step.CreateServiceAccountRoleBinding(clusterAccess)
+		err = step.CreateSchemaRegistryRoleBindings(clusterAccess)
 		if err != nil {
 			if errors.Is(err, confluent.ErrMissingSchemaRegistryIds) {
 				step.LogError(err, "unable to setup schema registry access")
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dfds/confluent-gateway/internal/confluent"
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/dfds/confluent-gateway/internal/vault"
+	"github.com/dfds/confluent-gateway/logging"
+	uuid "github.com/satori/go.uuid"
+)
+
+var ErrRoleBindingNotFound = errors.New("role binding not found")
+
+type AccessServiceInterface interface {
+	GetAccess(ctx context.Context, capabilityId models.CapabilityId, applicationName string) (*models.AccessInfo, error)
+	ListRoleBindings(ctx context.Context, capabilityId models.CapabilityId) ([]models.RoleBindingInfo, error)
+	DeleteRoleBinding(ctx context.Context, capabilityId models.CapabilityId, roleBindingId string) error
+}
+
+type AccessRepository interface {
+	GetApplicationServiceAccount(capabilityId models.CapabilityId, applicationName string) (*models.ServiceAccount, error)
+	GetServiceAccounts(capabilityId models.CapabilityId) ([]*models.ServiceAccount, error)
+	DeleteRoleBinding(roleBinding *models.RoleBinding) error
+}
+
+type AccessService struct {
+	Logger          logging.Logger
+	Repository      AccessRepository
+	ConfluentClient confluent.ConfluentClient
+	Vault           vault.Vault
+}
+
+func NewAccessService(logger logging.Logger, repository AccessRepository, confluentClient confluent.ConfluentClient, vault vault.Vault) *AccessService {
+	return &AccessService{
+		Logger:          logger,
+		Repository:      repository,
+		ConfluentClient: confluentClient,
+		Vault:           vault,
+	}
+}
+
+// GetAccess returns the access of the service account of an application of the capability, or of its default service
+// account when the application name is empty.
+func (s *AccessService) GetAccess(ctx context.Context, capabilityId models.CapabilityId, applicationName string) (*models.AccessInfo, error) {
+	serviceAccount, err := s.Repository.GetApplicationServiceAccount(capabilityId, applicationName)
+	if err != nil {
+		return nil, err
+	}
+
+	info := models.NewAccessInfo(serviceAccount)
+
+	for i := range serviceAccount.ClusterAccesses {
+		clusterAccess := &serviceAccount.ClusterAccesses[i]
+
+		apiKeys, err := s.getApiKeyStatus(ctx, serviceAccount, clusterAccess)
+		if err != nil {
+			return nil, err
+		}
+
+		info.ClusterAccesses = append(info.ClusterAccesses, models.NewClusterAccessInfo(clusterAccess, *apiKeys))
+	}
+
+	return &info, nil
+}
+
+// ListRoleBindings returns the role bindings of all the service accounts of the capability.
+func (s *AccessService) ListRoleBindings(_ context.Context, capabilityId models.CapabilityId) ([]models.RoleBindingInfo, error) {
+	serviceAccounts, err := s.Repository.GetServiceAccounts(capabilityId)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := []models.RoleBindingInfo{}
+	for _, serviceAccount := range serviceAccounts {
+		for _, clusterAccess := range serviceAccount.ClusterAccesses {
+			for i := range clusterAccess.RoleBindings {
+				if clusterAccess.RoleBindings[i].IsDeleted() {
+					continue
+				}
+				info := models.NewRoleBindingInfo(&clusterAccess.RoleBindings[i])
+				info.ClusterId = clusterAccess.ClusterId
+				info.ApplicationName = serviceAccount.ApplicationName
+				infos = append(infos, info)
+			}
+		}
+	}
+
+	return infos, nil
+}
+
+// DeleteRoleBinding removes a role binding of the capability from Confluent and marks it as deleted.
+func (s *AccessService) DeleteRoleBinding(ctx context.Context, capabilityId models.CapabilityId, roleBindingId string) error {
+	roleBinding, err := s.findRoleBinding(capabilityId, roleBindingId)
+	if err != nil {
+		return err
+	}
+
+	if roleBinding.IsCreated() && roleBinding.ConfluentId != "" {
+		if err := s.ConfluentClient.DeleteRoleBinding(ctx, roleBinding.ConfluentId); err != nil {
+			return err
+		}
+	}
+
+	return s.Repository.DeleteRoleBinding(roleBinding)
+}
+
+func (s *AccessService) findRoleBinding(capabilityId models.CapabilityId, roleBindingId string) (*models.RoleBinding, error) {
+	id, err := uuid.FromString(roleBindingId)
+	if err != nil {
+		return nil, ErrRoleBindingNotFound
+	}
+
+	serviceAccounts, err := s.Repository.GetServiceAccounts(capabilityId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, serviceAccount := range serviceAccounts {
+		for _, clusterAccess := range serviceAccount.ClusterAccesses {
+			for i := range clusterAccess.RoleBindings {
+				if clusterAccess.RoleBindings[i].Id == id && !clusterAccess.RoleBindings[i].IsDeleted() {
+					return &clusterAccess.RoleBindings[i], nil
+				}
+			}
+		}
+	}
+
+	return nil, ErrRoleBindingNotFound
+}
+
+func (s *AccessService) getApiKeyStatus(ctx context.Context, serviceAccount *models.ServiceAccount, clusterAccess *models.ClusterAccess) (*models.ApiKeyStatus, error) {
+	clusterKeys, err := s.ConfluentClient.CountClusterApiKeys(ctx, clusterAccess.ServiceAccountId, clusterAccess.ClusterId)
+	if err != nil {
+		s.Logger.Error(err, "failed to count cluster api keys for {ClusterId}", string(clusterAccess.ClusterId))
+		return nil, err
+	}
+
+	schemaRegistryKeys, err := s.ConfluentClient.CountSchemaRegistryApiKeys(ctx, clusterAccess.ServiceAccountId, clusterAccess.ClusterId)
+	if err != nil && !errors.Is(err, confluent.ErrSchemaRegistryIdIsEmpty) {
+		s.Logger.Error(err, "failed to count schema registry api keys for {ClusterId}", string(clusterAccess.ClusterId))
+		return nil, err
+	}
+
+	clusterKeyStored, err := s.queryVault(ctx, serviceAccount, clusterAccess.ClusterId, vault.OperationDestinationCluster)
+	if err != nil {
+		return nil, err
+	}
+
+	schemaRegistryKeyStored, err := s.queryVault(ctx, serviceAccount, clusterAccess.ClusterId, vault.OperationDestinationSchemaRegistry)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ApiKeyStatus{
+		ClusterApiKeyInConfluent:        clusterKeys > 0,
+		ClusterApiKeyInVault:            clusterKeyStored,
+		SchemaRegistryApiKeyInConfluent: schemaRegistryKeys > 0,
+		SchemaRegistryApiKeyInVault:     schemaRegistryKeyStored,
+	}, nil
+}
+
+func (s *AccessService) queryVault(ctx context.Context, serviceAccount *models.ServiceAccount, clusterId models.ClusterId, destination vault.OperationDestination) (bool, error) {
+	found, err := s.Vault.QueryApiKey(ctx, vault.Input{
+		OperationDestination: destination,
+		CapabilityId:         serviceAccount.CapabilityId,
+		ApplicationName:      serviceAccount.ApplicationName,
+		ClusterId:            clusterId,
+	})
+	if err != nil {
+		s.Logger.Error(err, "failed to query vault for {OperationDestination} api key on {ClusterId}", string(destination), string(clusterId))
+		return false, err
+	}
+
+	return found, nil
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dfds/confluent-gateway/internal/confluent"
+	"github.com/dfds/confluent-gateway/internal/mocks"
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/dfds/confluent-gateway/internal/vault"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetAccess(t *testing.T) {
+	now := time.Now()
+	mockClient := new(mocks.MockClient)
+	repository := &accessRepositoryStub{ServiceAccount: &models.ServiceAccount{
+		Id:           "sa-123",
+		CapabilityId: "some-capability",
+		ClusterAccesses: []models.ClusterAccess{
+			{
+				Id:               uuid.NewV4(),
+				ClusterId:        "abc-1234",
+				ServiceAccountId: "sa-123",
+				Acl: []models.AclEntry{
+					{Id: uuid.NewV4(), CreatedAt: &now},
+					{Id: uuid.NewV4()},
+				},
+			},
+		},
+	}}
+	vaultStub := &vaultStub{Stored: map[vault.OperationDestination]bool{vault.OperationDestinationCluster: true}}
+
+	mockClient.On("CountClusterApiKeys", mock.Anything, models.ServiceAccountId("sa-123"), models.ClusterId("abc-1234")).Return(1, nil)
+	mockClient.On("CountSchemaRegistryApiKeys", mock.Anything, models.ServiceAccountId("sa-123"), models.ClusterId("abc-1234")).Return(0, confluent.ErrSchemaRegistryIdIsEmpty)
+
+	accessService := NewAccessService(new(mocks.MockLogger), repository, mockClient, vaultStub)
+
+	access, err := accessService.GetAccess(context.TODO(), "some-capability", "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.ServiceAccountId("sa-123"), access.ServiceAccountId)
+	assert.Len(t, access.ClusterAccesses, 1)
+	assert.Equal(t, models.ApiKeyStatus{ClusterApiKeyInConfluent: true, ClusterApiKeyInVault: true}, access.ClusterAccesses[0].ApiKeys)
+	assert.Equal(t, models.AclEntryStatusCreated, access.ClusterAccesses[0].Acl[0].Status)
+	assert.Equal(t, models.AclEntryStatusPending, access.ClusterAccesses[0].Acl[1].Status)
+	mockClient.AssertExpectations(t)
+}
+
+func TestListRoleBindings(t *testing.T) {
+	now := time.Now()
+	repository := &accessRepositoryStub{ServiceAccount: &models.ServiceAccount{
+		Id:              "sa-123",
+		CapabilityId:    "some-capability",
+		ApplicationName: "billing",
+		ClusterAccesses: []models.ClusterAccess{
+			{
+				ClusterId: "abc-1234",
+				RoleBindings: []models.RoleBinding{
+					{Id: uuid.NewV4(), RoleBindingDefinition: models.RoleBindingDefinition{RoleName: models.RoleNameDeveloperRead, CrnPattern: "crn://some/topic=some-capability*"}, CreatedAt: &now},
+					{Id: uuid.NewV4(), RoleBindingDefinition: models.RoleBindingDefinition{RoleName: models.RoleNameDeveloperWrite, CrnPattern: "crn://some/topic=some-capability*"}},
+				},
+			},
+		},
+	}}
+	accessService := NewAccessService(new(mocks.MockLogger), repository, new(mocks.MockClient), &vaultStub{})
+
+	roleBindings, err := accessService.ListRoleBindings(context.TODO(), "some-capability")
+
+	assert.NoError(t, err)
+	assert.Len(t, roleBindings, 2)
+	assert.Equal(t, models.AclEntryStatusCreated, roleBindings[0].Status)
+	assert.Equal(t, models.AclEntryStatusPending, roleBindings[1].Status)
+	assert.Equal(t, models.ClusterId("abc-1234"), roleBindings[0].ClusterId)
+	assert.Equal(t, "billing", roleBindings[0].ApplicationName)
+}
+
+func TestDeleteRoleBinding(t *testing.T) {
+	now := time.Now()
+	roleBindingId := uuid.NewV4()
+	repository := &accessRepositoryStub{ServiceAccount: &models.ServiceAccount{
+		Id:           "sa-123",
+		CapabilityId: "some-capability",
+		ClusterAccesses: []models.ClusterAccess{
+			{
+				ClusterId:    "abc-1234",
+				RoleBindings: []models.RoleBinding{{Id: roleBindingId, ConfluentId: "rb-123", CreatedAt: &now}},
+			},
+		},
+	}}
+	mockClient := new(mocks.MockClient)
+	mockClient.On("DeleteRoleBinding", mock.Anything, "rb-123").Return(nil)
+	accessService := NewAccessService(new(mocks.MockLogger), repository, mockClient, &vaultStub{})
+
+	err := accessService.DeleteRoleBinding(context.TODO(), "some-capability", roleBindingId.String())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{roleBindingId}, repository.Deleted)
+	mockClient.AssertExpectations(t)
+}
+
+func TestDeleteRoleBinding_NotFound(t *testing.T) {
+	repository := &accessRepositoryStub{ServiceAccount: &models.ServiceAccount{Id: "sa-123", CapabilityId: "some-capability"}}
+	accessService := NewAccessService(new(mocks.MockLogger), repository, new(mocks.MockClient), &vaultStub{})
+
+	for _, roleBindingId := range []string{"not-a-uuid", uuid.NewV4().String()} {
+		err := accessService.DeleteRoleBinding(context.TODO(), "some-capability", roleBindingId)
+
+		assert.ErrorIs(t, err, ErrRoleBindingNotFound)
+	}
+	assert.Empty(t, repository.Deleted)
+}
+
+type accessRepositoryStub struct {
+	ServiceAccount *models.ServiceAccount
+	Deleted        []uuid.UUID
+}
+
+func (s *accessRepositoryStub) GetApplicationServiceAccount(models.CapabilityId, string) (*models.ServiceAccount, error) {
+	return s.ServiceAccount, nil
+}
+
+func (s *accessRepositoryStub) GetServiceAccounts(models.CapabilityId) ([]*models.ServiceAccount, error) {
+	return []*models.ServiceAccount{s.ServiceAccount}, nil
+}
+
+func (s *accessRepositoryStub) DeleteRoleBinding(roleBinding *models.RoleBinding) error {
+	s.Deleted = append(s.Deleted, roleBinding.Id)
+	return nil
+}
+
+type vaultStub struct {
+	Stored  map[vault.OperationDestination]bool
+	Keys    map[vault.OperationDestination]models.ApiKey
+	Deleted []vault.Input
+}
+
+func (s *vaultStub) StoreApiKey(_ context.Context, input vault.Input) error {
+	if s.Keys == nil {
+		s.Keys = map[vault.OperationDestination]models.ApiKey{}
+	}
+	if _, ok := s.Keys[input.OperationDestination]; ok && !input.StoringInput.Overwrite {
+		return errors.New("parameter already exists")
+	}
+	s.Keys[input.OperationDestination] = input.StoringInput.ApiKey
+	return nil
+}
+
+func (s *vaultStub) GetApiKey(_ context.Context, input vault.Input) (models.ApiKey, error) {
+	apiKey, ok := s.Keys[input.OperationDestination]
+	if !ok {
+		return models.ApiKey{}, vault.ErrApiKeyNotFound
+	}
+	return apiKey, nil
+}
+
+func (s *vaultStub) QueryApiKey(_ context.Context, input vault.Input) (bool, error) {
+	return s.Stored[input.OperationDestination], nil
+}
+
+func (s *vaultStub) DeleteApiKey(_ context.Context, input vault.Input) error {
+	s.Deleted = append(s.Deleted, input)
+	return nil
+}
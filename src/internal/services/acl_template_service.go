@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/dfds/confluent-gateway/logging"
+	uuid "github.com/satori/go.uuid"
+)
+
+var ErrClusterUsesRbac = errors.New("cluster grants access through role bindings")
+
+type AclTemplateServiceInterface interface {
+	GetAclTemplate(ctx context.Context, clusterId models.ClusterId) (*models.AclTemplate, error)
+	UpdateAclTemplate(ctx context.Context, clusterId models.ClusterId, entries []models.AclTemplateEntry) (*models.AclTemplate, error)
+	ReapplyAclTemplate(ctx context.Context, clusterId models.ClusterId) (*models.AclTemplateReapplyResult, error)
+}
+
+type AclTemplateRepository interface {
+	GetCluster(clusterId models.ClusterId) (*models.Cluster, error)
+	GetAclTemplate(clusterId models.ClusterId) (*models.AclTemplate, error)
+	CreateAclTemplate(template *models.AclTemplate) error
+	GetCapabilitiesBehindAclTemplate(clusterId models.ClusterId, version int) ([]models.CapabilityId, error)
+}
+
+type AclConfluent interface {
+	CreateACLEntry(ctx context.Context, clusterId models.ClusterId, userAccountId models.UserAccountId, entry models.AclDefinition) error
+	DeleteACLEntry(ctx context.Context, clusterId models.ClusterId, userAccountId models.UserAccountId, entry models.AclDefinition) error
+}
+
+type AclTemplateService struct {
+	Logger     logging.Logger
+	Repository AclTemplateRepository
+	Database   models.Database
+	Confluent  AclConfluent
+}
+
+func NewAclTemplateService(logger logging.Logger, repository AclTemplateRepository, database models.Database, confluent AclConfluent) *AclTemplateService {
+	return &AclTemplateService{
+		Logger:     logger,
+		Repository: repository,
+		Database:   database,
+		Confluent:  confluent,
+	}
+}
+
+func (s *AclTemplateService) GetAclTemplate(_ context.Context, clusterId models.ClusterId) (*models.AclTemplate, error) {
+	if err := s.ensureClusterExists(clusterId); err != nil {
+		return nil, err
+	}
+
+	return s.Repository.GetAclTemplate(clusterId)
+}
+
+// UpdateAclTemplate adds a new version of the ACL template of a cluster. Existing cluster accesses keep their ACL
+// until the template is reapplied.
+func (s *AclTemplateService) UpdateAclTemplate(_ context.Context, clusterId models.ClusterId, entries []models.AclTemplateEntry) (*models.AclTemplate, error) {
+	if err := s.ensureClusterExists(clusterId); err != nil {
+		return nil, err
+	}
+
+	current, err := s.Repository.GetAclTemplate(clusterId)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := models.NewAclTemplate(clusterId, current.Version+1, entries)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.Repository.CreateAclTemplate(template); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+// ReapplyAclTemplate brings the ACL of every cluster access on the cluster up to the current version of its
+// template, creating the missing entries and deleting the ones no longer in the template. A cluster access that
+// fails is left at its version, so reapplying again picks it up. A cluster in RBAC mode has no template to reapply.
+func (s *AclTemplateService) ReapplyAclTemplate(ctx context.Context, clusterId models.ClusterId) (*models.AclTemplateReapplyResult, error) {
+	cluster, err := s.Repository.GetCluster(clusterId)
+	if err != nil {
+		return nil, err
+	}
+	if cluster == nil {
+		return nil, ErrClusterNotFound
+	}
+	if cluster.UsesRbac() {
+		return nil, ErrClusterUsesRbac
+	}
+
+	template, err := s.Repository.GetAclTemplate(clusterId)
+	if err != nil {
+		return nil, err
+	}
+
+	capabilityIds, err := s.Repository.GetCapabilitiesBehindAclTemplate(clusterId, template.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.AclTemplateReapplyResult{
+		ClusterId: clusterId,
+		Version:   template.Version,
+		Updated:   []models.CapabilityId{},
+		Failed:    map[models.CapabilityId]string{},
+	}
+
+	for _, capabilityId := range capabilityIds {
+		if err := s.reapply(ctx, template, capabilityId); err != nil {
+			s.Logger.Error(err, "Unable to reapply version {Version} of the acl template to capability {CapabilityId} on cluster {ClusterId}", fmt.Sprint(template.Version), string(capabilityId), string(clusterId))
+			result.Failed[capabilityId] = err.Error()
+			continue
+		}
+
+		result.Updated = append(result.Updated, capabilityId)
+	}
+
+	return result, nil
+}
+
+// reapply brings the cluster accesses of all the service accounts of the capability on the cluster up to the
+// template. The changes are stored before they are made in Confluent, and each change is recorded as soon as
+// Confluent has made it, so that a failure part way leaves the database matching Confluent.
+func (s *AclTemplateService) reapply(ctx context.Context, template *models.AclTemplate, capabilityId models.CapabilityId) error {
+	session := s.Database.NewSession(ctx)
+
+	changes, err := s.prepareReapply(session, template, capabilityId)
+	if err != nil {
+		return err
+	}
+
+	for _, change := range changes {
+		if err := s.reapplyClusterAccess(ctx, session, template, change); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type aclTemplateChange struct {
+	clusterAccess *models.ClusterAccess
+	removed       []models.AclEntry
+}
+
+// prepareReapply applies the template to the cluster accesses of the capability and stores the entries it adds,
+// which are yet to be created in Confluent. The removed entries stay until they are deleted from Confluent.
+func (s *AclTemplateService) prepareReapply(session models.Session, template *models.AclTemplate, capabilityId models.CapabilityId) ([]aclTemplateChange, error) {
+	var changes []aclTemplateChange
+
+	err := session.Transaction(func(tx models.Transaction) error {
+		serviceAccounts, err := tx.GetServiceAccounts(capabilityId)
+		if err != nil {
+			return err
+		}
+
+		for _, serviceAccount := range serviceAccounts {
+			clusterAccess, ok := serviceAccount.TryGetClusterAccess(template.ClusterId)
+			if !ok {
+				continue
+			}
+
+			existing := map[uuid.UUID]bool{}
+			for _, entry := range clusterAccess.Acl {
+				existing[entry.Id] = true
+			}
+
+			removed := clusterAccess.ApplyAclTemplate(template, capabilityId)
+
+			for i := range clusterAccess.Acl {
+				entry := &clusterAccess.Acl[i]
+				if existing[entry.Id] {
+					continue
+				}
+
+				if err := tx.CreateAclEntry(entry); err != nil {
+					return err
+				}
+			}
+
+			changes = append(changes, aclTemplateChange{clusterAccess: clusterAccess, removed: removed})
+		}
+
+		if len(changes) == 0 {
+			return fmt.Errorf("no cluster access for capability '%s' found", capabilityId)
+		}
+		return nil
+	})
+
+	return changes, err
+}
+
+func (s *AclTemplateService) reapplyClusterAccess(ctx context.Context, session models.Session, template *models.AclTemplate, change aclTemplateChange) error {
+	clusterAccess := change.clusterAccess
+
+	// create the new entries before deleting the old ones, so that access carried over is never missing
+	for i := range clusterAccess.Acl {
+		entry := &clusterAccess.Acl[i]
+		if entry.IsValid() || entry.IsAwaitingApproval() {
+			continue
+		}
+
+		if err := s.Confluent.CreateACLEntry(ctx, template.ClusterId, clusterAccess.UserAccountId, entry.AclDefinition); err != nil {
+			return fmt.Errorf("unable to create ACL entry with definition %s, error: %w", entry.AclDefinition, err)
+		}
+
+		entry.Created()
+		if err := session.Transaction(func(tx models.Transaction) error { return tx.UpdateAclEntry(entry) }); err != nil {
+			return err
+		}
+	}
+
+	for i := range change.removed {
+		entry := &change.removed[i]
+
+		if entry.IsValid() {
+			if err := s.Confluent.DeleteACLEntry(ctx, template.ClusterId, clusterAccess.UserAccountId, entry.AclDefinition); err != nil {
+				return fmt.Errorf("unable to delete ACL entry with definition %s, error: %w", entry.AclDefinition, err)
+			}
+		}
+
+		if err := session.Transaction(func(tx models.Transaction) error { return tx.DeleteAclEntry(entry) }); err != nil {
+			return err
+		}
+	}
+
+	return session.Transaction(func(tx models.Transaction) error {
+		return tx.UpdateClusterAccess(clusterAccess)
+	})
+}
+
+func (s *AclTemplateService) ensureClusterExists(clusterId models.ClusterId) error {
+	cluster, err := s.Repository.GetCluster(clusterId)
+	if err != nil {
+		return err
+	}
+	if cluster == nil {
+		return ErrClusterNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dfds/confluent-gateway/internal/mocks"
+	"github.com/dfds/confluent-gateway/internal/models"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUpdateAclTemplate_AddsNextVersion(t *testing.T) {
+	repository := &aclTemplateRepositoryStub{Template: models.DefaultAclTemplate("abc-1234")}
+	sut := NewAclTemplateService(new(mocks.MockLogger), repository, nil, nil)
+
+	template, err := sut.UpdateAclTemplate(context.TODO(), "abc-1234", []models.AclTemplateEntry{
+		{ResourceType: models.ResourceTypeTopic, ResourceName: "{capabilityId}", PatternType: models.PatternTypePrefix, OperationType: models.OperationTypeRead, PermissionType: models.PermissionTypeAllow},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, template.Version)
+	assert.Equal(t, template, repository.Created)
+}
+
+func TestReapplyAclTemplate_AddsAndRemovesEntries(t *testing.T) {
+	now := time.Now()
+	template := &models.AclTemplate{ClusterId: "abc-1234", Version: 1, Entries: []models.AclTemplateEntry{
+		{ResourceType: models.ResourceTypeTopic, ResourceName: "{capabilityId}", PatternType: models.PatternTypePrefix, OperationType: models.OperationTypeRead, PermissionType: models.PermissionTypeAllow},
+		{ResourceType: models.ResourceTypeGroup, ResourceName: "{capabilityId}", PatternType: models.PatternTypePrefix, OperationType: models.OperationTypeRead, PermissionType: models.PermissionTypeAllow},
+	}}
+	kept := models.AclEntry{Id: uuid.NewV4(), CreatedAt: &now, AclDefinition: template.Render("some-capability")[0]}
+	obsolete := models.AclEntry{Id: uuid.NewV4(), CreatedAt: &now, AclDefinition: models.AclDefinition{ResourceType: models.ResourceTypeTopic, ResourceName: "pub.", PatternType: models.PatternTypePrefix, OperationType: models.OperationTypeRead, PermissionType: models.PermissionTypeAllow}}
+	tx := &aclTransactionStub{ServiceAccount: &models.ServiceAccount{
+		Id:              "sa-1234",
+		CapabilityId:    "some-capability",
+		ClusterAccesses: []models.ClusterAccess{{ClusterId: "abc-1234", UserAccountId: "User:1234", Acl: []models.AclEntry{kept, obsolete}}},
+	}}
+	confluent := &mocks.MockClient{}
+	confluent.On("CreateACLEntry", mock.Anything, models.ClusterId("abc-1234"), models.UserAccountId("User:1234"), template.Render("some-capability")[1]).Return(nil)
+	confluent.On("DeleteACLEntry", mock.Anything, models.ClusterId("abc-1234"), models.UserAccountId("User:1234"), obsolete.AclDefinition).Return(nil)
+	repository := &aclTemplateRepositoryStub{Template: template, CapabilityIds: []models.CapabilityId{"some-capability"}}
+	sut := NewAclTemplateService(new(mocks.MockLogger), repository, &aclDatabaseStub{tx: tx}, confluent)
+
+	result, err := sut.ReapplyAclTemplate(context.TODO(), "abc-1234")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.CapabilityId{"some-capability"}, result.Updated)
+	assert.Empty(t, result.Failed)
+	assert.Len(t, tx.Created, 1)
+	assert.Equal(t, []uuid.UUID{obsolete.Id}, tx.Deleted)
+	assert.Equal(t, 1, tx.Updated.AclTemplateVersion)
+	assert.Empty(t, tx.Updated.GetAclPendingCreation())
+	confluent.AssertExpectations(t)
+}
+
+func TestReapplyAclTemplate_ReportsFailures(t *testing.T) {
+	template := &models.AclTemplate{ClusterId: "abc-1234", Version: 1, Entries: []models.AclTemplateEntry{
+		{ResourceType: models.ResourceTypeTopic, ResourceName: "{capabilityId}", PatternType: models.PatternTypePrefix, OperationType: models.OperationTypeRead, PermissionType: models.PermissionTypeAllow},
+	}}
+	tx := &aclTransactionStub{ServiceAccount: &models.ServiceAccount{
+		Id:              "sa-1234",
+		ClusterAccesses: []models.ClusterAccess{{ClusterId: "abc-1234", UserAccountId: "User:1234"}},
+	}}
+	confluent := &mocks.MockClient{}
+	confluent.On("CreateACLEntry", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("some-error"))
+	logger := new(mocks.MockLogger)
+	logger.On("Error", mock.Anything, mock.Anything, mock.Anything).Return()
+	repository := &aclTemplateRepositoryStub{Template: template, CapabilityIds: []models.CapabilityId{"some-capability"}}
+	sut := NewAclTemplateService(logger, repository, &aclDatabaseStub{tx: tx}, confluent)
+
+	result, err := sut.ReapplyAclTemplate(context.TODO(), "abc-1234")
+
+	assert.NoError(t, err)
+	assert.Empty(t, result.Updated)
+	assert.Equal(t, map[models.CapabilityId]string{"some-capability": "unable to create ACL entry with definition TOPIC | some-capability | PREFIXED | READ | ALLOW, error: some-error"}, result.Failed)
+	assert.Len(t, tx.Created, 1, "the entry stays pending, so reapplying again creates it")
+	assert.Nil(t, tx.Created[0].CreatedAt)
+	assert.Nil(t, tx.Updated)
+}
+
+func TestReapplyAclTemplate_RejectsRbacCluster(t *testing.T) {
+	repository := &aclTemplateRepositoryStub{AccessMode: models.AccessModeRbac}
+	sut := NewAclTemplateService(new(mocks.MockLogger), repository, &aclDatabaseStub{}, &mocks.MockClient{})
+
+	_, err := sut.ReapplyAclTemplate(context.TODO(), "abc-1234")
+
+	assert.ErrorIs(t, err, ErrClusterUsesRbac)
+}
+
+type aclTemplateRepositoryStub struct {
+	Template      *models.AclTemplate
+	CapabilityIds []models.CapabilityId
+	Created       *models.AclTemplate
+	AccessMode    models.AccessMode
+}
+
+func (s *aclTemplateRepositoryStub) GetCluster(clusterId models.ClusterId) (*models.Cluster, error) {
+	return &models.Cluster{ClusterId: clusterId, AccessMode: s.AccessMode}, nil
+}
+
+func (s *aclTemplateRepositoryStub) GetAclTemplate(models.ClusterId) (*models.AclTemplate, error) {
+	return s.Template, nil
+}
+
+func (s *aclTemplateRepositoryStub) CreateAclTemplate(template *models.AclTemplate) error {
+	s.Created = template
+	return nil
+}
+
+func (s *aclTemplateRepositoryStub) GetCapabilitiesBehindAclTemplate(models.ClusterId, int) ([]models.CapabilityId, error) {
+	return s.CapabilityIds, nil
+}
+
+type aclDatabaseStub struct {
+	tx models.Transaction
+}
+
+func (d *aclDatabaseStub) NewSession(context.Context) models.Session {
+	return d
+}
+
+func (d *aclDatabaseStub) Transaction(f func(models.Transaction) error) error {
+	return f(d.tx)
+}
+
+type aclTransactionStub struct {
+	models.Transaction
+	ServiceAccount *models.ServiceAccount
+	Cluster        *models.Cluster
+	Created        []*models.AclEntry
+	Deleted        []uuid.UUID
+	Updated        *models.ClusterAccess
+}
+
+func (t *aclTransactionStub) GetServiceAccount(models.CapabilityId) (*models.ServiceAccount, error) {
+	return t.ServiceAccount, nil
+}
+
+func (t *aclTransactionStub) GetServiceAccounts(models.CapabilityId) ([]*models.ServiceAccount, error) {
+	return []*models.ServiceAccount{t.ServiceAccount}, nil
+}
+
+func (t *aclTransactionStub) CreateAclEntry(entry *models.AclEntry) error {
+	t.Created = append(t.Created, entry)
+	return nil
+}
+
+func (t *aclTransactionStub) UpdateAclEntry(*models.AclEntry) error {
+	return nil
+}
+
+func (t *aclTransactionStub) DeleteAclEntry(entry *models.AclEntry) error {
+	t.Deleted = append(t.Deleted, entry.Id)
+	return nil
+}
+
+func (t *aclTransactionStub) GetCluster(models.ClusterId) (*models.Cluster, error) {
+	return t.Cluster, nil
+}
+
+func (t *aclTransactionStub) CreateRoleBinding(*models.RoleBinding) error {
+	return nil
+}
+
+func (t *aclTransactionStub) UpdateRoleBinding(*models.RoleBinding) error {
+	return nil
+}
+
+func (t *aclTransactionStub) DeleteRoleBinding(roleBinding *models.RoleBinding) error {
+	roleBinding.Deleted()
+	return nil
+}
+
+func (t *aclTransactionStub) UpdateClusterAccess(clusterAccess *models.ClusterAccess) error {
+	t.Updated = clusterAccess
+	return nil
+}
@@ -0,0 +1,332 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/dfds/confluent-gateway/internal/vault"
+	"github.com/dfds/confluent-gateway/logging"
+)
+
+var ErrClusterNotFound = errors.New("cluster not found")
+var ErrClusterAlreadyExists = errors.New("cluster already exists")
+var ErrClusterInUse = errors.New("cluster has topics or cluster accesses")
+
+type ClusterServiceInterface interface {
+	ListClusters(ctx context.Context) ([]models.ClusterInfo, error)
+	GetCluster(ctx context.Context, clusterId models.ClusterId) (*models.ClusterInfo, error)
+	CreateCluster(ctx context.Context, request models.ClusterRequest) (*models.ClusterInfo, error)
+	UpdateCluster(ctx context.Context, clusterId models.ClusterId, request models.ClusterRequest) (*models.ClusterInfo, error)
+	DeleteCluster(ctx context.Context, clusterId models.ClusterId) error
+	RefreshClusters(ctx context.Context) error
+}
+
+type ClusterRepository interface {
+	GetClusters(ctx context.Context) ([]*models.Cluster, error)
+	GetCluster(clusterId models.ClusterId) (*models.Cluster, error)
+	CreateCluster(cluster *models.Cluster) error
+	UpdateCluster(cluster *models.Cluster) error
+	DeleteCluster(clusterId models.ClusterId) error
+	CountClusterUsages(clusterId models.ClusterId) (int64, error)
+}
+
+// ClusterCache holds the clusters, with their api keys, the confluent client works with.
+type ClusterCache interface {
+	Get(clusterId models.ClusterId) (*models.Cluster, error)
+	Replace(clusters []*models.Cluster)
+}
+
+// ClusterEncryptor encrypts the api keys kept in the cluster table.
+type ClusterEncryptor interface {
+	EncryptCluster(ctx context.Context, cluster *models.Cluster) (bool, error)
+	DecryptCluster(ctx context.Context, cluster *models.Cluster) error
+}
+
+type ClusterService struct {
+	Logger     logging.Logger
+	Repository ClusterRepository
+	Vault      vault.Vault
+	Cache      ClusterCache
+	Encryptor  ClusterEncryptor
+}
+
+func NewClusterService(logger logging.Logger, repository ClusterRepository, vault vault.Vault, cache ClusterCache, encryptor ClusterEncryptor) *ClusterService {
+	return &ClusterService{
+		Logger:     logger,
+		Repository: repository,
+		Vault:      vault,
+		Cache:      cache,
+		Encryptor:  encryptor,
+	}
+}
+
+func (s *ClusterService) ListClusters(ctx context.Context) ([]models.ClusterInfo, error) {
+	clusters, err := s.Repository.GetClusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := []models.ClusterInfo{}
+	for _, cluster := range clusters {
+		infos = append(infos, models.NewClusterInfo(cluster))
+	}
+
+	return infos, nil
+}
+
+func (s *ClusterService) GetCluster(_ context.Context, clusterId models.ClusterId) (*models.ClusterInfo, error) {
+	cluster, err := s.getCluster(clusterId)
+	if err != nil {
+		return nil, err
+	}
+
+	info := models.NewClusterInfo(cluster)
+	return &info, nil
+}
+
+func (s *ClusterService) getCluster(clusterId models.ClusterId) (*models.Cluster, error) {
+	cluster, err := s.Repository.GetCluster(clusterId)
+	if err != nil {
+		return nil, err
+	}
+	if cluster == nil {
+		return nil, ErrClusterNotFound
+	}
+
+	return cluster, nil
+}
+
+// CreateCluster adds a cluster, keeping its api keys in the vault.
+func (s *ClusterService) CreateCluster(ctx context.Context, request models.ClusterRequest) (*models.ClusterInfo, error) {
+	if err := request.Validate(true); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.Repository.GetCluster(request.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrClusterAlreadyExists
+	}
+
+	cluster := &models.Cluster{ClusterId: request.ClusterId}
+	request.Apply(cluster)
+
+	// no cluster row exists, so api keys found in the vault are left behind by an earlier attempt that failed to add
+	// the cluster and can be replaced
+	if err := s.storeApiKeys(ctx, cluster, request, true); err != nil {
+		return nil, err
+	}
+
+	if err := s.Repository.CreateCluster(cluster); err != nil {
+		return nil, err
+	}
+
+	return s.refreshed(ctx, cluster)
+}
+
+// UpdateCluster changes the settings of a cluster. Api keys given in the request replace the ones in the vault,
+// and move the keys of a cluster still keeping them in the cluster table into the vault.
+func (s *ClusterService) UpdateCluster(ctx context.Context, clusterId models.ClusterId, request models.ClusterRequest) (*models.ClusterInfo, error) {
+	if err := request.Validate(false); err != nil {
+		return nil, err
+	}
+
+	cluster, err := s.getCluster(clusterId)
+	if err != nil {
+		return nil, err
+	}
+
+	if cluster.HasStoredApiKeys() {
+		if err := s.Encryptor.DecryptCluster(ctx, cluster); err != nil {
+			return nil, err
+		}
+
+		if request.AdminApiKey == nil {
+			request.AdminApiKey = &models.ApiKeyRequest{Key: cluster.AdminApiKey.Username, Secret: cluster.AdminApiKey.Password}
+		}
+		if request.SchemaRegistryApiKey == nil && cluster.SchemaRegistryApiKey.Password != "" {
+			request.SchemaRegistryApiKey = &models.ApiKeyRequest{Key: cluster.SchemaRegistryApiKey.Username, Secret: cluster.SchemaRegistryApiKey.Password}
+		}
+	}
+
+	request.Apply(cluster)
+
+	if err := s.storeApiKeys(ctx, cluster, request, true); err != nil {
+		return nil, err
+	}
+
+	if err := s.Repository.UpdateCluster(cluster); err != nil {
+		return nil, err
+	}
+
+	return s.refreshed(ctx, cluster)
+}
+
+// DeleteCluster removes a cluster and its api keys, as long as it has no topics or cluster accesses.
+func (s *ClusterService) DeleteCluster(ctx context.Context, clusterId models.ClusterId) error {
+	if _, err := s.getCluster(clusterId); err != nil {
+		return err
+	}
+
+	usages, err := s.Repository.CountClusterUsages(clusterId)
+	if err != nil {
+		return err
+	}
+	if usages > 0 {
+		return ErrClusterInUse
+	}
+
+	if err := s.Repository.DeleteCluster(clusterId); err != nil {
+		return err
+	}
+
+	for _, destination := range []vault.OperationDestination{vault.OperationDestinationClusterAdmin, vault.OperationDestinationSchemaRegistryAdmin} {
+		if err := s.Vault.DeleteApiKey(ctx, vault.Input{OperationDestination: destination, ClusterId: clusterId}); err != nil {
+			return err
+		}
+	}
+
+	return s.RefreshClusters(ctx)
+}
+
+// storeApiKeys puts the api keys of the request in the vault and clears them from the cluster table.
+func (s *ClusterService) storeApiKeys(ctx context.Context, cluster *models.Cluster, request models.ClusterRequest, overwrite bool) error {
+	apiKeys := []struct {
+		destination vault.OperationDestination
+		request     *models.ApiKeyRequest
+		column      *models.ApiKey
+	}{
+		{vault.OperationDestinationClusterAdmin, request.AdminApiKey, &cluster.AdminApiKey},
+		{vault.OperationDestinationSchemaRegistryAdmin, request.SchemaRegistryApiKey, &cluster.SchemaRegistryApiKey},
+	}
+
+	for _, apiKey := range apiKeys {
+		if apiKey.request == nil {
+			continue
+		}
+
+		err := s.Vault.StoreApiKey(ctx, vault.Input{
+			OperationDestination: apiKey.destination,
+			ClusterId:            cluster.ClusterId,
+			StoringInput:         &vault.StoringInput{ApiKey: apiKey.request.ApiKey(), Overwrite: overwrite},
+		})
+		if err != nil {
+			return err
+		}
+
+		*apiKey.column = models.ApiKey{}
+	}
+
+	return nil
+}
+
+func (s *ClusterService) refreshed(ctx context.Context, cluster *models.Cluster) (*models.ClusterInfo, error) {
+	if err := s.RefreshClusters(ctx); err != nil {
+		return nil, err
+	}
+
+	info := models.NewClusterInfo(cluster)
+	return &info, nil
+}
+
+// RefreshClusters loads the clusters into the cache, reading the api keys of the clusters that keep them in the
+// vault. A cluster whose api keys cannot be read is kept as it was cached before.
+func (s *ClusterService) RefreshClusters(ctx context.Context) error {
+	clusters, err := s.Repository.GetClusters(ctx)
+	if err != nil {
+		return err
+	}
+
+	loaded := []*models.Cluster{}
+	for _, cluster := range clusters {
+		if err := s.loadApiKeys(ctx, cluster); err != nil {
+			s.Logger.Error(err, "Unable to read the api keys of cluster {ClusterId}", string(cluster.ClusterId))
+
+			if cached, err := s.Cache.Get(cluster.ClusterId); err == nil {
+				loaded = append(loaded, cached)
+			}
+			continue
+		}
+
+		loaded = append(loaded, cluster)
+	}
+
+	s.Cache.Replace(loaded)
+
+	return nil
+}
+
+// RefreshPeriodically refreshes the clusters every interval until the context is cancelled, so that the clusters
+// changed by other instances of the gateway are picked up.
+func (s *ClusterService) RefreshPeriodically(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := s.RefreshClusters(ctx); err != nil {
+				s.Logger.Error(err, "Unable to refresh clusters")
+			}
+		}
+	}
+}
+
+// ReencryptApiKeys encrypts the api keys kept in the cluster table with new data keys, including the ones stored
+// before encryption was introduced, and returns the clusters that were changed.
+func (s *ClusterService) ReencryptApiKeys(ctx context.Context) ([]models.ClusterId, error) {
+	clusters, err := s.Repository.GetClusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reencrypted := []models.ClusterId{}
+	for _, cluster := range clusters {
+		if err := s.Encryptor.DecryptCluster(ctx, cluster); err != nil {
+			return reencrypted, err
+		}
+
+		changed, err := s.Encryptor.EncryptCluster(ctx, cluster)
+		if err != nil {
+			return reencrypted, err
+		}
+		if !changed {
+			continue
+		}
+
+		if err := s.Repository.UpdateCluster(cluster); err != nil {
+			return reencrypted, err
+		}
+		reencrypted = append(reencrypted, cluster.ClusterId)
+	}
+
+	return reencrypted, nil
+}
+
+// loadApiKeys decrypts the api keys kept in the cluster table, or reads them from the vault.
+func (s *ClusterService) loadApiKeys(ctx context.Context, cluster *models.Cluster) error {
+	if cluster.HasStoredApiKeys() {
+		return s.Encryptor.DecryptCluster(ctx, cluster)
+	}
+
+	adminApiKey, err := s.Vault.GetApiKey(ctx, vault.Input{OperationDestination: vault.OperationDestinationClusterAdmin, ClusterId: cluster.ClusterId})
+	if err != nil {
+		return err
+	}
+
+	schemaRegistryApiKey, err := s.Vault.GetApiKey(ctx, vault.Input{OperationDestination: vault.OperationDestinationSchemaRegistryAdmin, ClusterId: cluster.ClusterId})
+	if err != nil && !errors.Is(err, vault.ErrApiKeyNotFound) {
+		return err
+	}
+
+	cluster.AdminApiKey = adminApiKey
+	cluster.SchemaRegistryApiKey = schemaRegistryApiKey
+
+	return nil
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dfds/confluent-gateway/internal/encryption"
+	"github.com/dfds/confluent-gateway/internal/mocks"
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/dfds/confluent-gateway/internal/storage"
+	"github.com/dfds/confluent-gateway/internal/vault"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateCluster_KeepsApiKeysInVault(t *testing.T) {
+	repository := &clusterRepositoryStub{}
+	vaultStub := &vaultStub{}
+	cache := storage.NewClusterCache(nil)
+	sut := NewClusterService(new(mocks.MockLogger), repository, vaultStub, cache, encryption.NewEncryptor(nil))
+
+	info, err := sut.CreateCluster(context.TODO(), models.ClusterRequest{
+		ClusterId:         "abc-1234",
+		Name:              "prod",
+		AdminApiEndpoint:  "http://localhost:5051",
+		AdminApiKey:       &models.ApiKeyRequest{Key: "admin_user", Secret: "admin_pass"},
+		BootstrapEndpoint: "localhost:9092",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.ClusterId("abc-1234"), info.ClusterId)
+	assert.Equal(t, models.ApiKey{}, repository.Clusters[0].AdminApiKey)
+	assert.Equal(t, models.ApiKey{Username: "admin_user", Password: "admin_pass"}, vaultStub.Keys[vault.OperationDestinationClusterAdmin])
+
+	cached, err := cache.Get("abc-1234")
+	assert.NoError(t, err)
+	assert.Equal(t, models.ApiKey{Username: "admin_user", Password: "admin_pass"}, cached.AdminApiKey)
+}
+
+func TestCreateCluster_ReplacesApiKeysLeftInVault(t *testing.T) {
+	repository := &clusterRepositoryStub{}
+	vaultStub := &vaultStub{Keys: map[vault.OperationDestination]models.ApiKey{
+		vault.OperationDestinationClusterAdmin: {Username: "stale_user", Password: "stale_pass"},
+	}}
+	sut := NewClusterService(new(mocks.MockLogger), repository, vaultStub, storage.NewClusterCache(nil), encryption.NewEncryptor(nil))
+
+	_, err := sut.CreateCluster(context.TODO(), models.ClusterRequest{
+		ClusterId:         "abc-1234",
+		Name:              "prod",
+		AdminApiEndpoint:  "http://localhost:5051",
+		AdminApiKey:       &models.ApiKeyRequest{Key: "admin_user", Secret: "admin_pass"},
+		BootstrapEndpoint: "localhost:9092",
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, repository.Clusters, 1)
+	assert.Equal(t, models.ApiKey{Username: "admin_user", Password: "admin_pass"}, vaultStub.Keys[vault.OperationDestinationClusterAdmin])
+}
+
+func TestCreateCluster_Invalid(t *testing.T) {
+	sut := NewClusterService(new(mocks.MockLogger), &clusterRepositoryStub{}, &vaultStub{}, storage.NewClusterCache(nil), encryption.NewEncryptor(nil))
+
+	_, err := sut.CreateCluster(context.TODO(), models.ClusterRequest{Name: "prod"})
+
+	assert.ErrorIs(t, err, models.ErrInvalidCluster)
+	assert.EqualError(t, err, "invalid cluster: adminApiEndpoint is required, adminApiKey is required, bootstrapEndpoint is required, id is required")
+}
+
+func TestRefreshClusters_KeepsApiKeysStoredInClusterTable(t *testing.T) {
+	repository := &clusterRepositoryStub{Clusters: []*models.Cluster{
+		{ClusterId: "abc-1234", AdminApiKey: models.ApiKey{Username: "admin_user", Password: "admin_pass"}},
+		{ClusterId: "def-5678"},
+	}}
+	vaultStub := &vaultStub{Keys: map[vault.OperationDestination]models.ApiKey{
+		vault.OperationDestinationClusterAdmin: {Username: "vault_user", Password: "vault_pass"},
+	}}
+	cache := storage.NewClusterCache(nil)
+	sut := NewClusterService(new(mocks.MockLogger), repository, vaultStub, cache, encryption.NewEncryptor(nil))
+
+	err := sut.RefreshClusters(context.TODO())
+
+	assert.NoError(t, err)
+	legacy, _ := cache.Get("abc-1234")
+	assert.Equal(t, models.ApiKey{Username: "admin_user", Password: "admin_pass"}, legacy.AdminApiKey)
+	added, _ := cache.Get("def-5678")
+	assert.Equal(t, models.ApiKey{Username: "vault_user", Password: "vault_pass"}, added.AdminApiKey)
+	assert.Equal(t, models.ApiKey{}, added.SchemaRegistryApiKey)
+}
+
+func TestRefreshClusters_DecryptsApiKeysStoredInClusterTable(t *testing.T) {
+	provider, _ := encryption.NewLocalKeyProvider(make([]byte, 32))
+	encryptor := encryption.NewEncryptor(provider)
+	cluster := &models.Cluster{ClusterId: "abc-1234", AdminApiKey: models.ApiKey{Username: "admin_user", Password: "admin_pass"}}
+	_, _ = encryptor.EncryptCluster(context.TODO(), cluster)
+	cache := storage.NewClusterCache(nil)
+	sut := NewClusterService(new(mocks.MockLogger), &clusterRepositoryStub{Clusters: []*models.Cluster{cluster}}, &vaultStub{}, cache, encryptor)
+
+	err := sut.RefreshClusters(context.TODO())
+
+	assert.NoError(t, err)
+	cached, _ := cache.Get("abc-1234")
+	assert.Equal(t, models.ApiKey{Username: "admin_user", Password: "admin_pass"}, cached.AdminApiKey)
+}
+
+func TestReencryptApiKeys(t *testing.T) {
+	provider, _ := encryption.NewLocalKeyProvider(make([]byte, 32))
+	encryptor := encryption.NewEncryptor(provider)
+	repository := &clusterRepositoryStub{Clusters: []*models.Cluster{
+		{ClusterId: "abc-1234", AdminApiKey: models.ApiKey{Username: "admin_user", Password: "admin_pass"}},
+		{ClusterId: "def-5678"},
+	}}
+	sut := NewClusterService(new(mocks.MockLogger), repository, &vaultStub{}, storage.NewClusterCache(nil), encryptor)
+
+	reencrypted, err := sut.ReencryptApiKeys(context.TODO())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.ClusterId{"abc-1234"}, reencrypted)
+	assert.True(t, encryption.IsEncrypted(repository.Clusters[0].AdminApiKey.Password))
+	assert.NoError(t, encryptor.DecryptCluster(context.TODO(), repository.Clusters[0]))
+	assert.Equal(t, models.ApiKey{Username: "admin_user", Password: "admin_pass"}, repository.Clusters[0].AdminApiKey)
+}
+
+func TestDeleteCluster_InUse(t *testing.T) {
+	repository := &clusterRepositoryStub{Clusters: []*models.Cluster{{ClusterId: "abc-1234"}}, Usages: 1}
+	sut := NewClusterService(new(mocks.MockLogger), repository, &vaultStub{}, storage.NewClusterCache(nil), encryption.NewEncryptor(nil))
+
+	err := sut.DeleteCluster(context.TODO(), "abc-1234")
+
+	assert.ErrorIs(t, err, ErrClusterInUse)
+	assert.Len(t, repository.Clusters, 1)
+}
+
+type clusterRepositoryStub struct {
+	Clusters []*models.Cluster
+	Usages   int64
+}
+
+func (s *clusterRepositoryStub) GetClusters(context.Context) ([]*models.Cluster, error) {
+	var clusters []*models.Cluster
+	for _, cluster := range s.Clusters {
+		copied := *cluster
+		clusters = append(clusters, &copied)
+	}
+	return clusters, nil
+}
+
+func (s *clusterRepositoryStub) GetCluster(clusterId models.ClusterId) (*models.Cluster, error) {
+	for _, cluster := range s.Clusters {
+		if cluster.ClusterId == clusterId {
+			copied := *cluster
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *clusterRepositoryStub) CreateCluster(cluster *models.Cluster) error {
+	s.Clusters = append(s.Clusters, cluster)
+	return nil
+}
+
+func (s *clusterRepositoryStub) UpdateCluster(cluster *models.Cluster) error {
+	for i, existing := range s.Clusters {
+		if existing.ClusterId == cluster.ClusterId {
+			s.Clusters[i] = cluster
+		}
+	}
+	return nil
+}
+
+func (s *clusterRepositoryStub) DeleteCluster(clusterId models.ClusterId) error {
+	for i, existing := range s.Clusters {
+		if existing.ClusterId == clusterId {
+			s.Clusters = append(s.Clusters[:i], s.Clusters[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (s *clusterRepositoryStub) CountClusterUsages(models.ClusterId) (int64, error) {
+	return s.Usages, nil
+}
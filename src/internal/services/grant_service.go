@@ -0,0 +1,346 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/dfds/confluent-gateway/internal/grant"
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/dfds/confluent-gateway/logging"
+	"github.com/dfds/confluent-gateway/messaging"
+	uuid "github.com/satori/go.uuid"
+)
+
+var ErrAccessGrantNotFound = errors.New("access grant not found")
+var ErrAccessGrantToOwner = errors.New("the topic belongs to the grantee")
+var ErrNoClusterAccess = errors.New("grantee has no access to the cluster")
+
+type GrantServiceInterface interface {
+	RequestGrant(ctx context.Context, request models.AccessGrantRequest) (*models.AccessGrant, error)
+	GetGrant(ctx context.Context, grantId string) (*models.AccessGrant, error)
+	ApproveGrant(ctx context.Context, grantId string) (*models.AccessGrant, error)
+	RevokeGrant(ctx context.Context, grantId string) error
+	ListGrants(ctx context.Context, capabilityId models.CapabilityId) ([]models.AccessGrant, error)
+}
+
+type GrantRepository interface {
+	GetTopicByName(ctx context.Context, clusterId models.ClusterId, name string) (*models.Topic, error)
+	GetServiceAccountByGrant(grantId uuid.UUID) (*models.ServiceAccount, error)
+	GetServiceAccountsGrantedBy(capabilityId models.CapabilityId) ([]*models.ServiceAccount, error)
+}
+
+type GrantConfluent interface {
+	AclConfluent
+	EnsureRoleBinding(ctx context.Context, serviceAccount models.ServiceAccountId, definition models.RoleBindingDefinition) (string, error)
+	DeleteRoleBinding(ctx context.Context, roleBindingId string) error
+}
+
+type GrantOutbox interface {
+	Produce(msg messaging.OutgoingMessage) error
+}
+
+type GrantOutboxRepository interface {
+	AddToOutbox(entry *messaging.OutboxEntry) error
+}
+
+type GrantOutboxFactory func(repository GrantOutboxRepository) GrantOutbox
+
+// GrantService lets a capability consume a private topic of another capability, by adding ACL entries for the
+// topic and a consumer group to the cluster access of the grantee.
+type GrantService struct {
+	Logger     logging.Logger
+	Repository GrantRepository
+	Database   models.Database
+	Confluent  GrantConfluent
+	Outbox     GrantOutboxFactory
+}
+
+func NewGrantService(logger logging.Logger, repository GrantRepository, database models.Database, confluent GrantConfluent, outbox GrantOutboxFactory) *GrantService {
+	return &GrantService{
+		Logger:     logger,
+		Repository: repository,
+		Database:   database,
+		Confluent:  confluent,
+		Outbox:     outbox,
+	}
+}
+
+// RequestGrant adds the ACL entries of a grant to the cluster access of the grantee. The entries are not created
+// until the owner of the topic approves the grant.
+func (s *GrantService) RequestGrant(ctx context.Context, request models.AccessGrantRequest) (*models.AccessGrant, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	topic, err := s.Repository.GetTopicByName(ctx, request.ClusterId, request.TopicName)
+	if err != nil {
+		return nil, err
+	}
+	if topic.CapabilityId == request.GranteeCapabilityId {
+		return nil, ErrAccessGrantToOwner
+	}
+
+	var accessGrant models.AccessGrant
+
+	err = s.Database.NewSession(ctx).Transaction(func(tx models.Transaction) error {
+		serviceAccount, err := tx.GetServiceAccount(request.GranteeCapabilityId)
+		if err != nil {
+			return err
+		}
+
+		clusterAccess, ok := serviceAccount.TryGetClusterAccess(request.ClusterId)
+		if !ok {
+			return ErrNoClusterAccess
+		}
+		if clusterAccess.HasGrant(request.TopicName) {
+			return models.ErrAccessGrantAlreadyExists
+		}
+
+		entries := models.NewGrantAclEntries(clusterAccess, topic.CapabilityId, request.TopicName, request.ConsumerGroup)
+		for i := range entries {
+			if err := tx.CreateAclEntry(&entries[i]); err != nil {
+				return err
+			}
+		}
+
+		accessGrant = models.NewAccessGrant(serviceAccount.CapabilityId, clusterAccess, entries)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &accessGrant, nil
+}
+
+// GetGrant returns the grant with the id, or ErrAccessGrantNotFound.
+func (s *GrantService) GetGrant(ctx context.Context, grantId string) (*models.AccessGrant, error) {
+	var accessGrant models.AccessGrant
+
+	err := s.withGrant(ctx, grantId, func(_ models.Transaction, serviceAccount *models.ServiceAccount, clusterAccess *models.ClusterAccess, entries []models.AclEntry) error {
+		accessGrant = models.NewAccessGrant(serviceAccount.CapabilityId, clusterAccess, entries)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &accessGrant, nil
+}
+
+// ApproveGrant creates the ACL entries of a grant that was waiting for the owner of the topic, along with the role
+// binding on the subjects of the topic.
+func (s *GrantService) ApproveGrant(ctx context.Context, grantId string) (*models.AccessGrant, error) {
+	var accessGrant models.AccessGrant
+
+	err := s.withGrant(ctx, grantId, func(tx models.Transaction, serviceAccount *models.ServiceAccount, clusterAccess *models.ClusterAccess, entries []models.AclEntry) error {
+		accessGrant = models.NewAccessGrant(serviceAccount.CapabilityId, clusterAccess, entries)
+		if accessGrant.OwnerApproved {
+			return nil
+		}
+
+		for i := range entries {
+			entries[i].OwnerApproved = true
+			if err := tx.UpdateAclEntry(&entries[i]); err != nil {
+				return err
+			}
+		}
+
+		if err := s.createEntries(ctx, tx, clusterAccess, entries); err != nil {
+			return err
+		}
+
+		accessGrant = models.NewAccessGrant(serviceAccount.CapabilityId, clusterAccess, entries)
+
+		if err := s.bindGrantedSubjects(ctx, tx, clusterAccess, accessGrant.TopicName); err != nil {
+			return err
+		}
+
+		return s.produceGranted(tx, accessGrant)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &accessGrant, nil
+}
+
+// RevokeGrant deletes the ACL entries and the subjects role binding of a grant, from Confluent as well as from the
+// cluster access of the grantee.
+func (s *GrantService) RevokeGrant(ctx context.Context, grantId string) error {
+	return s.withGrant(ctx, grantId, func(tx models.Transaction, serviceAccount *models.ServiceAccount, clusterAccess *models.ClusterAccess, entries []models.AclEntry) error {
+		accessGrant := models.NewAccessGrant(serviceAccount.CapabilityId, clusterAccess, entries)
+
+		for i := range entries {
+			entry := &entries[i]
+
+			if entry.IsValid() {
+				if err := s.Confluent.DeleteACLEntry(ctx, clusterAccess.ClusterId, clusterAccess.UserAccountId, entry.AclDefinition); err != nil {
+					return fmt.Errorf("unable to delete ACL entry with definition %s, error: %w", entry.AclDefinition, err)
+				}
+			}
+
+			if err := tx.DeleteAclEntry(entry); err != nil {
+				return err
+			}
+		}
+
+		if err := s.unbindGrantedSubjects(ctx, tx, clusterAccess, accessGrant.TopicName); err != nil {
+			return err
+		}
+
+		return s.Outbox(tx).Produce(&grant.AccessRevoked{
+			GrantId:             accessGrant.Id,
+			KafkaClusterId:      string(accessGrant.ClusterId),
+			TopicName:           accessGrant.TopicName,
+			OwnerCapabilityId:   string(accessGrant.OwnerCapabilityId),
+			GranteeCapabilityId: string(accessGrant.GranteeCapabilityId),
+		})
+	})
+}
+
+// ListGrants returns the grants a capability has been given as well as the grants it has given on its topics.
+func (s *GrantService) ListGrants(_ context.Context, capabilityId models.CapabilityId) ([]models.AccessGrant, error) {
+	serviceAccounts, err := s.Repository.GetServiceAccountsGrantedBy(capabilityId)
+	if err != nil {
+		return nil, err
+	}
+
+	grants := []models.AccessGrant{}
+	for _, serviceAccount := range serviceAccounts {
+		for i := range serviceAccount.ClusterAccesses {
+			clusterAccess := &serviceAccount.ClusterAccesses[i]
+
+			for _, entries := range clusterAccess.GetGrants() {
+				accessGrant := models.NewAccessGrant(serviceAccount.CapabilityId, clusterAccess, entries)
+				if accessGrant.GranteeCapabilityId == capabilityId || accessGrant.OwnerCapabilityId == capabilityId {
+					grants = append(grants, accessGrant)
+				}
+			}
+		}
+	}
+
+	sort.Slice(grants, func(i, j int) bool {
+		return grants[i].Id < grants[j].Id
+	})
+
+	return grants, nil
+}
+
+func (s *GrantService) withGrant(ctx context.Context, grantId string, f func(models.Transaction, *models.ServiceAccount, *models.ClusterAccess, []models.AclEntry) error) error {
+	id, err := uuid.FromString(grantId)
+	if err != nil {
+		return ErrAccessGrantNotFound
+	}
+
+	owner, err := s.Repository.GetServiceAccountByGrant(id)
+	if err != nil {
+		return err
+	}
+	if owner == nil {
+		return ErrAccessGrantNotFound
+	}
+
+	return s.Database.NewSession(ctx).Transaction(func(tx models.Transaction) error {
+		serviceAccount, err := tx.GetServiceAccount(owner.CapabilityId)
+		if err != nil {
+			return err
+		}
+
+		for i := range serviceAccount.ClusterAccesses {
+			clusterAccess := &serviceAccount.ClusterAccesses[i]
+			if entries, ok := clusterAccess.GetGrants()[id]; ok {
+				return f(tx, serviceAccount, clusterAccess, entries)
+			}
+		}
+
+		return ErrAccessGrantNotFound
+	})
+}
+
+func (s *GrantService) createEntries(ctx context.Context, tx models.Transaction, clusterAccess *models.ClusterAccess, entries []models.AclEntry) error {
+	for i := range entries {
+		entry := &entries[i]
+
+		if err := s.Confluent.CreateACLEntry(ctx, clusterAccess.ClusterId, clusterAccess.UserAccountId, entry.AclDefinition); err != nil {
+			return fmt.Errorf("unable to create ACL entry with definition %s, error: %w", entry.AclDefinition, err)
+		}
+
+		entry.Created()
+		if err := tx.UpdateAclEntry(entry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// bindGrantedSubjects lets the grantee read the schemas of the granted topic, which it cannot do through the role
+// binding on all subjects once its schema registry access is scoped to its capability.
+func (s *GrantService) bindGrantedSubjects(ctx context.Context, tx models.Transaction, clusterAccess *models.ClusterAccess, topicName string) error {
+	cluster, err := tx.GetCluster(clusterAccess.ClusterId)
+	if err != nil {
+		return err
+	}
+	if cluster == nil || !cluster.HasSchemaRegistryIds() {
+		return nil
+	}
+
+	roleBinding := clusterAccess.GetGrantedSubjectsRoleBinding(cluster, topicName)
+	if roleBinding == nil {
+		roleBinding = clusterAccess.AddGrantedSubjectsRoleBinding(cluster, topicName)
+		if err := tx.CreateRoleBinding(roleBinding); err != nil {
+			return err
+		}
+	}
+	if roleBinding.IsCreated() {
+		return nil
+	}
+
+	confluentId, err := s.Confluent.EnsureRoleBinding(ctx, clusterAccess.ServiceAccountId, roleBinding.RoleBindingDefinition)
+	if err != nil {
+		return fmt.Errorf("unable to create role binding %s, error: %w", roleBinding.RoleBindingDefinition, err)
+	}
+
+	roleBinding.Created(confluentId)
+	return tx.UpdateRoleBinding(roleBinding)
+}
+
+// unbindGrantedSubjects removes the role binding on the subjects of the topic of a revoked grant.
+func (s *GrantService) unbindGrantedSubjects(ctx context.Context, tx models.Transaction, clusterAccess *models.ClusterAccess, topicName string) error {
+	cluster, err := tx.GetCluster(clusterAccess.ClusterId)
+	if err != nil {
+		return err
+	}
+	if cluster == nil || !cluster.HasSchemaRegistryIds() {
+		return nil
+	}
+
+	roleBinding := clusterAccess.GetGrantedSubjectsRoleBinding(cluster, topicName)
+	if roleBinding == nil {
+		return nil
+	}
+
+	if roleBinding.IsCreated() && roleBinding.ConfluentId != "" {
+		if err := s.Confluent.DeleteRoleBinding(ctx, roleBinding.ConfluentId); err != nil {
+			return fmt.Errorf("unable to delete role binding %s, error: %w", roleBinding.RoleBindingDefinition, err)
+		}
+	}
+
+	return tx.DeleteRoleBinding(roleBinding)
+}
+
+func (s *GrantService) produceGranted(tx models.Transaction, accessGrant models.AccessGrant) error {
+	return s.Outbox(tx).Produce(&grant.AccessGranted{
+		GrantId:             accessGrant.Id,
+		KafkaClusterId:      string(accessGrant.ClusterId),
+		TopicName:           accessGrant.TopicName,
+		ConsumerGroup:       accessGrant.ConsumerGroup,
+		OwnerCapabilityId:   string(accessGrant.OwnerCapabilityId),
+		GranteeCapabilityId: string(accessGrant.GranteeCapabilityId),
+		OwnerApproved:       accessGrant.OwnerApproved,
+	})
+}
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dfds/confluent-gateway/internal/grant"
+	"github.com/dfds/confluent-gateway/internal/mocks"
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/dfds/confluent-gateway/messaging"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRequestGrant_WaitsForApproval(t *testing.T) {
+	tx := &aclTransactionStub{ServiceAccount: granteeServiceAccount()}
+	confluent := &mocks.MockClient{}
+	outbox := &grantOutboxSpy{}
+	sut := NewGrantService(new(mocks.MockLogger), &grantRepositoryStub{}, &aclDatabaseStub{tx: tx}, confluent, outbox.factory)
+
+	accessGrant, err := sut.RequestGrant(context.TODO(), grantRequest())
+
+	assert.NoError(t, err)
+	assert.False(t, accessGrant.OwnerApproved)
+	assert.False(t, accessGrant.Created)
+	assert.Len(t, tx.Created, 3)
+	confluent.AssertNotCalled(t, "CreateACLEntry", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.Empty(t, outbox.Produced)
+}
+
+func TestRequestGrant_Rejects(t *testing.T) {
+	tests := []struct {
+		name           string
+		request        models.AccessGrantRequest
+		serviceAccount *models.ServiceAccount
+		wantErr        error
+	}{
+		{
+			name:           "own topic",
+			request:        models.AccessGrantRequest{ClusterId: "abc-1234", TopicName: "owner-cap.orders", GranteeCapabilityId: "owner-cap", ConsumerGroup: "owner-cap.consumer"},
+			serviceAccount: granteeServiceAccount(),
+			wantErr:        ErrAccessGrantToOwner,
+		},
+		{
+			name:           "no cluster access",
+			request:        grantRequest(),
+			serviceAccount: &models.ServiceAccount{Id: "sa-1234", CapabilityId: "grantee-cap"},
+			wantErr:        ErrNoClusterAccess,
+		},
+		{
+			name:           "existing grant",
+			request:        grantRequest(),
+			serviceAccount: grantedServiceAccount(true),
+			wantErr:        models.ErrAccessGrantAlreadyExists,
+		},
+		{
+			name:    "public topic",
+			request: models.AccessGrantRequest{ClusterId: "abc-1234", TopicName: "pub.owner-cap.orders", GranteeCapabilityId: "grantee-cap", ConsumerGroup: "grantee-cap.consumer"},
+			wantErr: models.ErrInvalidAccessGrant,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := &aclTransactionStub{ServiceAccount: tt.serviceAccount}
+			sut := NewGrantService(new(mocks.MockLogger), &grantRepositoryStub{}, &aclDatabaseStub{tx: tx}, &mocks.MockClient{}, (&grantOutboxSpy{}).factory)
+
+			_, err := sut.RequestGrant(context.TODO(), tt.request)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Empty(t, tx.Created)
+		})
+	}
+}
+
+func TestApproveGrant_CreatesEntries(t *testing.T) {
+	serviceAccount := grantedServiceAccount(false)
+	grantId := *serviceAccount.ClusterAccesses[0].Acl[0].GrantId
+	tx := &aclTransactionStub{ServiceAccount: serviceAccount}
+	confluent := &mocks.MockClient{}
+	confluent.On("CreateACLEntry", mock.Anything, models.ClusterId("abc-1234"), models.UserAccountId("User:1234"), mock.Anything).Return(nil)
+	outbox := &grantOutboxSpy{}
+	sut := NewGrantService(new(mocks.MockLogger), &grantRepositoryStub{ServiceAccount: serviceAccount}, &aclDatabaseStub{tx: tx}, confluent, outbox.factory)
+
+	accessGrant, err := sut.ApproveGrant(context.TODO(), grantId.String())
+
+	assert.NoError(t, err)
+	assert.True(t, accessGrant.OwnerApproved)
+	assert.True(t, accessGrant.Created)
+	confluent.AssertNumberOfCalls(t, "CreateACLEntry", 3)
+	assert.Len(t, outbox.Produced, 1)
+}
+
+func TestApproveGrant_BindsGrantedSubjects(t *testing.T) {
+	serviceAccount := grantedServiceAccount(false)
+	grantId := *serviceAccount.ClusterAccesses[0].Acl[0].GrantId
+	cluster := schemaRegistryCluster()
+	tx := &aclTransactionStub{ServiceAccount: serviceAccount, Cluster: cluster}
+	definition := models.GrantedSubjectsRoleBinding(cluster, "owner-cap.orders")
+	confluent := &mocks.MockClient{}
+	confluent.On("CreateACLEntry", mock.Anything, models.ClusterId("abc-1234"), models.UserAccountId("User:1234"), mock.Anything).Return(nil)
+	confluent.On("EnsureRoleBinding", mock.Anything, models.ServiceAccountId("sa-1234"), definition).Return("rb-1234", nil)
+	sut := NewGrantService(new(mocks.MockLogger), &grantRepositoryStub{ServiceAccount: serviceAccount}, &aclDatabaseStub{tx: tx}, confluent, (&grantOutboxSpy{}).factory)
+
+	_, err := sut.ApproveGrant(context.TODO(), grantId.String())
+
+	assert.NoError(t, err)
+	roleBinding := serviceAccount.ClusterAccesses[0].GetGrantedSubjectsRoleBinding(cluster, "owner-cap.orders")
+	assert.True(t, roleBinding.IsCreated())
+	assert.Equal(t, "rb-1234", roleBinding.ConfluentId)
+}
+
+func TestApproveGrant_NotFound(t *testing.T) {
+	sut := NewGrantService(new(mocks.MockLogger), &grantRepositoryStub{}, &aclDatabaseStub{}, &mocks.MockClient{}, (&grantOutboxSpy{}).factory)
+
+	_, err := sut.ApproveGrant(context.TODO(), uuid.NewV4().String())
+
+	assert.ErrorIs(t, err, ErrAccessGrantNotFound)
+}
+
+func TestRevokeGrant_DeletesEntries(t *testing.T) {
+	serviceAccount := grantedServiceAccount(true)
+	grantId := *serviceAccount.ClusterAccesses[0].Acl[0].GrantId
+	tx := &aclTransactionStub{ServiceAccount: serviceAccount}
+	confluent := &mocks.MockClient{}
+	confluent.On("DeleteACLEntry", mock.Anything, models.ClusterId("abc-1234"), models.UserAccountId("User:1234"), mock.Anything).Return(nil)
+	outbox := &grantOutboxSpy{}
+	sut := NewGrantService(new(mocks.MockLogger), &grantRepositoryStub{ServiceAccount: serviceAccount}, &aclDatabaseStub{tx: tx}, confluent, outbox.factory)
+
+	err := sut.RevokeGrant(context.TODO(), grantId.String())
+
+	assert.NoError(t, err)
+	assert.Len(t, tx.Deleted, 3)
+	confluent.AssertNumberOfCalls(t, "DeleteACLEntry", 3)
+	assert.Equal(t, []messaging.OutgoingMessage{&grant.AccessRevoked{
+		GrantId:             grantId.String(),
+		KafkaClusterId:      "abc-1234",
+		TopicName:           "owner-cap.orders",
+		OwnerCapabilityId:   "owner-cap",
+		GranteeCapabilityId: "grantee-cap",
+	}}, outbox.Produced)
+}
+
+func TestRevokeGrant_DeletesSubjectsRoleBinding(t *testing.T) {
+	serviceAccount := grantedServiceAccount(true)
+	clusterAccess := &serviceAccount.ClusterAccesses[0]
+	grantId := *clusterAccess.Acl[0].GrantId
+	cluster := schemaRegistryCluster()
+	clusterAccess.AddGrantedSubjectsRoleBinding(cluster, "owner-cap.orders").Created("rb-1234")
+	tx := &aclTransactionStub{ServiceAccount: serviceAccount, Cluster: cluster}
+	confluent := &mocks.MockClient{}
+	confluent.On("DeleteACLEntry", mock.Anything, models.ClusterId("abc-1234"), models.UserAccountId("User:1234"), mock.Anything).Return(nil)
+	confluent.On("DeleteRoleBinding", mock.Anything, "rb-1234").Return(nil)
+	sut := NewGrantService(new(mocks.MockLogger), &grantRepositoryStub{ServiceAccount: serviceAccount}, &aclDatabaseStub{tx: tx}, confluent, (&grantOutboxSpy{}).factory)
+
+	err := sut.RevokeGrant(context.TODO(), grantId.String())
+
+	assert.NoError(t, err)
+	confluent.AssertCalled(t, "DeleteRoleBinding", mock.Anything, "rb-1234")
+	assert.Nil(t, clusterAccess.GetGrantedSubjectsRoleBinding(cluster, "owner-cap.orders"))
+}
+
+func TestListGrants_ReturnsGivenAndReceivedGrants(t *testing.T) {
+	serviceAccount := grantedServiceAccount(true)
+	sut := NewGrantService(new(mocks.MockLogger), &grantRepositoryStub{ServiceAccounts: []*models.ServiceAccount{serviceAccount}}, nil, nil, nil)
+
+	given, err := sut.ListGrants(context.TODO(), "owner-cap")
+	assert.NoError(t, err)
+	assert.Len(t, given, 1)
+
+	received, err := sut.ListGrants(context.TODO(), "grantee-cap")
+	assert.NoError(t, err)
+	assert.Equal(t, given, received)
+
+	other, err := sut.ListGrants(context.TODO(), "other-cap")
+	assert.NoError(t, err)
+	assert.Empty(t, other)
+}
+
+func grantRequest() models.AccessGrantRequest {
+	return models.AccessGrantRequest{
+		ClusterId:           "abc-1234",
+		TopicName:           "owner-cap.orders",
+		GranteeCapabilityId: "grantee-cap",
+		ConsumerGroup:       "grantee-cap.orders-consumer",
+	}
+}
+
+func granteeServiceAccount() *models.ServiceAccount {
+	return &models.ServiceAccount{
+		Id:              "sa-1234",
+		CapabilityId:    "grantee-cap",
+		ClusterAccesses: []models.ClusterAccess{{Id: uuid.NewV4(), ServiceAccountId: "sa-1234", ClusterId: "abc-1234", UserAccountId: "User:1234"}},
+	}
+}
+
+func grantedServiceAccount(created bool) *models.ServiceAccount {
+	serviceAccount := granteeServiceAccount()
+	clusterAccess := &serviceAccount.ClusterAccesses[0]
+
+	clusterAccess.Acl = models.NewGrantAclEntries(clusterAccess, "owner-cap", "owner-cap.orders", "grantee-cap.orders-consumer")
+	if created {
+		now := time.Now()
+		for i := range clusterAccess.Acl {
+			clusterAccess.Acl[i].OwnerApproved = true
+			clusterAccess.Acl[i].CreatedAt = &now
+		}
+	}
+
+	return serviceAccount
+}
+
+func schemaRegistryCluster() *models.Cluster {
+	return &models.Cluster{ClusterId: "abc-1234", OrganizationId: "org-1", EnvironmentId: "env-1", SchemaRegistryId: "lsrc-1234"}
+}
+
+type grantRepositoryStub struct {
+	ServiceAccount  *models.ServiceAccount
+	ServiceAccounts []*models.ServiceAccount
+}
+
+func (s *grantRepositoryStub) GetTopicByName(_ context.Context, clusterId models.ClusterId, name string) (*models.Topic, error) {
+	return &models.Topic{CapabilityId: "owner-cap", ClusterId: clusterId, Name: name}, nil
+}
+
+func (s *grantRepositoryStub) GetServiceAccountByGrant(uuid.UUID) (*models.ServiceAccount, error) {
+	return s.ServiceAccount, nil
+}
+
+func (s *grantRepositoryStub) GetServiceAccountsGrantedBy(models.CapabilityId) ([]*models.ServiceAccount, error) {
+	return s.ServiceAccounts, nil
+}
+
+type grantOutboxSpy struct {
+	Produced []messaging.OutgoingMessage
+}
+
+func (o *grantOutboxSpy) factory(GrantOutboxRepository) GrantOutbox {
+	return o
+}
+
+func (o *grantOutboxSpy) Produce(msg messaging.OutgoingMessage) error {
+	o.Produced = append(o.Produced, msg)
+	return nil
+}
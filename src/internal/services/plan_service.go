@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/dfds/confluent-gateway/internal/confluent"
+	"github.com/dfds/confluent-gateway/internal/create"
+	del "github.com/dfds/confluent-gateway/internal/delete"
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/dfds/confluent-gateway/internal/plan"
+	"github.com/dfds/confluent-gateway/internal/schema"
+	"github.com/dfds/confluent-gateway/internal/serviceaccount"
+	"github.com/dfds/confluent-gateway/internal/vault"
+	"github.com/dfds/confluent-gateway/logging"
+	"github.com/dfds/confluent-gateway/messaging"
+)
+
+type PlanServiceInterface interface {
+	EventTypes() []string
+	Plan(ctx context.Context, eventType string, data []byte) (*models.Plan, error)
+}
+
+var ErrUnknownEventType = errors.New("unknown event type")
+var ErrInvalidEvent = errors.New("invalid event")
+
+// planDependencies are the recording stand-ins a process is built from when planning.
+type planDependencies struct {
+	database  models.Database
+	confluent confluent.ConfluentClient
+	vault     vault.Vault
+}
+
+type plannedEvent struct {
+	message reflect.Type
+	handler func(planDependencies) messaging.MessageHandler
+}
+
+// PlanService shows what the gateway would do when handling an event, by running the process of the event against
+// a rolled back database and recording clients for Confluent and the vault.
+type PlanService struct {
+	Logger    logging.Logger
+	Database  plan.Rehearser
+	Confluent confluent.ConfluentClient
+	Vault     vault.Vault
+	events    map[string]plannedEvent
+}
+
+func NewPlanService(logger logging.Logger, database plan.Rehearser, confluentClient confluent.ConfluentClient, vault vault.Vault, outbox messaging.OutboxFactory, namingPolicy create.NamingPolicy) *PlanService {
+	s := &PlanService{
+		Logger:    logger,
+		Database:  database,
+		Confluent: confluentClient,
+		Vault:     vault,
+		events:    map[string]plannedEvent{},
+	}
+
+	s.register("topic-requested", &create.TopicRequested{}, func(d planDependencies) messaging.MessageHandler {
+		return create.NewTopicRequestedHandler(create.NewProcess(logger, d.database, d.confluent, func(repository create.OutboxRepository) create.Outbox { return outbox(repository) }, namingPolicy))
+	})
+	s.register("topic-deleted", &del.TopicDeletionRequested{}, func(d planDependencies) messaging.MessageHandler {
+		return del.NewTopicRequestedHandler(del.NewProcess(logger, d.database, d.confluent, func(repository del.OutboxRepository) del.Outbox { return outbox(repository) }))
+	})
+	s.register("message-contract-requested", &schema.MessageContractRequested{}, func(d planDependencies) messaging.MessageHandler {
+		return schema.NewSchemaAddedHandler(schema.NewProcess(logger, d.database, d.confluent, d.vault, func(repository schema.OutboxRepository) schema.Outbox { return outbox(repository) }))
+	})
+	s.register("message-contract-deleted", &schema.MessageContractDeleted{}, func(d planDependencies) messaging.MessageHandler {
+		return schema.NewMessageContractDeletedHandler(schema.NewDeleteProcess(logger, d.database, d.confluent, func(repository schema.OutboxRepository) schema.Outbox { return outbox(repository) }))
+	})
+	s.register("cluster-access-requested", &serviceaccount.ServiceAccountAccessRequested{}, func(d planDependencies) messaging.MessageHandler {
+		return serviceaccount.NewAccessRequestedHandler(serviceaccount.NewProcess(logger, d.database, d.confluent, d.vault, func(repository serviceaccount.OutboxRepository) serviceaccount.Outbox {
+			return outbox(repository)
+		}))
+	})
+
+	return s
+}
+
+func (s *PlanService) register(eventType string, message any, handler func(planDependencies) messaging.MessageHandler) {
+	s.events[eventType] = plannedEvent{message: reflect.TypeOf(message).Elem(), handler: handler}
+}
+
+// EventTypes lists the events that can be planned.
+func (s *PlanService) EventTypes() []string {
+	eventTypes := make([]string, 0, len(s.events))
+	for eventType := range s.events {
+		eventTypes = append(eventTypes, eventType)
+	}
+	sort.Strings(eventTypes)
+	return eventTypes
+}
+
+// Plan handles an event without making any changes and returns the changes it would have made. A failing process
+// is reported in the plan rather than as an error.
+func (s *PlanService) Plan(ctx context.Context, eventType string, data []byte) (*models.Plan, error) {
+	event, ok := s.events[eventType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEventType, eventType)
+	}
+
+	message := reflect.New(event.message).Interface()
+	if err := json.Unmarshal(data, message); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidEvent, err)
+	}
+
+	recorder := plan.NewRecorder()
+	result := &models.Plan{EventType: eventType}
+
+	err := s.Database.Rehearse(ctx, func(database models.Database) error {
+		handler := event.handler(planDependencies{
+			database:  plan.NewRecordingDatabase(database, recorder),
+			confluent: plan.NewRecordingClient(s.Confluent, recorder),
+			vault:     plan.NewRecordingVault(s.Vault, recorder),
+		})
+
+		if err := handler.Handle(ctx, messaging.NewMessageContext(map[string]string{}, message)); err != nil {
+			s.Logger.Warning("Planned {EventType} would fail: {Error}", eventType, err.Error())
+			result.Error = err.Error()
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Actions = recorder.Actions()
+
+	return result, nil
+}
@@ -2,29 +2,57 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/dfds/confluent-gateway/internal/confluent"
 	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/dfds/confluent-gateway/internal/schema"
 	"github.com/dfds/confluent-gateway/logging"
 )
 
 type SchemaServiceInterface interface {
-	ListSchemas(ctx context.Context, subjectPrefix string, clusterId models.ClusterId) ([]models.Schema, error)
+	ListSchemas(ctx context.Context, subjectPrefix string, clusterId models.ClusterId, offset int, limit int) ([]models.Schema, error)
+	ListSubjects(ctx context.Context, clusterId models.ClusterId, subjectPrefix string) ([]string, error)
+	ListSubjectVersions(ctx context.Context, clusterId models.ClusterId, subject string) ([]int, error)
+	GetSchemaVersion(ctx context.Context, clusterId models.ClusterId, subject string, version string) (*models.Schema, error)
+	GetCompatibilityLevel(ctx context.Context, clusterId models.ClusterId, subject string) (*models.SubjectCompatibility, error)
+	CheckCompatibility(ctx context.Context, request models.SchemaCompatibilityRequest) (*models.CompatibilityResult, error)
+	SetCompatibilityLevel(ctx context.Context, clusterId models.ClusterId, subject string, level models.CompatibilityLevel) (*models.SubjectCompatibility, error)
+	GetCapabilitySchemaSettings(ctx context.Context, capabilityId models.CapabilityId) (*models.CapabilitySchemaSettings, error)
+	SaveCapabilitySchemaSettings(ctx context.Context, capabilityId models.CapabilityId, level models.CompatibilityLevel) (*models.CapabilitySchemaSettings, error)
+	GetSubjectOwner(ctx context.Context, clusterId models.ClusterId, subject string) (models.CapabilityId, error)
+	GetTopicOwner(ctx context.Context, topicId string) (models.CapabilityId, error)
+}
+
+var ErrInvalidCompatibilityLevel = errors.New("invalid compatibility level")
+var ErrSubjectNotRegistered = errors.New("subject was not registered through the gateway")
+
+type SchemaRepository interface {
+	GetTopic(topicId string) (*models.Topic, error)
+	GetCluster(clusterId models.ClusterId) (*models.Cluster, error)
+	SelectSchemaProcessStatesBySubject(clusterId models.ClusterId, subject string) ([]models.SchemaProcess, error)
+	GetCapabilitySchemaSettings(capabilityId models.CapabilityId) (*models.CapabilitySchemaSettings, error)
+	SaveCapabilitySchemaSettings(settings *models.CapabilitySchemaSettings) error
 }
 
 type SchemaService struct {
 	Logger          logging.Logger
 	ConfluentClient confluent.ConfluentClient
+	Repository      SchemaRepository
 }
 
-func NewSchemaService(logger logging.Logger, confluentClient confluent.ConfluentClient) *SchemaService {
+func NewSchemaService(logger logging.Logger, confluentClient confluent.ConfluentClient, repository SchemaRepository) *SchemaService {
 	return &SchemaService{
+		Logger:          logger,
 		ConfluentClient: confluentClient,
+		Repository:      repository,
 	}
 }
 
-func (s *SchemaService) ListSchemas(ctx context.Context, subjectPrefix string, clusterId models.ClusterId) ([]models.Schema, error) {
-	schemas, err := s.ConfluentClient.ListSchemas(ctx, subjectPrefix, clusterId)
+func (s *SchemaService) ListSchemas(ctx context.Context, subjectPrefix string, clusterId models.ClusterId, offset int, limit int) ([]models.Schema, error) {
+	schemas, err := s.ConfluentClient.ListSchemas(ctx, subjectPrefix, clusterId, offset, limit)
 
 	if err != nil {
 		s.Logger.Error(err, "failed to list schemas")
@@ -33,3 +61,171 @@ func (s *SchemaService) ListSchemas(ctx context.Context, subjectPrefix string, c
 
 	return schemas, nil
 }
+
+func (s *SchemaService) ListSubjects(ctx context.Context, clusterId models.ClusterId, subjectPrefix string) ([]string, error) {
+	subjects, err := s.ConfluentClient.ListSubjects(ctx, clusterId, subjectPrefix)
+
+	if err != nil {
+		s.Logger.Error(err, "failed to list subjects")
+		return nil, err
+	}
+
+	return subjects, nil
+}
+
+func (s *SchemaService) ListSubjectVersions(ctx context.Context, clusterId models.ClusterId, subject string) ([]int, error) {
+	versions, err := s.ConfluentClient.ListSubjectVersions(ctx, clusterId, subject)
+
+	if err != nil {
+		s.Logger.Error(err, "failed to list versions of subject {Subject}", subject)
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+func (s *SchemaService) GetSchemaVersion(ctx context.Context, clusterId models.ClusterId, subject string, version string) (*models.Schema, error) {
+	schema, err := s.ConfluentClient.GetSchemaVersion(ctx, clusterId, subject, version)
+
+	if err != nil {
+		s.Logger.Error(err, "failed to get version {Version} of subject {Subject}", version, subject)
+		return nil, err
+	}
+
+	return schema, nil
+}
+
+func (s *SchemaService) GetCompatibilityLevel(ctx context.Context, clusterId models.ClusterId, subject string) (*models.SubjectCompatibility, error) {
+	level, err := s.ConfluentClient.GetCompatibilityLevel(ctx, clusterId, subject)
+
+	if err != nil {
+		s.Logger.Error(err, "failed to get compatibility level of subject {Subject}", subject)
+		return nil, err
+	}
+
+	return &models.SubjectCompatibility{Subject: subject, CompatibilityLevel: level}, nil
+}
+
+// CheckCompatibility tests a schema against the subject it would be registered under, without registering it.
+func (s *SchemaService) CheckCompatibility(ctx context.Context, request models.SchemaCompatibilityRequest) (*models.CompatibilityResult, error) {
+	topic, err := s.Repository.GetTopic(request.TopicId)
+	if err != nil {
+		return nil, err
+	}
+
+	definition := models.NewSchemaDefinition(request.SchemaType, request.Schema, request.References).WithRules(request.Metadata, request.RuleSet)
+	if err := schema.Validate(definition); err != nil {
+		return nil, err
+	}
+
+	subject, err := s.subjectName(topic, request, definition)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.ConfluentClient.CheckSchemaCompatibility(ctx, topic.ClusterId, subject, definition)
+	if err != nil {
+		s.Logger.Error(err, "failed to check compatibility of subject {Subject}", subject)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// subjectName resolves the subject the same way the schema process does: the strategy of the request, then the
+// strategy of the cluster, then the default strategy.
+func (s *SchemaService) subjectName(topic *models.Topic, request models.SchemaCompatibilityRequest, definition models.SchemaDefinition) (string, error) {
+	strategy := request.SubjectNameStrategy
+	if strategy == "" {
+		cluster, err := s.Repository.GetCluster(topic.ClusterId)
+		if err != nil {
+			return "", err
+		}
+		if cluster != nil {
+			strategy = cluster.SubjectNameStrategy
+		}
+	}
+	if strategy == "" {
+		strategy = models.SubjectNameStrategyTopicMessageType
+	}
+
+	if !strategy.IsValid() {
+		return "", fmt.Errorf("%w: unknown subject name strategy %q", schema.ErrInvalidSchema, strategy)
+	}
+
+	subject, err := strategy.SubjectName(topic.Name, request.MessageType, definition)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s requires a record name in the schema", schema.ErrInvalidSchema, strategy)
+	}
+
+	return subject, nil
+}
+
+func (s *SchemaService) SetCompatibilityLevel(ctx context.Context, clusterId models.ClusterId, subject string, level models.CompatibilityLevel) (*models.SubjectCompatibility, error) {
+	if !level.IsValid() {
+		return nil, ErrInvalidCompatibilityLevel
+	}
+
+	if err := s.ConfluentClient.SetCompatibilityLevel(ctx, clusterId, subject, level); err != nil {
+		s.Logger.Error(err, "failed to set compatibility level of subject {Subject}", subject)
+		return nil, err
+	}
+
+	return &models.SubjectCompatibility{Subject: subject, CompatibilityLevel: string(level)}, nil
+}
+
+// GetCapabilitySchemaSettings returns empty settings, meaning the registry default, when none are saved.
+func (s *SchemaService) GetCapabilitySchemaSettings(ctx context.Context, capabilityId models.CapabilityId) (*models.CapabilitySchemaSettings, error) {
+	settings, err := s.Repository.GetCapabilitySchemaSettings(capabilityId)
+	if err != nil {
+		return nil, err
+	}
+
+	if settings == nil {
+		return &models.CapabilitySchemaSettings{CapabilityId: capabilityId}, nil
+	}
+
+	return settings, nil
+}
+
+func (s *SchemaService) SaveCapabilitySchemaSettings(ctx context.Context, capabilityId models.CapabilityId, level models.CompatibilityLevel) (*models.CapabilitySchemaSettings, error) {
+	if level != "" && !level.IsValid() {
+		return nil, ErrInvalidCompatibilityLevel
+	}
+
+	settings := &models.CapabilitySchemaSettings{
+		CapabilityId:       capabilityId,
+		CompatibilityLevel: level,
+		UpdatedAt:          time.Now(),
+	}
+
+	if err := s.Repository.SaveCapabilitySchemaSettings(settings); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// GetSubjectOwner returns the capability owning the topic a subject was registered for, whichever strategy named the
+// subject.
+func (s *SchemaService) GetSubjectOwner(ctx context.Context, clusterId models.ClusterId, subject string) (models.CapabilityId, error) {
+	schemas, err := s.Repository.SelectSchemaProcessStatesBySubject(clusterId, subject)
+	if err != nil {
+		return "", err
+	}
+	if len(schemas) == 0 {
+		return "", ErrSubjectNotRegistered
+	}
+
+	return s.GetTopicOwner(ctx, schemas[0].TopicId)
+}
+
+// GetTopicOwner returns the capability owning the topic with the id.
+func (s *SchemaService) GetTopicOwner(_ context.Context, topicId string) (models.CapabilityId, error) {
+	topic, err := s.Repository.GetTopic(topicId)
+	if err != nil {
+		return "", err
+	}
+
+	return topic.CapabilityId, nil
+}
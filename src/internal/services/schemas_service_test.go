@@ -32,9 +32,9 @@ func TestListSchemas(t *testing.T) {
 	}
 
 	// Test 1: Successful listing of schemas
-	mockClient.On("ListSchemas", mock.Anything, "", mock.Anything).Return(expectedSchemas, nil)
+	mockClient.On("ListSchemas", mock.Anything, "", mock.Anything, 0, 0).Return(expectedSchemas, nil)
 
-	schemas, err := schemaService.ListSchemas(context.TODO(), "", "")
+	schemas, err := schemaService.ListSchemas(context.TODO(), "", "", 0, 0)
 	assert.Equal(t, expectedSchemas, schemas)
 	assert.NoError(t, err)
 
@@ -42,3 +42,89 @@ func TestListSchemas(t *testing.T) {
 	mockClient.AssertExpectations(t)
 	mockLogger.AssertExpectations(t)
 }
+
+func TestCheckCompatibility(t *testing.T) {
+	mockClient := new(mocks.MockClient)
+	schemaService := NewSchemaService(new(mocks.MockLogger), mockClient, &schemaRepositoryStub{
+		Topic: &models.Topic{Id: "topic-id", ClusterId: "abc-1234", Name: "some-capability.topic"},
+	})
+
+	expected := &models.CompatibilityResult{Subject: "some-capability.topic-some-event", IsCompatible: true}
+	mockClient.On("CheckSchemaCompatibility", mock.Anything, models.ClusterId("abc-1234"), "some-capability.topic-some-event", models.SchemaDefinition{SchemaType: models.SchemaTypeJson, Schema: "{}"}).Return(expected, nil)
+
+	result, err := schemaService.CheckCompatibility(context.TODO(), models.SchemaCompatibilityRequest{TopicId: "topic-id", MessageType: "some-event", Schema: "{}"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+	mockClient.AssertExpectations(t)
+}
+
+func TestSaveCapabilitySchemaSettings(t *testing.T) {
+	repository := &schemaRepositoryStub{}
+	schemaService := NewSchemaService(new(mocks.MockLogger), new(mocks.MockClient), repository)
+
+	// Test 1: no saved settings means the registry default
+	settings, err := schemaService.GetCapabilitySchemaSettings(context.TODO(), "some-capability")
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.CompatibilityLevel(""), settings.CompatibilityLevel)
+
+	// Test 2: invalid level is rejected
+	_, err = schemaService.SaveCapabilitySchemaSettings(context.TODO(), "some-capability", "SOMETIMES")
+
+	assert.ErrorIs(t, err, ErrInvalidCompatibilityLevel)
+
+	// Test 3: valid level is saved
+	settings, err = schemaService.SaveCapabilitySchemaSettings(context.TODO(), "some-capability", models.CompatibilityFullTransitive)
+
+	assert.NoError(t, err)
+	assert.Equal(t, settings, repository.Settings)
+}
+
+func TestGetSubjectOwner(t *testing.T) {
+	schemaService := NewSchemaService(new(mocks.MockLogger), new(mocks.MockClient), &schemaRepositoryStub{
+		Topic:   &models.Topic{Id: "topic-id", CapabilityId: "some-capability", ClusterId: "abc-1234", Name: "some-capability.topic"},
+		Schemas: []models.SchemaProcess{{ClusterId: "abc-1234", TopicId: "topic-id", Subject: "com.example.Order"}},
+	})
+
+	owner, err := schemaService.GetSubjectOwner(context.TODO(), "abc-1234", "com.example.Order")
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.CapabilityId("some-capability"), owner)
+}
+
+func TestGetSubjectOwner_NotRegistered(t *testing.T) {
+	schemaService := NewSchemaService(new(mocks.MockLogger), new(mocks.MockClient), &schemaRepositoryStub{})
+
+	_, err := schemaService.GetSubjectOwner(context.TODO(), "abc-1234", "com.example.Order")
+
+	assert.ErrorIs(t, err, ErrSubjectNotRegistered)
+}
+
+type schemaRepositoryStub struct {
+	Topic    *models.Topic
+	Cluster  *models.Cluster
+	Settings *models.CapabilitySchemaSettings
+	Schemas  []models.SchemaProcess
+}
+
+func (s *schemaRepositoryStub) GetTopic(string) (*models.Topic, error) {
+	return s.Topic, nil
+}
+
+func (s *schemaRepositoryStub) GetCluster(models.ClusterId) (*models.Cluster, error) {
+	return s.Cluster, nil
+}
+
+func (s *schemaRepositoryStub) SelectSchemaProcessStatesBySubject(models.ClusterId, string) ([]models.SchemaProcess, error) {
+	return s.Schemas, nil
+}
+
+func (s *schemaRepositoryStub) GetCapabilitySchemaSettings(models.CapabilityId) (*models.CapabilitySchemaSettings, error) {
+	return s.Settings, nil
+}
+
+func (s *schemaRepositoryStub) SaveCapabilitySchemaSettings(settings *models.CapabilitySchemaSettings) error {
+	s.Settings = settings
+	return nil
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+
+	"github.com/dfds/confluent-gateway/internal/confluent"
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/dfds/confluent-gateway/logging"
+)
+
+type TopicServiceInterface interface {
+	ListTopics(ctx context.Context, capabilityId models.CapabilityId, live bool) ([]models.TopicInfo, error)
+	GetTopic(ctx context.Context, clusterId models.ClusterId, name string, live bool) (*models.TopicInfo, error)
+}
+
+type TopicRepository interface {
+	GetTopicsByCapabilityId(ctx context.Context, capabilityId models.CapabilityId) ([]*models.Topic, error)
+	GetTopicByName(ctx context.Context, clusterId models.ClusterId, name string) (*models.Topic, error)
+}
+
+type TopicService struct {
+	Logger          logging.Logger
+	Repository      TopicRepository
+	ConfluentClient confluent.ConfluentClient
+}
+
+func NewTopicService(logger logging.Logger, repository TopicRepository, confluentClient confluent.ConfluentClient) *TopicService {
+	return &TopicService{
+		Logger:          logger,
+		Repository:      repository,
+		ConfluentClient: confluentClient,
+	}
+}
+
+func (s *TopicService) ListTopics(ctx context.Context, capabilityId models.CapabilityId, live bool) ([]models.TopicInfo, error) {
+	topics, err := s.Repository.GetTopicsByCapabilityId(ctx, capabilityId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.TopicInfo, len(topics))
+
+	for i, topic := range topics {
+		info, err := s.toTopicInfo(ctx, topic, live)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = *info
+	}
+
+	return result, nil
+}
+
+func (s *TopicService) GetTopic(ctx context.Context, clusterId models.ClusterId, name string, live bool) (*models.TopicInfo, error) {
+	topic, err := s.Repository.GetTopicByName(ctx, clusterId, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toTopicInfo(ctx, topic, live)
+}
+
+func (s *TopicService) toTopicInfo(ctx context.Context, topic *models.Topic, live bool) (*models.TopicInfo, error) {
+	info := models.NewTopicInfo(topic)
+
+	if !live {
+		return &info, nil
+	}
+
+	kafkaTopic, err := s.ConfluentClient.GetTopic(ctx, topic.ClusterId, topic.Name)
+	if err != nil {
+		s.Logger.Error(err, "failed to get topic {TopicName} from cluster {ClusterId}", topic.Name, string(topic.ClusterId))
+		return nil, err
+	}
+
+	info.Live = kafkaTopic
+
+	return &info, nil
+}
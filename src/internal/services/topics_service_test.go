@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dfds/confluent-gateway/internal/mocks"
+	"github.com/dfds/confluent-gateway/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestListTopics(t *testing.T) {
+	mockClient := new(mocks.MockClient)
+	repository := &topicRepositoryStub{Topics: []*models.Topic{
+		{Id: "1", CapabilityId: "some-capability", ClusterId: "abc-1234", Name: "some-capability.topic", Partitions: 3},
+	}}
+
+	topicService := NewTopicService(new(mocks.MockLogger), repository, mockClient)
+
+	// Test 1: only database state
+	topics, err := topicService.ListTopics(context.TODO(), "some-capability", false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.TopicInfo{
+		{Id: "1", CapabilityId: "some-capability", ClusterId: "abc-1234", Name: "some-capability.topic", Partitions: 3},
+	}, topics)
+
+	// Test 2: merged with live state
+	kafkaTopic := &models.KafkaTopic{Name: "some-capability.topic", PartitionsCount: 6, ReplicationFactor: 3, Configs: map[string]string{"retention.ms": "-1"}}
+	mockClient.On("GetTopic", mock.Anything, models.ClusterId("abc-1234"), "some-capability.topic").Return(kafkaTopic, nil)
+
+	topics, err = topicService.ListTopics(context.TODO(), "some-capability", true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, kafkaTopic, topics[0].Live)
+	mockClient.AssertExpectations(t)
+}
+
+type topicRepositoryStub struct {
+	Topics []*models.Topic
+}
+
+func (s *topicRepositoryStub) GetTopicsByCapabilityId(context.Context, models.CapabilityId) ([]*models.Topic, error) {
+	return s.Topics, nil
+}
+
+func (s *topicRepositoryStub) GetTopicByName(context.Context, models.ClusterId, string) (*models.Topic, error) {
+	return s.Topics[0], nil
+}
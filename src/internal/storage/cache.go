@@ -3,6 +3,7 @@ package storage
 import (
 	"fmt"
 	"github.com/dfds/confluent-gateway/internal/models"
+	"sync"
 )
 
 func NewClusterCache(items []*models.Cluster) *Cache[models.ClusterId, *models.Cluster] {
@@ -12,17 +13,29 @@ func NewClusterCache(items []*models.Cluster) *Cache[models.ClusterId, *models.C
 }
 
 type Cache[K ~string, T any] struct {
+	mu    sync.RWMutex
 	cache map[K]T
+	key   func(T) K
 }
 
 func NewCache[K ~string, T any](items []T, key func(T) K) *Cache[K, T] {
+	c := &Cache[K, T]{key: key}
+	c.Replace(items)
+	return c
+}
+
+// Replace swaps the items in the cache for the given ones.
+func (c *Cache[K, T]) Replace(items []T) {
 	cache := map[K]T{}
 
 	for _, item := range items {
-		cache[key(item)] = item
+		cache[c.key(item)] = item
 	}
 
-	return &Cache[K, T]{cache: cache}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cache = cache
 }
 
 type ErrKeyNotFound string
@@ -32,6 +45,9 @@ func (s ErrKeyNotFound) Error() string {
 }
 
 func (c *Cache[K, T]) Get(key K) (T, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
 	item, ok := c.cache[key]
 	if !ok {
 		var zeroValue T
@@ -47,6 +47,18 @@ func TestNewClusterCache(t *testing.T) {
 	}
 }
 
+func TestCache_Replace(t *testing.T) {
+	sut := NewClusterCache([]*models.Cluster{{ClusterId: "cluster-1"}})
+
+	sut.Replace([]*models.Cluster{{ClusterId: "cluster-2"}})
+
+	_, err := sut.Get("cluster-1")
+	assert.Equal(t, ErrKeyNotFound("cluster-1"), err)
+	cluster, err := sut.Get("cluster-2")
+	assert.NoError(t, err)
+	assert.Equal(t, models.ClusterId("cluster-2"), cluster.ClusterId)
+}
+
 func TestErrKeyNotFound_Error(t *testing.T) {
 	err := ErrKeyNotFound("what")
 
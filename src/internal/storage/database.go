@@ -7,13 +7,17 @@ import (
 	"github.com/dfds/confluent-gateway/internal/models"
 	"github.com/dfds/confluent-gateway/logging"
 	"github.com/dfds/confluent-gateway/messaging"
+	uuid "github.com/satori/go.uuid"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"strings"
+	"time"
 )
 
 var ErrTopicNotFound = errors.New("requested topic not found")
 var ErrServiceAccountNotFound = errors.New("requested service account not found")
+var ErrProcessNotFound = errors.New("requested process not found")
 
 type Database struct {
 	db *gorm.DB
@@ -40,6 +44,24 @@ func (d *Database) Transaction(f func(models.Transaction) error) error {
 	})
 }
 
+var errRehearsalDone = errors.New("rehearsal done")
+
+// Rehearse runs f against a database whose changes are rolled back afterwards.
+func (d *Database) Rehearse(ctx context.Context, f func(models.Database) error) error {
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := f(&Database{tx}); err != nil {
+			return err
+		}
+		return errRehearsalDone
+	})
+
+	if errors.Is(err, errRehearsalDone) {
+		return nil
+	}
+
+	return err
+}
+
 func (d *Database) GetClusters(ctx context.Context) ([]*models.Cluster, error) {
 	var clusters []*models.Cluster
 
@@ -51,11 +73,55 @@ func (d *Database) GetClusters(ctx context.Context) ([]*models.Cluster, error) {
 	return clusters, nil
 }
 
+func (d *Database) CreateCluster(cluster *models.Cluster) error {
+	return d.db.Create(cluster).Error
+}
+
+func (d *Database) UpdateCluster(cluster *models.Cluster) error {
+	return d.db.Save(cluster).Error
+}
+
+func (d *Database) DeleteCluster(clusterId models.ClusterId) error {
+	return d.db.Delete(&models.Cluster{}, "id = ?", clusterId).Error
+}
+
+// CountClusterUsages counts the topics and cluster accesses of a cluster.
+func (d *Database) CountClusterUsages(clusterId models.ClusterId) (int64, error) {
+	var topics, clusterAccesses int64
+
+	if err := d.db.Model(&models.Topic{}).Where("cluster_id = ?", clusterId).Count(&topics).Error; err != nil {
+		return 0, err
+	}
+	if err := d.db.Model(&models.ClusterAccess{}).Where("cluster_id = ?", clusterId).Count(&clusterAccesses).Error; err != nil {
+		return 0, err
+	}
+
+	return topics + clusterAccesses, nil
+}
+
+// GetCluster returns nil when the cluster is unknown.
+func (d *Database) GetCluster(clusterId models.ClusterId) (*models.Cluster, error) {
+	var cluster = &models.Cluster{}
+
+	err := d.db.First(cluster, "id = ?", clusterId).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return cluster, nil
+}
+
 func (d *Database) GetCreateProcessState(capabilityId models.CapabilityId, clusterId models.ClusterId, topicName string) (*models.CreateProcess, error) {
 	var state = models.CreateProcess{}
 
 	err := d.db.
 		Model(&state).
+		Where("failed_at is null").
+		Order("created_at desc").
 		First(&state, "capability_id = ? and cluster_id = ? and topic_name = ?", capabilityId, clusterId, topicName).
 		Error
 
@@ -78,11 +144,18 @@ func (d *Database) UpdateCreateProcessState(state *models.CreateProcess) error {
 	return d.db.Save(state).Error
 }
 
+// LockCreateProcessState reloads the state, keeping others from running the process until the transaction ends.
+func (d *Database) LockCreateProcessState(state *models.CreateProcess) error {
+	return lockProcessState(d.db, state, state.Id)
+}
+
 func (d *Database) GetDeleteProcessState(topicId string) (*models.DeleteProcess, error) {
 	var state = models.DeleteProcess{}
 
 	err := d.db.
 		Model(&state).
+		Where("failed_at is null").
+		Order("created_at desc").
 		First(&state, "topic_id = ?", topicId).
 		Error
 
@@ -101,18 +174,30 @@ func (d *Database) SaveDeleteProcessState(state *models.DeleteProcess) error {
 	return d.db.Create(state).Error
 }
 
+// LockDeleteProcessState reloads the state, keeping others from running the process until the transaction ends.
+func (d *Database) LockDeleteProcessState(state *models.DeleteProcess) error {
+	return lockProcessState(d.db, state, state.Id)
+}
+
 func (d *Database) UpdateDeleteProcessState(state *models.DeleteProcess) error {
 	return d.db.Save(state).Error
 }
 
+// GetServiceAccount returns the default service account of the capability.
 func (d *Database) GetServiceAccount(capabilityId models.CapabilityId) (*models.ServiceAccount, error) {
+	return d.GetApplicationServiceAccount(capabilityId, "")
+}
+
+// GetApplicationServiceAccount returns the service account of an application of the capability.
+func (d *Database) GetApplicationServiceAccount(capabilityId models.CapabilityId, applicationName string) (*models.ServiceAccount, error) {
 	var serviceAccount models.ServiceAccount
 
 	err := d.db.
 		Model(&serviceAccount).
 		Preload("ClusterAccesses").
-		Preload("ClusterAccesses.Acl").
-		First(&serviceAccount, "capability_id = ?", capabilityId).
+		Preload("ClusterAccesses.Acl", "deleted_at IS NULL").
+		Preload("ClusterAccesses.RoleBindings").
+		First(&serviceAccount, "capability_id = ? AND application_name = ?", capabilityId, applicationName).
 		Error
 
 	if err != nil {
@@ -126,6 +211,63 @@ func (d *Database) GetServiceAccount(capabilityId models.CapabilityId) (*models.
 	return &serviceAccount, nil
 }
 
+// GetServiceAccounts returns all the service accounts of the capability, the default one first.
+func (d *Database) GetServiceAccounts(capabilityId models.CapabilityId) ([]*models.ServiceAccount, error) {
+	var serviceAccounts []*models.ServiceAccount
+
+	err := d.db.
+		Preload("ClusterAccesses").
+		Preload("ClusterAccesses.Acl", "deleted_at IS NULL").
+		Preload("ClusterAccesses.RoleBindings").
+		Order("application_name").
+		Find(&serviceAccounts, "capability_id = ?", capabilityId).
+		Error
+
+	return serviceAccounts, err
+}
+
+// GetServiceAccountByGrant returns the service account whose cluster access has the ACL entries of a grant, or nil
+// when there is no such grant.
+func (d *Database) GetServiceAccountByGrant(grantId uuid.UUID) (*models.ServiceAccount, error) {
+	var serviceAccount models.ServiceAccount
+
+	err := d.db.
+		Joins("JOIN cluster_access ON cluster_access.service_account_id = service_account.id").
+		Joins("JOIN acl ON acl.cluster_access_id = cluster_access.id").
+		First(&serviceAccount, "acl.grant_id = ? AND acl.deleted_at IS NULL", grantId).
+		Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &serviceAccount, nil
+}
+
+// GetServiceAccountsGrantedBy returns the service accounts of the capability and of the capabilities it has
+// granted access to its topics.
+func (d *Database) GetServiceAccountsGrantedBy(capabilityId models.CapabilityId) ([]*models.ServiceAccount, error) {
+	var serviceAccounts []*models.ServiceAccount
+
+	err := d.db.
+		Preload("ClusterAccesses").
+		Preload("ClusterAccesses.Acl", "deleted_at IS NULL").
+		Where("capability_id = ?", capabilityId).
+		Or("id IN (?)", d.db.
+			Table("cluster_access").
+			Select("cluster_access.service_account_id").
+			Joins("JOIN acl ON acl.cluster_access_id = cluster_access.id").
+			Where("acl.granted_by = ? AND acl.deleted_at IS NULL", capabilityId)).
+		Find(&serviceAccounts).
+		Error
+
+	return serviceAccounts, err
+}
+
 func (d *Database) CreateServiceAccount(serviceAccount *models.ServiceAccount) error {
 	return d.db.Create(serviceAccount).Error
 }
@@ -134,6 +276,68 @@ func (d *Database) UpdateAclEntry(aclEntry *models.AclEntry) error {
 	return d.db.Save(aclEntry).Error
 }
 
+func (d *Database) CreateAclEntry(aclEntry *models.AclEntry) error {
+	return d.db.Create(aclEntry).Error
+}
+
+// DeleteAclEntry marks the entry as deleted, keeping it as a record of the access the capability had.
+func (d *Database) DeleteAclEntry(aclEntry *models.AclEntry) error {
+	aclEntry.Deleted()
+	return d.db.Model(aclEntry).Update("deleted_at", aclEntry.DeletedAt).Error
+}
+
+func (d *Database) CreateRoleBinding(roleBinding *models.RoleBinding) error {
+	return d.db.Create(roleBinding).Error
+}
+
+func (d *Database) UpdateRoleBinding(roleBinding *models.RoleBinding) error {
+	return d.db.Save(roleBinding).Error
+}
+
+// DeleteRoleBinding marks the role binding as deleted, keeping it as a record of the access the capability had.
+func (d *Database) DeleteRoleBinding(roleBinding *models.RoleBinding) error {
+	roleBinding.Deleted()
+	return d.db.Model(roleBinding).Update("deleted_at", roleBinding.DeletedAt).Error
+}
+
+// GetAclTemplate returns the latest version of the ACL template of a cluster, or the default template when the
+// cluster has none.
+func (d *Database) GetAclTemplate(clusterId models.ClusterId) (*models.AclTemplate, error) {
+	var template models.AclTemplate
+
+	err := d.db.Order("version desc").First(&template, "cluster_id = ?", clusterId).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.DefaultAclTemplate(clusterId), nil
+		}
+
+		return nil, err
+	}
+
+	return &template, nil
+}
+
+func (d *Database) CreateAclTemplate(template *models.AclTemplate) error {
+	return d.db.Create(template).Error
+}
+
+// GetCapabilitiesBehindAclTemplate returns the capabilities whose cluster access has an ACL from an older version
+// of the template than the given one.
+func (d *Database) GetCapabilitiesBehindAclTemplate(clusterId models.ClusterId, version int) ([]models.CapabilityId, error) {
+	var capabilityIds []models.CapabilityId
+
+	err := d.db.
+		Model(&models.ClusterAccess{}).
+		Joins("JOIN service_account ON service_account.id = cluster_access.service_account_id").
+		Where("cluster_access.cluster_id = ? AND cluster_access.acl_template_version < ?", clusterId, version).
+		Distinct().
+		Order("service_account.capability_id").
+		Pluck("service_account.capability_id", &capabilityIds).
+		Error
+
+	return capabilityIds, err
+}
+
 func (d *Database) CreateClusterAccess(clusterAccess *models.ClusterAccess) error {
 	return d.db.Create(clusterAccess).Error
 }
@@ -182,6 +386,35 @@ func (d *Database) GetTopic(topicId string) (*models.Topic, error) {
 	return topic, nil
 }
 
+func (d *Database) GetTopicsByCapabilityId(ctx context.Context, capabilityId models.CapabilityId) ([]*models.Topic, error) {
+	var topics []*models.Topic
+
+	err := d.db.WithContext(ctx).
+		Order("name").
+		Find(&topics, "capability_id = ?", capabilityId).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return topics, nil
+}
+
+func (d *Database) GetTopicByName(ctx context.Context, clusterId models.ClusterId, name string) (*models.Topic, error) {
+	var topic = &models.Topic{}
+
+	err := d.db.WithContext(ctx).First(topic, "cluster_id = ? and name = ?", clusterId, name).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTopicNotFound
+		}
+
+		return nil, err
+	}
+
+	return topic, nil
+}
+
 func (d *Database) DeleteTopic(topicId string) error {
 	return d.db.Delete(&models.Topic{}, "id = ?", topicId).Error
 }
@@ -191,6 +424,8 @@ func (d *Database) GetSchemaProcessState(messageContractId string) (*models.Sche
 
 	err := d.db.
 		Model(schema).
+		Where("failed_at is null").
+		Order("created_at desc").
 		First(schema, "message_contract_id = ?", messageContractId).
 		Error
 
@@ -209,10 +444,103 @@ func (d *Database) SaveSchemaProcessState(schema *models.SchemaProcess) error {
 	return d.db.Create(schema).Error
 }
 
+// LockSchemaProcessState reloads the state, keeping others from running the process until the transaction ends.
+func (d *Database) LockSchemaProcessState(state *models.SchemaProcess) error {
+	return lockProcessState(d.db, state, state.Id)
+}
+
 func (d *Database) UpdateSchemaProcessState(schema *models.SchemaProcess) error {
 	return d.db.Save(schema).Error
 }
 
+// GetCapabilitySchemaSettings returns nil when the capability has no settings of its own.
+func (d *Database) GetCapabilitySchemaSettings(capabilityId models.CapabilityId) (*models.CapabilitySchemaSettings, error) {
+	var settings = &models.CapabilitySchemaSettings{}
+
+	err := d.db.First(settings, "capability_id = ?", capabilityId).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+func (d *Database) SaveCapabilitySchemaSettings(settings *models.CapabilitySchemaSettings) error {
+	return d.db.Save(settings).Error
+}
+
+// stuckProcess selects the processes started before the given time that have neither completed nor failed, and that
+// have not been resumed since resumedBefore.
+// stuckProcess skips the processes that are running a step, so a process is only resumed by one scheduler at a time.
+func stuckProcess(db *gorm.DB, startedBefore time.Time, resumedBefore time.Time) *gorm.DB {
+	return db.
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("completed_at is null and failed_at is null and created_at < ?", startedBefore).
+		Where("(resumed_at is null or resumed_at < ?)", resumedBefore).
+		Order("created_at")
+}
+
+func lockProcessState(db *gorm.DB, state any, id uuid.UUID) error {
+	err := db.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(state, "id = ?", id).
+		Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrProcessNotFound
+	}
+
+	return err
+}
+
+func (d *Database) SelectStuckCreateProcessStates(startedBefore time.Time, resumedBefore time.Time) ([]*models.CreateProcess, error) {
+	var states []*models.CreateProcess
+	err := stuckProcess(d.db, startedBefore, resumedBefore).Find(&states).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return states, nil
+}
+
+func (d *Database) SelectStuckDeleteProcessStates(startedBefore time.Time, resumedBefore time.Time) ([]*models.DeleteProcess, error) {
+	var states []*models.DeleteProcess
+	err := stuckProcess(d.db, startedBefore, resumedBefore).Find(&states).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return states, nil
+}
+
+func (d *Database) SelectStuckSchemaProcessStates(startedBefore time.Time, resumedBefore time.Time) ([]*models.SchemaProcess, error) {
+	var states []*models.SchemaProcess
+	err := stuckProcess(d.db, startedBefore, resumedBefore).Find(&states).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return states, nil
+}
+
+func (d *Database) GetProcessSteps(process string, processId string) ([]*models.ProcessStep, error) {
+	var steps []*models.ProcessStep
+	err := d.db.Where("process = ? and process_id = ?", process, processId).Find(&steps).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return steps, nil
+}
+
+func (d *Database) SaveProcessStep(step *models.ProcessStep) error {
+	return d.db.Save(step).Error
+}
+
 func (d *Database) SelectSchemaProcessStatesByTopicId(s string) ([]models.SchemaProcess, error) {
 
 	var schemas []models.SchemaProcess
@@ -224,6 +552,30 @@ func (d *Database) SelectSchemaProcessStatesByTopicId(s string) ([]models.Schema
 	return schemas, nil
 }
 
+func (d *Database) SelectSchemaProcessStatesBySubject(clusterId models.ClusterId, subject string) ([]models.SchemaProcess, error) {
+	var schemas []models.SchemaProcess
+	err := d.db.Where("cluster_id = ? and subject = ?", clusterId, subject).Find(&schemas).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return schemas, nil
+}
+
+// HasRecordNameSubjects tells whether any schema on the cluster is registered under the name of its record rather than
+// the name of its topic.
+func (d *Database) HasRecordNameSubjects(clusterId models.ClusterId) (bool, error) {
+	var count int64
+	err := d.db.Model(&models.SchemaProcess{}).
+		Where("cluster_id = ? and subject_name_strategy = ? and deleted_at is null", clusterId, models.SubjectNameStrategyRecord).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
 func (d *Database) DeleteSchemaProcessStateById(id string) error {
 	return d.db.Delete(&models.SchemaProcess{}, "id = ?", id).Error
 }
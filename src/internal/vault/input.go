@@ -7,12 +7,24 @@ type OperationDestination string
 const (
 	OperationDestinationCluster        OperationDestination = "cluster"
 	OperationDestinationSchemaRegistry OperationDestination = "schema-registry"
+
+	// OperationDestinationClusterAdmin and OperationDestinationSchemaRegistryAdmin hold the keys the gateway itself
+	// uses to administer a cluster, which belong to no capability.
+	OperationDestinationClusterAdmin        OperationDestination = "cluster-admin"
+	OperationDestinationSchemaRegistryAdmin OperationDestination = "schema-registry-admin"
 )
 
+func (d OperationDestination) isAdmin() bool {
+	return d == OperationDestinationClusterAdmin || d == OperationDestinationSchemaRegistryAdmin
+}
+
 type Input struct {
 	OperationDestination OperationDestination
 	CapabilityId         models.CapabilityId
-	ClusterId            models.ClusterId
+	// ApplicationName keeps the api keys of the service account of an application apart from the ones of the
+	// default service account of the capability.
+	ApplicationName string
+	ClusterId       models.ClusterId
 
 	// storing fields
 	StoringInput *StoringInput
@@ -2,10 +2,16 @@ package vault
 
 import (
 	"context"
+	"errors"
+
+	"github.com/dfds/confluent-gateway/internal/models"
 )
 
+var ErrApiKeyNotFound = errors.New("api key not found")
+
 type Vault interface {
 	StoreApiKey(ctx context.Context, input Input) error
 	QueryApiKey(ctx context.Context, input Input) (bool, error)
+	GetApiKey(ctx context.Context, input Input) (models.ApiKey, error)
 	DeleteApiKey(ctx context.Context, input Input) error
 }
@@ -2,6 +2,7 @@ package vault
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
 	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/dfds/confluent-gateway/internal/models"
 	"github.com/dfds/confluent-gateway/logging"
 )
 
@@ -19,11 +21,20 @@ type vault struct {
 }
 
 func getApiParameter(input Input) string {
+	capabilityPath := fmt.Sprintf("/capabilities/%s/kafka/%s", input.CapabilityId, input.ClusterId)
+	if input.ApplicationName != "" {
+		capabilityPath = fmt.Sprintf("%s/applications/%s", capabilityPath, input.ApplicationName)
+	}
+
 	switch input.OperationDestination {
 	case OperationDestinationCluster:
-		return fmt.Sprintf("/capabilities/%s/kafka/%s/credentials", input.CapabilityId, input.ClusterId)
+		return capabilityPath + "/credentials"
 	case OperationDestinationSchemaRegistry:
-		return fmt.Sprintf("/capabilities/%s/kafka/%s/schemaregistry-credentials", input.CapabilityId, input.ClusterId)
+		return capabilityPath + "/schemaregistry-credentials"
+	case OperationDestinationClusterAdmin:
+		return fmt.Sprintf("/kafka/clusters/%s/admin-credentials", input.ClusterId)
+	case OperationDestinationSchemaRegistryAdmin:
+		return fmt.Sprintf("/kafka/clusters/%s/schemaregistry-admin-credentials", input.ClusterId)
 	default:
 		return ""
 	}
@@ -34,10 +45,12 @@ func validateInput(input Input, isStoring bool) error {
 	switch input.OperationDestination {
 	case OperationDestinationCluster:
 	case OperationDestinationSchemaRegistry:
+	case OperationDestinationClusterAdmin:
+	case OperationDestinationSchemaRegistryAdmin:
 	default:
 		return errors.New(fmt.Sprintf("invalid operationDestination: %s", input.OperationDestination))
 	}
-	if input.CapabilityId == "" {
+	if input.CapabilityId == "" && !input.OperationDestination.isAdmin() {
 		return errors.New("capabilityId is required")
 	}
 	if input.ClusterId == "" {
@@ -74,18 +87,9 @@ func (v *vault) StoreApiKey(ctx context.Context, input Input) error {
 
 	v.logger.Trace("Sending request to AWS Parameter Store")
 	_, err = client.PutParameter(ctx, &ssm.PutParameterInput{
-		Name:  aws.String(parameterName),
-		Value: aws.String(`{ "key": "` + apiKey.Username + `", "secret": "` + apiKey.Password + `" }`),
-		Tags: []types.Tag{
-			{
-				Key:   aws.String("capabilityId"),
-				Value: aws.String(string(input.CapabilityId)),
-			},
-			{
-				Key:   aws.String("createdBy"),
-				Value: aws.String("Kafka-Janitor"),
-			},
-		},
+		Name:      aws.String(parameterName),
+		Value:     aws.String(`{ "key": "` + apiKey.Username + `", "secret": "` + apiKey.Password + `" }`),
+		Tags:      getTags(input),
 		Tier:      types.ParameterTierStandard,
 		Type:      types.ParameterTypeSecureString,
 		Overwrite: &input.StoringInput.Overwrite,
@@ -100,6 +104,63 @@ func (v *vault) StoreApiKey(ctx context.Context, input Input) error {
 	return nil
 }
 
+func getTags(input Input) []types.Tag {
+	owner := types.Tag{Key: aws.String("capabilityId"), Value: aws.String(string(input.CapabilityId))}
+	if input.OperationDestination.isAdmin() {
+		owner = types.Tag{Key: aws.String("clusterId"), Value: aws.String(string(input.ClusterId))}
+	}
+
+	tags := []types.Tag{
+		owner,
+		{
+			Key:   aws.String("createdBy"),
+			Value: aws.String("Kafka-Janitor"),
+		},
+	}
+	if input.ApplicationName != "" {
+		tags = append(tags, types.Tag{Key: aws.String("applicationName"), Value: aws.String(input.ApplicationName)})
+	}
+
+	return tags
+}
+
+// GetApiKey reads an api key back from the vault, and returns ErrApiKeyNotFound when there is none.
+func (v *vault) GetApiKey(ctx context.Context, input Input) (models.ApiKey, error) {
+	err := validateInput(input, false)
+	if err != nil {
+		return models.ApiKey{}, err
+	}
+
+	parameterName := getApiParameter(input)
+	v.logger.Trace("Reading API key for cluster {ClusterId} at location {ParameterName}", string(input.ClusterId), parameterName)
+
+	client := ssm.NewFromConfig(v.config)
+
+	v.logger.Trace("Sending request to AWS Parameter Store")
+
+	output, err := client.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(parameterName),
+		WithDecryption: aws.Bool(true),
+	})
+	if err != nil {
+		var pnf *types.ParameterNotFound
+		if errors.As(err, &pnf) {
+			return models.ApiKey{}, ErrApiKeyNotFound
+		}
+		return models.ApiKey{}, err
+	}
+
+	var value struct {
+		Key    string `json:"key"`
+		Secret string `json:"secret"`
+	}
+	if output.Parameter == nil || json.Unmarshal([]byte(aws.ToString(output.Parameter.Value)), &value) != nil {
+		return models.ApiKey{}, fmt.Errorf("unable to read api key at location %s", parameterName)
+	}
+
+	return models.ApiKey{Username: value.Key, Password: value.Secret}, nil
+}
+
 func (v *vault) QueryApiKey(ctx context.Context, input Input) (bool, error) {
 	err := validateInput(input, false)
 	if err != nil {
@@ -109,3 +109,53 @@ func TestVault_StoreApiKey_ReturnsErrorWhenServerDoes(t *testing.T) {
 	// assert
 	assert.NotNil(t, err)
 }
+
+func TestVault_GetApiKey_ReadsClusterAdminApiKey(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Second*5)
+	defer cancel()
+
+	sentRequest := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		sentRequest = string(body)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"Parameter": {"Name": "/kafka/clusters/bar/admin-credentials", "Value": "{ \"key\": \"baz\", \"secret\": \"qux\" }"}}`))
+	}))
+
+	defer server.Close()
+
+	config, _ := NewTestConfig(server.URL)
+	sut := vault{
+		logger: logging.NilLogger(),
+		config: *config,
+	}
+	input := Input{
+		OperationDestination: OperationDestinationClusterAdmin,
+		ClusterId:            models.ClusterId("bar"),
+	}
+
+	// act
+	apiKey, err := sut.GetApiKey(ctx, input)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, models.ApiKey{Username: "baz", Password: "qux"}, apiKey)
+	assert.JSONEq(t, `{"Name": "/kafka/clusters/bar/admin-credentials", "WithDecryption": true}`, sentRequest)
+}
+
+func TestGetApiParameter_KeepsApplicationsApart(t *testing.T) {
+	tests := []struct {
+		name  string
+		input Input
+		want  string
+	}{
+		{"default service account", Input{OperationDestination: OperationDestinationCluster, CapabilityId: "foo", ClusterId: "bar"}, "/capabilities/foo/kafka/bar/credentials"},
+		{"application cluster key", Input{OperationDestination: OperationDestinationCluster, CapabilityId: "foo", ApplicationName: "app", ClusterId: "bar"}, "/capabilities/foo/kafka/bar/applications/app/credentials"},
+		{"application schema registry key", Input{OperationDestination: OperationDestinationSchemaRegistry, CapabilityId: "foo", ApplicationName: "app", ClusterId: "bar"}, "/capabilities/foo/kafka/bar/applications/app/schemaregistry-credentials"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, getApiParameter(tt.input))
+		})
+	}
+}
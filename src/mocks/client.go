@@ -14,6 +14,7 @@ type MockClient struct {
 	GotName                     string
 	GotPartitions               int
 	GotRetention                int64
+	GotSubjects                 []string
 	OnCreateServiceAccountError error
 	OnCreateAclEntryError       error
 	OnCreateApiKeyError         error
@@ -59,5 +60,6 @@ func (m *MockClient) DeleteTopic(ctx context.Context, clusterId models.ClusterId
 
 func (m *MockClient) DeleteSchema(ctx context.Context, clusterId models.ClusterId, subject string, schema string, version string) error {
 	fmt.Printf("Deleting Schema %s.%s/%s@%s\n", clusterId, subject, schema, version)
+	m.GotSubjects = append(m.GotSubjects, subject)
 	return m.OnDeleteSchemaError
 }
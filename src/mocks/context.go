@@ -3,17 +3,22 @@ package mocks
 import "github.com/dfds/confluent-gateway/internal/models"
 
 type StepContextMock struct {
-	ReturnClusterAccess *models.ClusterAccess
+	ReturnClusterAccess       *models.ClusterAccess
+	ReturnCompatibilityResult *models.CompatibilityResult
 
 	MarkServiceAccountAsReadyWasCalled bool
 	MarkClusterAccessAsReadyWasCalled  bool
 	MarkApiKeyAsReadyWasCalled         bool
 	MarkApiKeyInVaultAsReadyWasCalled  bool
 	MarkAsCompletedWasCalled           bool
+	MarkAsFailedWasCalled              bool
 	TopicProvisionedEventWasRaised     bool
+	TopicProvisioningFailedWasRaised   bool
 	TopicDeletedEventWasRaised         bool
+	TopicDeletionFailedWasRaised       bool
 	SchemaRegisteredEventWasRaised     bool
 	SchemaRegistrationFailedWasRaised  bool
+	SchemaIncompatibleWasRaised        bool
 
 	OnCreateServiceAccountError     error
 	OnGetOrCreateClusterAccessError error
@@ -24,6 +29,9 @@ type StepContextMock struct {
 	OnCreateTopicError              error
 	OnDeleteTopicError              error
 	OnRegisterSchemaError           error
+	OnCheckSchemaCompatibilityError error
+	OnValidateSchemaError           error
+	OnEnsureCompatibilityLevelError error
 }
 
 func (m *StepContextMock) HasServiceAccount() bool {
@@ -99,6 +107,15 @@ func (m *StepContextMock) RaiseTopicProvisionedEvent() error {
 	return nil
 }
 
+func (m *StepContextMock) MarkAsFailed() {
+	m.MarkAsFailedWasCalled = true
+}
+
+func (m *StepContextMock) RaiseTopicProvisioningFailedEvent(models.FailureCode, string) error {
+	m.TopicProvisioningFailedWasRaised = true
+	return nil
+}
+
 func (m *StepContextMock) DeleteTopic() error {
 	return m.OnDeleteTopicError
 }
@@ -108,6 +125,26 @@ func (m *StepContextMock) RaiseTopicDeletedEvent() error {
 	return nil
 }
 
+func (m *StepContextMock) RaiseTopicDeletionFailedEvent(models.FailureCode, string) error {
+	m.TopicDeletionFailedWasRaised = true
+	return nil
+}
+
+func (m *StepContextMock) ValidateSchema() error {
+	return m.OnValidateSchemaError
+}
+
+func (m *StepContextMock) EnsureCompatibilityLevel() error {
+	return m.OnEnsureCompatibilityLevelError
+}
+
+func (m *StepContextMock) CheckSchemaCompatibility() (*models.CompatibilityResult, error) {
+	if m.ReturnCompatibilityResult == nil {
+		return &models.CompatibilityResult{IsCompatible: true}, m.OnCheckSchemaCompatibilityError
+	}
+	return m.ReturnCompatibilityResult, m.OnCheckSchemaCompatibilityError
+}
+
 func (m *StepContextMock) RegisterSchema() error {
 	return m.OnRegisterSchemaError
 }
@@ -121,3 +158,8 @@ func (m *StepContextMock) RaiseSchemaRegistrationFailed(s string) error {
 	m.SchemaRegistrationFailedWasRaised = true
 	return nil
 }
+
+func (m *StepContextMock) RaiseSchemaIncompatible(*models.CompatibilityResult) error {
+	m.SchemaIncompatibleWasRaised = true
+	return nil
+}